@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"net/textproto"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/lroman242/redirector/config"
 	"github.com/lroman242/redirector/domain/dto"
@@ -34,7 +36,13 @@ Examples:
   redirector redirect test-slug --p1=value1 --p2=value2
 
   # Test with custom parameters
-  redirector redirect test-slug --param key1=value1 --param key2=value2`,
+  redirector redirect test-slug --param key1=value1 --param key2=value2
+
+  # Test with headers and cookies used by {header:...} and {cookie:...} tokens
+  redirector redirect test-slug --header "X-Device-Id: abc" --cookie vid=123
+
+  # Test tracking link schedule at specific moment
+  redirector redirect test-slug --at=2025-03-01T18:30:00+01:00`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		// Initialize service
@@ -55,6 +63,8 @@ Examples:
 		protocol, _ := cmd.Flags().GetString("protocol")
 		urlStr, _ := cmd.Flags().GetString("url")
 		referrer, _ := cmd.Flags().GetString("referrer")
+		visitorID, _ := cmd.Flags().GetString("visitor-id")
+		atStr, _ := cmd.Flags().GetString("at")
 
 		// Initialize params map
 		params := make(map[string][]string)
@@ -80,6 +90,27 @@ Examples:
 			}
 		}
 
+		// Get headers
+		headers := make(map[string][]string)
+		customHeaders, _ := cmd.Flags().GetStringArray("header")
+		for _, header := range customHeaders {
+			name, value, found := strings.Cut(header, ":")
+			if found {
+				key := textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(name))
+				headers[key] = append(headers[key], strings.TrimSpace(value))
+			}
+		}
+
+		// Get cookies
+		cookies := make(map[string]string)
+		customCookies, _ := cmd.Flags().GetStringArray("cookie")
+		for _, cookie := range customCookies {
+			name, value, found := strings.Cut(cookie, "=")
+			if found {
+				cookies[name] = value
+			}
+		}
+
 		// Parse URL if provided
 		var incomeURL *url.URL
 		if urlStr != "" {
@@ -91,17 +122,31 @@ Examples:
 			}
 		}
 
+		// Parse request time if provided
+		var requestTime time.Time
+		if atStr != "" {
+			var err error
+			requestTime, err = time.Parse(time.RFC3339, atStr)
+			if err != nil {
+				slog.Error("Invalid request time", "error", err)
+				return
+			}
+		}
+
 		// Create request data
 		requestData := &dto.RedirectRequestData{
 			RequestID: requestID,
 			Slug:      slug,
 			Params:    params,
-			Headers:   make(map[string][]string),
+			Headers:   headers,
+			Cookies:   cookies,
 			UserAgent: userAgent,
 			IP:        net.ParseIP(ipAddress),
 			Protocol:  protocol,
 			URL:       incomeURL,
 			Referer:   referrer,
+			VisitorID: visitorID,
+			Time:      requestTime,
 		}
 
 		// Validate request data
@@ -149,6 +194,8 @@ func init() {
 	redirectCmd.Flags().String("protocol", "https", "Protocol (http/https)")
 	redirectCmd.Flags().String("url", "", "Full URL of the request")
 	redirectCmd.Flags().String("referrer", "", "Referrer URL")
+	redirectCmd.Flags().String("visitor-id", "", "Visitor ID (value of the visitor cookie)")
+	redirectCmd.Flags().String("at", "", "Request time in RFC3339 format (optional, current time is used if not provided)")
 
 	// Add p1-p4 parameter flags
 	redirectCmd.Flags().String("p1", "", "Value for p1 parameter")
@@ -158,4 +205,8 @@ func init() {
 
 	// Add custom parameter flag
 	redirectCmd.Flags().StringArray("param", []string{}, "Custom parameters in key=value format (can be used multiple times)")
+
+	// Add custom headers and cookies flags
+	redirectCmd.Flags().StringArray("header", []string{}, "Request headers in \"Name: value\" format (can be used multiple times)")
+	redirectCmd.Flags().StringArray("cookie", []string{}, "Request cookies in name=value format (can be used multiple times)")
 }
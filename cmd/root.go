@@ -80,6 +80,11 @@ func init() {
 	rootCmd.PersistentFlags().String("http_server_port", "8080", "http server post")
 	rootCmd.PersistentFlags().Bool("http_server_ssl", false, "is ssl enabled")
 	rootCmd.PersistentFlags().String("http_server_cert", "path/cert.pem", "path to ssl certs")
+	rootCmd.PersistentFlags().Bool(
+		"http_server_trust_forwarded_proto",
+		false,
+		"read request protocol from X-Forwarded-Proto header set by a trusted proxy",
+	)
 
 	// Redis configuration flags
 	rootCmd.PersistentFlags().String("redis_host", "localhost", "Redis server hostname")
@@ -99,8 +104,106 @@ func init() {
 		"Time client waits for connection if all connections are busy in seconds",
 	)
 
+	// ClickHouse configuration flags
+	rootCmd.PersistentFlags().String(
+		"clickhouse_host",
+		"",
+		"ClickHouse server hostname, clicks and conversions are not stored if empty",
+	)
+	rootCmd.PersistentFlags().String("clickhouse_port", "9000", "ClickHouse native protocol port")
+	rootCmd.PersistentFlags().String("clickhouse_db", "default", "ClickHouse database name")
+	rootCmd.PersistentFlags().String("clickhouse_user", "default", "ClickHouse user")
+	rootCmd.PersistentFlags().String("clickhouse_password", "", "ClickHouse user password")
+
+	// Redirect configuration flags
+	rootCmd.PersistentFlags().Int("redirect_max_depth", 5, "Maximum number of slug hops allowed for a single request")
+	rootCmd.PersistentFlags().String(
+		"redirect_fallback_url",
+		"",
+		"URL used when a redirect chain contains a loop or exceeds the maximum depth",
+	)
+	rootCmd.PersistentFlags().String(
+		"redirect_not_found_fallback_url",
+		"",
+		"URL used instead of 404 error when the tracking link is not found",
+	)
+	rootCmd.PersistentFlags().String(
+		"redirect_disabled_fallback_url",
+		"",
+		"URL used instead of 410 error when the tracking link is disabled",
+	)
+	rootCmd.PersistentFlags().String(
+		"redirect_blocked_fallback_url",
+		"",
+		"URL used instead of 403 error when traffic is rejected by tracking link restrictions",
+	)
+	rootCmd.PersistentFlags().String(
+		"redirect_geo_fallback_url",
+		"",
+		"URL used instead of 403 error when traffic is rejected by geo restrictions",
+	)
+	rootCmd.PersistentFlags().String(
+		"redirect_misconfigured_fallback_url",
+		"",
+		"URL used instead of 500 error when the tracking link redirect rules or redirect chain are invalid",
+	)
+	rootCmd.PersistentFlags().Bool(
+		"redirect_click_caps_enabled",
+		false,
+		"Enforce tracking link and campaign click caps with Redis counters",
+	)
+
+	// Unique clicks configuration flags
+	rootCmd.PersistentFlags().String(
+		"unique_clicks_detector",
+		"",
+		"Storage of seen clicks used to detect duplicates (redis/memory), detection is disabled if empty",
+	)
+	rootCmd.PersistentFlags().String("unique_clicks_key", "ip_ua", "Visitor identification for duplicate clicks (ip_ua/visitor)")
+	rootCmd.PersistentFlags().Int("unique_clicks_window", 86400, "Duplicate clicks detection window in seconds")
+	rootCmd.PersistentFlags().Uint("unique_clicks_bloom_capacity", 1000000, "Expected number of clicks per window (memory detector)")
+	rootCmd.PersistentFlags().Float64("unique_clicks_bloom_fp_rate", 0.001, "Bloom filter false positive rate (memory detector)")
+
+	// IP lists configuration flags
+	rootCmd.PersistentFlags().String(
+		"ip_lists_file",
+		"",
+		"path to the file with global IP allow/deny lists, one \"[allow|deny] <cidr>\" entry per line (optional)",
+	)
+	rootCmd.PersistentFlags().Int("ip_lists_reload_interval", 60, "Global IP lists reload interval in seconds")
+
+	// Referrer cloaking configuration flags
+	rootCmd.PersistentFlags().String(
+		"cloaking_secret",
+		"",
+		"Key used to sign referrer cloaking hop URLs, should be shared by all instances (random if empty)",
+	)
+	rootCmd.PersistentFlags().Int("cloaking_hop_ttl", 60, "Referrer cloaking hop URL lifetime in seconds")
+
+	// Interstitial pages configuration flags
+	rootCmd.PersistentFlags().String(
+		"interstitial_templates_dir",
+		"",
+		"Directory with interstitial page templates (<name>.html), interstitial pages are disabled if empty",
+	)
+	rootCmd.PersistentFlags().Int("interstitial_reload_interval", 60, "Interstitial templates reload interval in seconds")
+
+	// Affiliate postbacks configuration flags
+	rootCmd.PersistentFlags().Int(
+		"postback_dispatch_interval",
+		5,
+		"Affiliate postbacks queue check interval in seconds, postbacks are not sent if not positive",
+	)
+	rootCmd.PersistentFlags().Int("postback_batch_size", 100, "Number of affiliate postbacks sent per queue check")
+	rootCmd.PersistentFlags().Int("postback_max_attempts", 10, "Number of affiliate postback delivery attempts")
+	rootCmd.PersistentFlags().Int("postback_retry_backoff", 30, "Delay before the first affiliate postback retry in seconds")
+	rootCmd.PersistentFlags().Int("postback_max_retry_backoff", 3600, "Maximum delay between affiliate postback retries in seconds")
+	rootCmd.PersistentFlags().Int("postback_timeout", 10, "Affiliate postback request timeout in seconds")
+
 	// GeoIP2 configuration flags
 	rootCmd.PersistentFlags().String("geoip2_db_path", "GeoIP2-City.mmdb", "path to GeoIP2 DB file")
+	rootCmd.PersistentFlags().String("geoip2_asn_db_path", "", "path to GeoLite2-ASN or GeoIP2-ISP DB file (optional)")
+	rootCmd.PersistentFlags().String("geoip2_connection_type_db_path", "", "path to GeoIP2-Connection-Type DB file (optional)")
 
 	err := viper.BindPFlags(rootCmd.PersistentFlags())
 	if err != nil {
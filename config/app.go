@@ -4,6 +4,7 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"sync"
 
 	"github.com/fsnotify/fsnotify"
@@ -24,9 +25,27 @@ type AppConfig struct {
 	LogConf *LoggerConf
 	// RedisConf contains Redis connection settings
 	RedisConf *RedisConf
+	// ClickHouseConf contains ClickHouse connection settings
+	ClickHouseConf *ClickHouseConf
+	// RedirectConf contains redirect processing settings
+	RedirectConf *RedirectConf
+	// UniqueClicksConf contains duplicate clicks detection settings
+	UniqueClicksConf *UniqueClicksConf
+	// IPListsConf contains global IP allow/deny lists settings
+	IPListsConf *IPListsConf
+	// CloakingConf contains referrer cloaking settings
+	CloakingConf *CloakingConf
+	// InterstitialConf contains interstitial page templates settings
+	InterstitialConf *InterstitialConf
+	// PostbackConf contains affiliate postbacks settings
+	PostbackConf *PostbackConf
 
 	// GeoIP2DBPath is the path to the GeoIP2 database file
 	GeoIP2DBPath string `mapstructure:"geoip2_db_path"`
+	// GeoIP2ASNDBPath is the path to the GeoLite2-ASN (or GeoIP2-ISP) database file, optional
+	GeoIP2ASNDBPath string `mapstructure:"geoip2_asn_db_path"`
+	// GeoIP2ConnectionTypeDBPath is the path to the GeoIP2-Connection-Type database file, optional
+	GeoIP2ConnectionTypeDBPath string `mapstructure:"geoip2_connection_type_db_path"`
 }
 
 // String function implements Stringer interfaces and used to represent
@@ -37,6 +56,12 @@ func (cfg *AppConfig) String() string {
 	return string(cfgJSON)
 }
 
+// LogValue function implements slog.LogValuer, so all log handlers render the configuration
+// by String function, which omits secrets.
+func (cfg *AppConfig) LogValue() slog.Value {
+	return slog.StringValue(cfg.String())
+}
+
 // GetConfig function provides access to the application config.
 func GetConfig() *AppConfig {
 	if applicationConfig == nil {
@@ -71,6 +96,13 @@ func initConfig() *AppConfig {
 	cfg.HTTPServerConf = new(HTTPServerConf)
 	cfg.DBConf = new(DBConf)
 	cfg.LogConf = new(LoggerConf)
+	cfg.ClickHouseConf = new(ClickHouseConf)
+	cfg.RedirectConf = new(RedirectConf)
+	cfg.UniqueClicksConf = new(UniqueClicksConf)
+	cfg.IPListsConf = new(IPListsConf)
+	cfg.CloakingConf = new(CloakingConf)
+	cfg.InterstitialConf = new(InterstitialConf)
+	cfg.PostbackConf = new(PostbackConf)
 
 	// Viper unmarshal the loaded env variables into the config structs
 	if err := viper.Unmarshal(&cfg.HTTPServerConf); err != nil {
@@ -85,6 +117,27 @@ func initConfig() *AppConfig {
 	if err := viper.Unmarshal(&cfg.RedisConf); err != nil {
 		panic(fmt.Errorf("cannot unmarshal RedisConf. error: %w", err))
 	}
+	if err := viper.Unmarshal(&cfg.ClickHouseConf); err != nil {
+		panic(fmt.Errorf("cannot unmarshal ClickHouseConf. error: %w", err))
+	}
+	if err := viper.Unmarshal(&cfg.RedirectConf); err != nil {
+		panic(fmt.Errorf("cannot unmarshal RedirectConf. error: %w", err))
+	}
+	if err := viper.Unmarshal(&cfg.UniqueClicksConf); err != nil {
+		panic(fmt.Errorf("cannot unmarshal UniqueClicksConf. error: %w", err))
+	}
+	if err := viper.Unmarshal(&cfg.IPListsConf); err != nil {
+		panic(fmt.Errorf("cannot unmarshal IPListsConf. error: %w", err))
+	}
+	if err := viper.Unmarshal(&cfg.CloakingConf); err != nil {
+		panic(fmt.Errorf("cannot unmarshal CloakingConf. error: %w", err))
+	}
+	if err := viper.Unmarshal(&cfg.InterstitialConf); err != nil {
+		panic(fmt.Errorf("cannot unmarshal InterstitialConf. error: %w", err))
+	}
+	if err := viper.Unmarshal(&cfg.PostbackConf); err != nil {
+		panic(fmt.Errorf("cannot unmarshal PostbackConf. error: %w", err))
+	}
 	if err := viper.Unmarshal(&cfg); err != nil {
 		panic(fmt.Errorf("cannot unmarshal GeoIP2DBPath. error: %w", err))
 	}
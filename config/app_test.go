@@ -0,0 +1,26 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestAppConfig_String_OmitsSecrets(t *testing.T) {
+	cfg := &AppConfig{
+		CloakingConf: &CloakingConf{Secret: "cloaking-secret", HopTTL: 30},
+	}
+
+	if s := cfg.String(); strings.Contains(s, "cloaking-secret") || !strings.Contains(s, "HopTTL") {
+		t.Errorf("unexpected config string %s", s)
+	}
+
+	var buf bytes.Buffer
+	for _, handler := range []slog.Handler{slog.NewTextHandler(&buf, nil), slog.NewJSONHandler(&buf, nil)} {
+		slog.New(handler).Info("config", "config", cfg)
+	}
+	if strings.Contains(buf.String(), "cloaking-secret") {
+		t.Errorf("secret is logged: %s", buf.String())
+	}
+}
@@ -0,0 +1,21 @@
+// Package config contains structures that represent configs for different application modules.
+package config
+
+// ClickHouseConf holds ClickHouse connection configuration.
+type ClickHouseConf struct {
+	// Host is the ClickHouse server hostname, clicks and conversions are not stored if empty
+	Host string `mapstructure:"clickhouse_host"`
+	// Port is the ClickHouse native protocol port
+	Port string `mapstructure:"clickhouse_port"`
+	// Database is the ClickHouse database name
+	Database string `mapstructure:"clickhouse_db"`
+	// Username is the ClickHouse user
+	Username string `mapstructure:"clickhouse_user"`
+	// Password is the ClickHouse user password
+	Password string `mapstructure:"clickhouse_password"`
+}
+
+// IsEnabled returns true when the ClickHouse connection is configured.
+func (c *ClickHouseConf) IsEnabled() bool {
+	return c.Host != ""
+}
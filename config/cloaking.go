@@ -0,0 +1,17 @@
+// Package config contains structures that represent configs for different application modules.
+package config
+
+import "time"
+
+// CloakingConf contains referrer cloaking settings.
+type CloakingConf struct {
+	// Secret is the key used to sign hop URLs, it should be shared by all instances of the service
+	Secret string `mapstructure:"cloaking_secret" json:"-"`
+	// HopTTL is the hop URL lifetime in seconds
+	HopTTL int `mapstructure:"cloaking_hop_ttl"`
+}
+
+// HopTTLDuration returns the hop URL lifetime.
+func (c *CloakingConf) HopTTLDuration() time.Duration {
+	return time.Duration(c.HopTTL) * time.Second
+}
@@ -22,6 +22,9 @@ type HTTPServerConf struct {
 	ShutdownTimeout int `mapstructure:"http_server_shutdown_timeout"`
 	// SSLCertPath is the path to SSL certificate file
 	SSLCertPath string `mapstructure:"http_server_cert"`
+	// TrustForwardedProto enables reading the request protocol from X-Forwarded-Proto header,
+	// it should be set only if the server is reachable through a trusted proxy or load balancer
+	TrustForwardedProto bool `mapstructure:"http_server_trust_forwarded_proto"`
 }
 
 // GetHTTPReadTimeout return server read timeout configuration value.
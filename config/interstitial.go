@@ -0,0 +1,17 @@
+// Package config contains structures that represent configs for different application modules.
+package config
+
+import "time"
+
+// InterstitialConf contains interstitial page templates settings.
+type InterstitialConf struct {
+	// TemplatesDir is the directory with "<name>.html" templates, interstitial pages are disabled if empty
+	TemplatesDir string `mapstructure:"interstitial_templates_dir"`
+	// ReloadInterval is the interval (in seconds) between templates reloads, templates are loaded once if not positive
+	ReloadInterval int `mapstructure:"interstitial_reload_interval"`
+}
+
+// ReloadDuration returns the interval between templates reloads.
+func (c *InterstitialConf) ReloadDuration() time.Duration {
+	return time.Duration(c.ReloadInterval) * time.Second
+}
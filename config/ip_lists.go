@@ -0,0 +1,17 @@
+// Package config contains structures that represent configs for different application modules.
+package config
+
+import "time"
+
+// IPListsConf contains settings used to load global IP allow/deny lists.
+type IPListsConf struct {
+	// FilePath is the path to the local file with additional lists entries, optional
+	FilePath string `mapstructure:"ip_lists_file"`
+	// ReloadInterval is the interval (in seconds) between lists reloads, lists are loaded once if not positive
+	ReloadInterval int `mapstructure:"ip_lists_reload_interval"`
+}
+
+// ReloadDuration returns the interval between lists reloads.
+func (c *IPListsConf) ReloadDuration() time.Duration {
+	return time.Duration(c.ReloadInterval) * time.Second
+}
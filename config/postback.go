@@ -0,0 +1,40 @@
+// Package config contains structures that represent configs for different application modules.
+package config
+
+import "time"
+
+// PostbackConf contains settings of server-to-server postbacks sent to affiliate trackers.
+type PostbackConf struct {
+	// DispatchInterval is the interval (in seconds) between queue checks, postbacks are not sent if not positive
+	DispatchInterval int `mapstructure:"postback_dispatch_interval"`
+	// BatchSize is the number of postbacks claimed by a single queue check
+	BatchSize int `mapstructure:"postback_batch_size"`
+	// MaxAttempts is the number of delivery attempts made before the postback is failed
+	MaxAttempts int `mapstructure:"postback_max_attempts"`
+	// RetryBackoff is the delay (in seconds) before the first retry, doubled for every next one
+	RetryBackoff int `mapstructure:"postback_retry_backoff"`
+	// MaxRetryBackoff is the maximum delay (in seconds) between retries
+	MaxRetryBackoff int `mapstructure:"postback_max_retry_backoff"`
+	// Timeout is the postback request timeout (in seconds)
+	Timeout int `mapstructure:"postback_timeout"`
+}
+
+// DispatchDuration returns the interval between queue checks.
+func (c *PostbackConf) DispatchDuration() time.Duration {
+	return time.Duration(c.DispatchInterval) * time.Second
+}
+
+// RetryBackoffDuration returns the delay before the first retry.
+func (c *PostbackConf) RetryBackoffDuration() time.Duration {
+	return time.Duration(c.RetryBackoff) * time.Second
+}
+
+// MaxRetryBackoffDuration returns the maximum delay between retries.
+func (c *PostbackConf) MaxRetryBackoffDuration() time.Duration {
+	return time.Duration(c.MaxRetryBackoff) * time.Second
+}
+
+// TimeoutDuration returns the postback request timeout.
+func (c *PostbackConf) TimeoutDuration() time.Duration {
+	return time.Duration(c.Timeout) * time.Second
+}
@@ -0,0 +1,24 @@
+// Package config contains structures that represent configs for different application modules.
+package config
+
+// RedirectConf contains settings used by the redirect interactor.
+type RedirectConf struct {
+	// MaxRedirectDepth is the maximum number of slug hops allowed for a single request
+	MaxRedirectDepth int `mapstructure:"redirect_max_depth"`
+	// FallbackURL is the URL used when a redirect chain contains a loop or is too long, or redirect rules are invalid,
+	// MisconfiguredFallbackURL takes precedence over it
+	FallbackURL string `mapstructure:"redirect_fallback_url"`
+	// NotFoundFallbackURL is the URL used instead of an error page when the tracking link is not found
+	NotFoundFallbackURL string `mapstructure:"redirect_not_found_fallback_url"`
+	// DisabledFallbackURL is the URL used instead of an error page when the tracking link is disabled
+	DisabledFallbackURL string `mapstructure:"redirect_disabled_fallback_url"`
+	// BlockedFallbackURL is the URL used instead of an error page when traffic is rejected by restrictions
+	BlockedFallbackURL string `mapstructure:"redirect_blocked_fallback_url"`
+	// GeoFallbackURL is the URL used instead of an error page when traffic is rejected by geo restrictions
+	GeoFallbackURL string `mapstructure:"redirect_geo_fallback_url"`
+	// MisconfiguredFallbackURL is the URL used instead of an error page when the tracking link redirect rules
+	// or redirect chain are invalid
+	MisconfiguredFallbackURL string `mapstructure:"redirect_misconfigured_fallback_url"`
+	// ClickCapsEnabled enables click caps enforcement with Redis counters
+	ClickCapsEnabled bool `mapstructure:"redirect_click_caps_enabled"`
+}
@@ -0,0 +1,31 @@
+// Package config contains structures that represent configs for different application modules.
+package config
+
+import "time"
+
+// Predefined unique clicks detectors.
+const (
+	// RedisUniqueClicksDetector stores seen clicks in Redis.
+	RedisUniqueClicksDetector = "redis"
+	// MemoryUniqueClicksDetector stores seen clicks in in-memory bloom filters.
+	MemoryUniqueClicksDetector = "memory"
+)
+
+// UniqueClicksConf contains settings used to detect duplicate clicks.
+type UniqueClicksConf struct {
+	// Detector is the storage of seen clicks ("redis" or "memory"), detection is disabled if empty
+	Detector string `mapstructure:"unique_clicks_detector"`
+	// Key defines how visitors are identified ("ip_ua" or "visitor")
+	Key string `mapstructure:"unique_clicks_key"`
+	// Window is the deduplication window (in seconds)
+	Window int `mapstructure:"unique_clicks_window"`
+	// BloomCapacity is the expected number of clicks per window used to size in-memory bloom filters
+	BloomCapacity uint `mapstructure:"unique_clicks_bloom_capacity"`
+	// BloomFalsePositiveRate is the acceptable share of unique clicks reported as duplicates by bloom filters
+	BloomFalsePositiveRate float64 `mapstructure:"unique_clicks_bloom_fp_rate"`
+}
+
+// WindowDuration returns the deduplication window.
+func (c *UniqueClicksConf) WindowDuration() time.Duration {
+	return time.Duration(c.Window) * time.Second
+}
@@ -11,17 +11,32 @@ CREATE TABLE IF NOT EXISTS clicks (
                                       affiliate_id String,
                                       advertiser_id String,
                                       is_parallel UInt8,
+                                      is_unique UInt8,
+                                      is_cloaked UInt8,
 
                                       landing_id String,
                                       gclid String,
+                                      ad_click_ids Map(String, String),
 
                                       agent String,
                                       platform String,
                                       browser String,
                                       device String,
+                                      bot UInt8,
 
                                       ip String,
                                       country_code FixedString(2),
+                                      region String,
+                                      city String,
+                                      postal_code String,
+                                      latitude Float64,
+                                      longitude Float64,
+                                      timezone String,
+                                      asn UInt32,
+                                      asn_org String,
+                                      isp String,
+                                      connection_type String,
+                                      language String,
 
                                       p1 String,
                                       p2 String,
@@ -38,4 +53,22 @@ CREATE TABLE IF NOT EXISTS clicks (
     ENGINE = MergeTree()
 PARTITION BY toYYYYMM(created_at)
 ORDER BY (created_at, id)
-SETTINGS index_granularity = 8192;
\ No newline at end of file
+SETTINGS index_granularity = 8192;
+-- Columns added after the initial schema, upgrade tables created by earlier versions.
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS bot UInt8 AFTER device;
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS is_unique UInt8 AFTER is_parallel;
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS language String AFTER country_code;
+ALTER TABLE clicks
+    ADD COLUMN IF NOT EXISTS region String AFTER country_code,
+    ADD COLUMN IF NOT EXISTS city String AFTER region,
+    ADD COLUMN IF NOT EXISTS postal_code String AFTER city,
+    ADD COLUMN IF NOT EXISTS latitude Float64 AFTER postal_code,
+    ADD COLUMN IF NOT EXISTS longitude Float64 AFTER latitude,
+    ADD COLUMN IF NOT EXISTS timezone String AFTER longitude;
+ALTER TABLE clicks
+    ADD COLUMN IF NOT EXISTS asn UInt32 AFTER timezone,
+    ADD COLUMN IF NOT EXISTS asn_org String AFTER asn,
+    ADD COLUMN IF NOT EXISTS isp String AFTER asn_org,
+    ADD COLUMN IF NOT EXISTS connection_type String AFTER isp;
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS ad_click_ids Map(String, String) AFTER gclid;
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS is_cloaked UInt8 AFTER is_unique;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS conversions (
+                                      id String,
+                                      click_id String,
+                                      transaction_id String,
+                                      event_type LowCardinality(String),
+                                      payout Float64,
+
+                                      slug String,
+                                      source_id String,
+                                      campaign_id String,
+                                      affiliate_id String,
+                                      advertiser_id String,
+                                      landing_id String,
+
+                                      clicked_at DateTime,
+                                      created_at DateTime,
+
+                                      INDEX idx_click click_id TYPE bloom_filter GRANULARITY 1,
+                                      INDEX idx_transaction transaction_id TYPE bloom_filter GRANULARITY 1,
+                                      INDEX idx_campaign campaign_id TYPE bloom_filter GRANULARITY 1,
+                                      INDEX idx_affiliate affiliate_id TYPE bloom_filter GRANULARITY 1
+)
+    ENGINE = MergeTree()
+PARTITION BY toYYYYMM(created_at)
+ORDER BY (created_at, id)
+SETTINGS index_granularity = 8192;
@@ -0,0 +1,22 @@
+package dto
+
+// ConversionRequestData type describes input of interactor.ConversionInteractor Convert function.
+type ConversionRequestData struct {
+	// RequestID uniquely identifies the postback request, it's used as the conversion ID
+	RequestID string
+	// ClickID is the identifier of the click (RedirectRequestData.RequestID) the conversion belongs to
+	ClickID string
+	// EventType is the type of the advertiser event, the default event type is used if empty
+	EventType string
+	// Payout is the amount paid for the conversion
+	Payout float64
+	// TransactionID is the advertiser side event identifier, optional
+	TransactionID string
+	// Signature is the hex encoded HMAC-SHA256 of SignedPayload made with the advertiser postback secret
+	Signature string
+	// SignedPayload is the canonical form of the conversion parameters covered by the signature
+	SignedPayload string
+	// AllowUnsigned accepts conversions without signature (e.g. tracking pixels fired by browsers),
+	// their payout is ignored and affiliates are not notified
+	AllowUnsigned bool
+}
@@ -5,7 +5,10 @@ package dto
 import (
 	"errors"
 	"net"
+	"net/textproto"
 	"net/url"
+	"strings"
+	"time"
 )
 
 // RedirectRequestData contains all the information needed to process a redirect request,
@@ -19,6 +22,8 @@ type RedirectRequestData struct {
 	Params map[string][]string
 	// Headers contains HTTP request headers
 	Headers map[string][]string
+	// Cookies contains HTTP request cookies
+	Cookies map[string]string
 	// UserAgent is the raw User-Agent header string
 	UserAgent string
 	// IP is the client's IP address
@@ -29,6 +34,10 @@ type RedirectRequestData struct {
 	Referer string
 	// URL contains the full request URL
 	URL *url.URL
+	// VisitorID identifies a returning visitor (taken from the visitor cookie), might be empty
+	VisitorID string
+	// Time is the moment the request was received, current time is used if zero
+	Time time.Time
 }
 
 // GetParam is a helper function for convenient access to the request query params.
@@ -40,6 +49,37 @@ func (rrd *RedirectRequestData) GetParam(key string) []string {
 	return make([]string, 0)
 }
 
+// GetHeader is a helper function for convenient access to the request headers (case-insensitive).
+// Multiple header values are joined with comma.
+func (rrd *RedirectRequestData) GetHeader(name string) string {
+	if val, ok := rrd.Headers[textproto.CanonicalMIMEHeaderKey(name)]; ok {
+		return strings.Join(val, ",")
+	}
+
+	for key, val := range rrd.Headers {
+		if strings.EqualFold(key, name) {
+			return strings.Join(val, ",")
+		}
+	}
+
+	return ""
+}
+
+// GetCookie is a helper function for convenient access to the request cookies.
+func (rrd *RedirectRequestData) GetCookie(name string) string {
+	return rrd.Cookies[name]
+}
+
+// VisitorKey returns a string which identifies the visitor across requests.
+// VisitorID is used when it's known, otherwise the key is built from the IP address and User-Agent.
+func (rrd *RedirectRequestData) VisitorKey() string {
+	if rrd.VisitorID != "" {
+		return rrd.VisitorID
+	}
+
+	return rrd.IP.String() + "|" + rrd.UserAgent
+}
+
 // Validate function validates the redirect request data.
 func (rrd *RedirectRequestData) Validate() error {
 	if rrd.Slug == "" {
@@ -47,6 +47,52 @@ func TestRedirectRequestData_GetParam(t *testing.T) {
 	}
 }
 
+func TestRedirectRequestData_GetHeader(t *testing.T) {
+	testCases := []struct {
+		name       string
+		headers    map[string][]string
+		headerName string
+		expected   string
+	}{
+		{
+			name:       "no header",
+			headers:    map[string][]string{},
+			headerName: "X-Device-Id",
+			expected:   "",
+		},
+		{
+			name:       "canonical header name",
+			headers:    map[string][]string{"X-Device-Id": {"abc"}},
+			headerName: "x-device-id",
+			expected:   "abc",
+		},
+		{
+			name:       "not canonical header key",
+			headers:    map[string][]string{"x-device-id": {"abc"}},
+			headerName: "X-Device-Id",
+			expected:   "abc",
+		},
+		{
+			name:       "multiple values",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.1.1.1", "2.2.2.2"}},
+			headerName: "X-Forwarded-For",
+			expected:   "1.1.1.1,2.2.2.2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requestData := dto.RedirectRequestData{
+				Headers: tc.headers,
+			}
+
+			if value := requestData.GetHeader(tc.headerName); value != tc.expected {
+				t.Errorf("expected %q but got %q", tc.expected, value)
+			}
+		})
+	}
+}
+
 func TestRedirectRequestData_Validate(t *testing.T) {
 	tests := []struct {
 		name        string
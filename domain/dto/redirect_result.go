@@ -2,8 +2,25 @@
 // These DTOs are used to encapsulate data that is transferred between different layers of the application.
 package dto
 
+// Interstitial type describes the page (countdown, disclaimer, age gate, ...) rendered before the final redirect.
+type Interstitial struct {
+	// Template is the name of the interstitial page template
+	Template string
+	// Token resolves the token expression ("country_code", "p1|default:none") for the request
+	Token func(expr string) string
+}
+
 // RedirectResult type describes output of interactor.RedirectInteractor Redirect function.
 type RedirectResult struct {
 	TargetURL string
 	OutputCh  <-chan *ClickProcessingResult
+	// Method is the redirect method (valueobject.FoundRedirectMethod, valueobject.MetaRefreshRedirectMethod, ...),
+	// "303 See Other" redirect is used when empty
+	Method string
+	// Cloaked indicates that the referrer should be hidden from the target with an intermediate page
+	Cloaked bool
+	// Interstitial is the page rendered before the redirect, nil if the visitor is redirected immediately
+	Interstitial *Interstitial
+	// Reason is the restriction which caused the redirect rules to be applied, nil for regular redirects
+	Reason error
 }
@@ -37,11 +37,17 @@ type Click struct {
 	AdvertiserID string
 	// IsParallel indicates if this is a parallel redirect
 	IsParallel bool
+	// IsUnique indicates if this is the first click of the visitor during the deduplication window
+	IsUnique bool
+	// IsCloaked indicates if the referrer was hidden from the target with referrer cloaking
+	IsCloaked bool
 
 	// LandingID identifies the landing page
 	LandingID string
 	// GCLID is the Google Click Identifier
 	GCLID string
+	// AdClickIDs contains known ad network click identifiers (gclid, fbclid, msclkid, ...) by query param name
+	AdClickIDs map[string]string
 
 	// UserAgent contains parsed user agent information
 	UserAgent *valueobject.UserAgent
@@ -53,11 +59,36 @@ type Click struct {
 	Browser string
 	// Device is the device type
 	Device string
+	// Bot indicates if the click was made by a bot/crawler
+	Bot bool
 
 	// IP is the visitor's IP address
 	IP net.IP
 	// CountryCode is the visitor's country code
 	CountryCode string
+	// Region is the visitor's region (state, province) code
+	Region string
+	// City is the visitor's city name
+	City string
+	// PostalCode is the visitor's postal code
+	PostalCode string
+	// Latitude is the visitor's approximate latitude
+	Latitude float64
+	// Longitude is the visitor's approximate longitude
+	Longitude float64
+	// TimeZone is the visitor's timezone
+	TimeZone string
+
+	// ASN is the visitor's autonomous system number
+	ASN uint
+	// ASNOrganization is the organization the visitor's autonomous system is registered to
+	ASNOrganization string
+	// ISP is the visitor's internet service provider
+	ISP string
+	// ConnectionType is the visitor's connection type
+	ConnectionType string
+	// Language is the visitor's preferred language code
+	Language string
 
 	// P1-P4 are custom tracking parameters
 	P1 string
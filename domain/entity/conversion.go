@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// Conversion represents an advertiser event (install, lead, sale, ...) attributed to a click.
+type Conversion struct {
+	// ID uniquely identifies this conversion
+	ID string
+	// ClickID identifies the click the conversion is attributed to
+	ClickID string
+	// TransactionID is the advertiser side event identifier used to deduplicate postbacks
+	TransactionID string
+	// EventType is the type of the advertiser event (e.g. "install", "lead", "sale")
+	EventType string
+	// Payout is the amount paid for the conversion
+	Payout float64
+
+	// Slug identifies the tracking link of the click
+	Slug string
+	// SourceID identifies the traffic source of the click
+	SourceID string
+	// CampaignID identifies the campaign of the click
+	CampaignID string
+	// AffiliateID identifies the affiliate of the click
+	AffiliateID string
+	// AdvertiserID identifies the advertiser of the click
+	AdvertiserID string
+	// LandingID identifies the landing page of the click
+	LandingID string
+	// Click is the click the conversion is attributed to
+	Click *Click
+
+	// ClickedAt is the time of the click
+	ClickedAt time.Time
+	// CreatedAt is the time of the conversion
+	CreatedAt time.Time
+}
+
+// DeduplicationKey returns the key shared by repeated postbacks of the same conversion.
+// Conversions are matched by advertiser and transaction ID, conversions without transaction ID
+// are matched by click ID and event type.
+func (c *Conversion) DeduplicationKey() string {
+	if c.TransactionID != "" {
+		return "transaction|" + c.AdvertiserID + "|" + c.TransactionID
+	}
+
+	return "event|" + c.ClickID + "|" + c.EventType
+}
@@ -0,0 +1,36 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/lroman242/redirector/domain/entity"
+)
+
+func TestConversion_DeduplicationKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		conversion entity.Conversion
+		want       string
+	}{
+		{
+			name: "transaction id",
+			conversion: entity.Conversion{
+				ID: "conv-1", ClickID: "click-1", AdvertiserID: "advertiser-1", TransactionID: "tx-1", EventType: "sale",
+			},
+			want: "transaction|advertiser-1|tx-1",
+		},
+		{
+			name:       "no transaction id",
+			conversion: entity.Conversion{ID: "conv-1", ClickID: "click-1", AdvertiserID: "advertiser-1", EventType: "sale"},
+			want:       "event|click-1|sale",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.conversion.DeduplicationKey(); got != tt.want {
+				t.Errorf("DeduplicationKey() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
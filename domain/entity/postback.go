@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// Postback represents a server-to-server callback notifying the affiliate tracker about the conversion.
+type Postback struct {
+	// ID uniquely identifies this postback
+	ID string
+	// ConversionID identifies the conversion the postback notifies about
+	ConversionID string
+	// AffiliateID identifies the affiliate the postback is sent to
+	AffiliateID string
+	// SourceID identifies the traffic source of the conversion
+	SourceID string
+	// URL is the rendered postback URL
+	URL string
+	// Status is the delivery status (see valueobject.PendingPostbackStatus)
+	Status string
+	// Attempts is the number of delivery attempts made
+	Attempts int
+	// NextAttemptAt is the time of the next delivery attempt
+	NextAttemptAt time.Time
+	// CreatedAt is the time the postback was queued
+	CreatedAt time.Time
+}
+
+// PostbackAttempt represents a single delivery attempt of the postback.
+type PostbackAttempt struct {
+	// PostbackID identifies the postback
+	PostbackID string
+	// ConversionID identifies the conversion the postback notifies about
+	ConversionID string
+	// Attempt is the sequence number of the attempt, starting with 1
+	Attempt int
+	// URL is the requested postback URL
+	URL string
+	// StatusCode is the HTTP status code of the response, zero if no response was received
+	StatusCode int
+	// Error describes the transport error, empty if the response was received
+	Error string
+	// Duration is the time taken by the request
+	Duration time.Duration
+	// CreatedAt is the time the attempt was made
+	CreatedAt time.Time
+}
@@ -27,12 +27,25 @@ type TrackingLink struct {
 	// CampaignProtocolRedirectRules contains protocol-specific redirect logic
 	CampaignProtocolRedirectRules *valueobject.RedirectRules
 
+	// AllowedIPs defines networks which are always permitted, takes precedence over DeniedIPs and global lists
+	AllowedIPs valueobject.CIDRList
+	// DeniedIPs defines networks which are blocked, takes precedence over global lists
+	DeniedIPs valueobject.CIDRList
+	// CampaignIPRedirectRulesID references rules for blocked IP addresses
+	CampaignIPRedirectRulesID int32
+	// CampaignIPRedirectRules contains redirect logic for blocked IP addresses
+	CampaignIPRedirectRules *valueobject.RedirectRules
+
 	// IsCampaignOveraged indicates if campaign limits have been exceeded
 	IsCampaignOveraged bool
 	// CampaignOveragedRedirectRulesID references rules for overaged campaigns
 	CampaignOveragedRedirectRulesID int32
 	// CampaignOverageRedirectRules contains redirect logic for overaged campaigns
 	CampaignOverageRedirectRules *valueobject.RedirectRules
+	// ClickCaps limits the number of clicks of this tracking link, overage rules are applied when reached
+	ClickCaps valueobject.ClickCaps
+	// CampaignClickCaps limits the number of clicks of the whole campaign, it is shared by all tracking links of the campaign
+	CampaignClickCaps valueobject.ClickCaps
 
 	// IsCampaignActive indicates if the campaign is currently active
 	IsCampaignActive bool
@@ -41,6 +54,21 @@ type TrackingLink struct {
 	// CampaignDisabledRedirectRules contains redirect logic for inactive campaigns
 	CampaignDisabledRedirectRules *valueobject.RedirectRules
 
+	// AllowBots indicates if traffic detected as bots/crawlers is allowed, otherwise it is handled
+	// by the bots redirect rules. Tracking links decoded from JSON without the field allow bots
+	AllowBots bool
+	// CampaignBotsRedirectRulesID references bot-specific redirect rules
+	CampaignBotsRedirectRulesID int32
+	// CampaignBotsRedirectRules contains redirect logic for bot traffic
+	CampaignBotsRedirectRules *valueobject.RedirectRules
+
+	// HandleDuplicateClicks indicates if duplicate clicks should be handled by the duplicate redirect rules
+	HandleDuplicateClicks bool
+	// CampaignDuplicateRedirectRulesID references rules for duplicate clicks
+	CampaignDuplicateRedirectRulesID int32
+	// CampaignDuplicateRedirectRules contains redirect logic for duplicate clicks
+	CampaignDuplicateRedirectRules *valueobject.RedirectRules
+
 	// AllowedGeos defines which geographic locations are permitted
 	AllowedGeos AllowedListType
 	// CampaignGeoRedirectRulesID references geo-specific redirect rules
@@ -48,6 +76,20 @@ type TrackingLink struct {
 	// CampaignGeoRedirectRules contains redirect logic for geo restrictions
 	CampaignGeoRedirectRules *valueobject.RedirectRules
 
+	// AllowedRegions defines which regions (states, provinces) are permitted, e.g. "US-CA" or "CA"
+	AllowedRegions AllowedListType
+	// CampaignRegionRedirectRulesID references region-specific redirect rules
+	CampaignRegionRedirectRulesID int32
+	// CampaignRegionRedirectRules contains redirect logic for region restrictions
+	CampaignRegionRedirectRules *valueobject.RedirectRules
+
+	// AllowedCities defines which cities are permitted
+	AllowedCities AllowedListType
+	// CampaignCityRedirectRulesID references city-specific redirect rules
+	CampaignCityRedirectRulesID int32
+	// CampaignCityRedirectRules contains redirect logic for city restrictions
+	CampaignCityRedirectRules *valueobject.RedirectRules
+
 	// AllowedDevices defines which device types are permitted
 	AllowedDevices AllowedListType
 	// CampaignDevicesRedirectRulesID references device-specific redirect rules
@@ -62,11 +104,62 @@ type TrackingLink struct {
 	// CampaignOSRedirectRules contains redirect logic for OS restrictions
 	CampaignOSRedirectRules *valueobject.RedirectRules
 
+	// AllowedLanguages defines which visitor languages (e.g. "en", "pt-br") are permitted
+	AllowedLanguages AllowedListType
+	// CampaignLanguageRedirectRulesID references language-specific redirect rules
+	CampaignLanguageRedirectRulesID int32
+	// CampaignLanguageRedirectRules contains redirect logic for language restrictions
+	CampaignLanguageRedirectRules *valueobject.RedirectRules
+
+	// Schedule defines the time windows the tracking link is running in, always running if empty
+	Schedule valueobject.Schedule
+	// CampaignScheduleRedirectRulesID references rules for traffic outside of the schedule
+	CampaignScheduleRedirectRulesID int32
+	// CampaignScheduleRedirectRules contains redirect logic for traffic outside of the schedule
+	CampaignScheduleRedirectRules *valueobject.RedirectRules
+
+	// TargetingRules is the ordered list of targeting rules evaluated after the fixed restrictions above
+	TargetingRules []*valueobject.TargetingRule
+
 	// TargetURLTemplate is the template for generating the final redirect URL
 	TargetURLTemplate string
 
+	// RedirectMethod defines how the visitor is sent to the target URL (301, 302, 307, 308, meta_refresh, javascript),
+	// "303 See Other" redirect is used when empty
+	RedirectMethod string
+	// CloakReferrer indicates that the source site is hidden from the target with a double meta refresh,
+	// the redirect method is ignored in that case
+	CloakReferrer bool
+	// InterstitialTemplate is the name of the page template rendered before the redirect, the visitor is redirected
+	// immediately if empty
+	InterstitialTemplate string
+
+	// AllowedTokenKeys defines which query params, headers and cookies might be echoed by dynamic tokens.
+	// Keys have "param:utm_source", "header:X-Device-Id" or "cookie:vid" form, "param:*" allows all params
+	AllowedTokenKeys AllowedListType
+
+	// ParamsPassThrough defines which incoming query params are merged into the target URL
+	ParamsPassThrough valueobject.ParamsPassThrough
+
+	// ForwardedAdClickIDs defines which ad network click identifiers (gclid, fbclid, ...) are forwarded to the destination
+	ForwardedAdClickIDs AllowedListType
+
 	// AllowDeeplink indicates if deeplink redirects are allowed
 	AllowDeeplink bool
+	// AllowedDeeplinkSchemes defines which deeplink URL schemes are permitted, http and https are used if empty
+	AllowedDeeplinkSchemes AllowedListType
+	// AllowedDeeplinkDomains defines domain patterns ("example.com", "*.example.com") deeplinks might point to
+	AllowedDeeplinkDomains AllowedListType
+	// AdvertiserDeeplinkDomains defines domain patterns allowed for all tracking links of the advertiser
+	AdvertiserDeeplinkDomains AllowedListType
+	// CampaignDeeplinkRedirectRulesID references rules for rejected deeplinks
+	CampaignDeeplinkRedirectRulesID int32
+	// CampaignDeeplinkRedirectRules contains redirect logic for rejected deeplinks
+	CampaignDeeplinkRedirectRules *valueobject.RedirectRules
+
+	// AdvertiserFallbackURLs defines URLs traffic is sent to instead of error pages, keyed by the error class
+	// ("disabled", "blocked", "geo", "misconfigured")
+	AdvertiserFallbackURLs map[string]string
 
 	// CampaignID identifies the campaign this link belongs to
 	CampaignID string
@@ -81,6 +174,20 @@ type TrackingLink struct {
 	LandingPages map[string]*LandingPage
 }
 
+// UnmarshalJSON decodes the tracking link, bots are allowed unless the data disables them explicitly.
+func (t *TrackingLink) UnmarshalJSON(data []byte) error {
+	type trackingLink TrackingLink
+
+	x := trackingLink{AllowBots: true}
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+
+	*t = TrackingLink(x)
+
+	return nil
+}
+
 // AllowedListType represents a map of allowed values where the key is the value name
 // and the boolean indicates if it's allowed.
 type AllowedListType map[string]bool
@@ -117,4 +224,8 @@ type LandingPage struct {
 	PreviewURL string
 	// TargetURL is the actual URL where traffic will be sent
 	TargetURL string
+	// Weight is the share of the default traffic the landing page receives during rotation
+	Weight int
+	// IsActive shows if the landing page takes part in the default rotation
+	IsActive bool
 }
@@ -166,3 +166,31 @@ func TestAllowedListType_ScanUnmarshalError(t *testing.T) {
 		t.Errorf("Expected a JSON syntax error, got %T: %v", err, err)
 	}
 }
+
+func TestTrackingLink_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		allowBots bool
+	}{
+		{name: "bots allowed by default", data: `{"Slug":"abc"}`, allowBots: true},
+		{name: "bots disabled", data: `{"Slug":"abc","AllowBots":false}`, allowBots: false},
+		{name: "bots enabled", data: `{"Slug":"abc","AllowBots":true}`, allowBots: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trkLink := new(entity.TrackingLink)
+			if err := json.Unmarshal([]byte(tt.data), trkLink); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if trkLink.Slug != "abc" {
+				t.Errorf("unexpected slug %q", trkLink.Slug)
+			}
+			if trkLink.AllowBots != tt.allowBots {
+				t.Errorf("unexpected AllowBots. expected %t but got %t", tt.allowBots, trkLink.AllowBots)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package interactor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/lroman242/redirector/domain/dto"
+	"github.com/lroman242/redirector/domain/entity"
+	"github.com/lroman242/redirector/domain/repository"
+)
+
+// defaultConversionEventType is used when the postback doesn't define the event type.
+const defaultConversionEventType = "conversion"
+
+var (
+	// ErrInvalidConversion is returned when the conversion request data is incomplete or malformed.
+	ErrInvalidConversion = errors.New("invalid conversion data")
+	// ErrClickNotFound is returned when the conversion click ID doesn't match any stored click.
+	ErrClickNotFound = errors.New("no click was found by click ID")
+	// ErrDuplicateConversion is returned when the conversion was already registered.
+	ErrDuplicateConversion = errors.New("conversion is already registered")
+	// ErrUnauthorizedConversion is returned when the conversion signature is missing or invalid.
+	ErrUnauthorizedConversion = errors.New("conversion signature is missing or invalid")
+)
+
+//go:generate mockgen -package=mocks -destination=mocks/mock_conversion_interactor.go -source=conversion_interactor.go ConversionInteractor
+
+// ConversionInteractor handles the business logic for processing conversion postbacks.
+type ConversionInteractor interface {
+	// Convert attributes the conversion to the click, deduplicates and stores it.
+	// Returns ErrInvalidConversion, ErrClickNotFound, ErrUnauthorizedConversion or ErrDuplicateConversion
+	// if the conversion is rejected.
+	Convert(ctx context.Context, requestData *dto.ConversionRequestData) (*entity.Conversion, error)
+}
+
+// conversionInteractor implements ConversionInteractor.
+type conversionInteractor struct {
+	clicksRepository         repository.ClicksRepository
+	conversionsRepository    repository.ConversionsRepository
+	conversionKeysRepository repository.ConversionKeysRepository
+	postbackSecrets          repository.PostbackSecretsRepository
+	postbacks                PostbackInteractor
+}
+
+// ConversionInteractorOption configures optional behaviour of the ConversionInteractor implementation.
+type ConversionInteractorOption func(*conversionInteractor)
+
+// WithAffiliatePostbacks enables server-to-server postbacks to affiliate trackers for stored conversions.
+// Affiliates are not notified when the option is not set.
+func WithAffiliatePostbacks(postbacks PostbackInteractor) ConversionInteractorOption {
+	return func(c *conversionInteractor) {
+		c.postbacks = postbacks
+	}
+}
+
+// NewConversionInteractor function creates ConversionInteractor implementation.
+// Conversions are authenticated with the postback secrets of the click advertiser.
+func NewConversionInteractor(
+	clicksRepository repository.ClicksRepository,
+	conversionsRepository repository.ConversionsRepository,
+	conversionKeysRepository repository.ConversionKeysRepository,
+	postbackSecrets repository.PostbackSecretsRepository,
+	options ...ConversionInteractorOption,
+) ConversionInteractor {
+	c := &conversionInteractor{
+		clicksRepository:         clicksRepository,
+		conversionsRepository:    conversionsRepository,
+		conversionKeysRepository: conversionKeysRepository,
+		postbackSecrets:          postbackSecrets,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// Convert function validates the postback, attributes it to the click and stores the conversion.
+func (c *conversionInteractor) Convert(
+	ctx context.Context,
+	requestData *dto.ConversionRequestData,
+) (*entity.Conversion, error) {
+	clickID := strings.TrimSpace(requestData.ClickID)
+	if clickID == "" {
+		return nil, fmt.Errorf("%w: click ID is required", ErrInvalidConversion)
+	}
+	if requestData.Payout < 0 || math.IsNaN(requestData.Payout) || math.IsInf(requestData.Payout, 0) {
+		return nil, fmt.Errorf("%w: payout must be a non-negative number", ErrInvalidConversion)
+	}
+
+	click, err := c.clicksRepository.FindClick(ctx, clickID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find click: %w", err)
+	}
+	if click == nil {
+		return nil, ErrClickNotFound
+	}
+
+	authenticated, err := c.authenticate(ctx, click, requestData)
+	if err != nil {
+		return nil, err
+	}
+	if !authenticated && !requestData.AllowUnsigned {
+		return nil, ErrUnauthorizedConversion
+	}
+
+	payout := requestData.Payout
+	if !authenticated {
+		// the payout of unsigned conversions is controlled by the visitor, so it's not trusted
+		payout = 0
+	}
+
+	eventType := strings.ToLower(strings.TrimSpace(requestData.EventType))
+	if eventType == "" {
+		eventType = defaultConversionEventType
+	}
+
+	conversion := &entity.Conversion{
+		ID:            requestData.RequestID,
+		ClickID:       click.ID,
+		TransactionID: strings.TrimSpace(requestData.TransactionID),
+		EventType:     eventType,
+		Payout:        payout,
+		Slug:          click.Slug,
+		SourceID:      click.SourceID,
+		CampaignID:    click.CampaignID,
+		AffiliateID:   click.AffiliateID,
+		AdvertiserID:  click.AdvertiserID,
+		LandingID:     click.LandingID,
+		Click:         click,
+		ClickedAt:     click.CreatedAt,
+		CreatedAt:     time.Now(),
+	}
+
+	// the deduplication key is reserved atomically, so concurrent postbacks of the same conversion are rejected
+	reserved, err := c.conversionKeysRepository.Reserve(ctx, conversion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve conversion key: %w", err)
+	}
+	if !reserved {
+		return nil, ErrDuplicateConversion
+	}
+
+	if err = c.conversionsRepository.Save(ctx, conversion); err != nil {
+		// the key is released, so the advertiser might retry the postback
+		if releaseErr := c.conversionKeysRepository.Release(ctx, conversion); releaseErr != nil {
+			slog.Error("failed to release conversion key",
+				slog.String("conversion_id", conversion.ID),
+				slog.String("error", releaseErr.Error()),
+			)
+		}
+
+		return nil, fmt.Errorf("failed to save conversion: %w", err)
+	}
+
+	// the conversion is already stored, so the postback failure must not make the advertiser retry it,
+	// affiliates are notified about authenticated conversions only
+	if c.postbacks != nil && authenticated {
+		if err = c.postbacks.Enqueue(ctx, conversion); err != nil {
+			slog.Error("failed to enqueue affiliate postback",
+				slog.String("conversion_id", conversion.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	return conversion, nil
+}
+
+// authenticate function verifies the conversion signature with the postback secret of the click advertiser.
+// False is returned for unsigned conversions, ErrUnauthorizedConversion is returned if the signature is invalid
+// or the advertiser has no postback secret.
+func (c *conversionInteractor) authenticate(
+	ctx context.Context,
+	click *entity.Click,
+	requestData *dto.ConversionRequestData,
+) (bool, error) {
+	if requestData.Signature == "" {
+		return false, nil
+	}
+
+	secret, err := c.postbackSecrets.FindSecret(ctx, click.AdvertiserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find postback secret: %w", err)
+	}
+	if secret == "" {
+		return false, fmt.Errorf("%w: advertiser postback secret is not set", ErrUnauthorizedConversion)
+	}
+
+	signature, err := hex.DecodeString(requestData.Signature)
+	if err != nil {
+		return false, fmt.Errorf("%w: signature must be hex encoded", ErrUnauthorizedConversion)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(requestData.SignedPayload))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return false, ErrUnauthorizedConversion
+	}
+
+	return true, nil
+}
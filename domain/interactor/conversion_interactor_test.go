@@ -0,0 +1,345 @@
+package interactor_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/lroman242/redirector/domain/dto"
+	"github.com/lroman242/redirector/domain/entity"
+	"github.com/lroman242/redirector/domain/interactor"
+	"github.com/lroman242/redirector/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+// conversionTestSecret is the postback secret of the click advertiser used to sign test conversions.
+const conversionTestSecret = "advertiser-secret"
+
+// signConversion function returns the copy of the request data signed with the secret.
+func signConversion(requestData *dto.ConversionRequestData, secret string) *dto.ConversionRequestData {
+	signed := *requestData
+	signed.SignedPayload = "click_id=" + requestData.ClickID + "&transaction_id=" + requestData.TransactionID
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed.SignedPayload))
+	signed.Signature = hex.EncodeToString(mac.Sum(nil))
+
+	return &signed
+}
+
+func TestConversionInteractor_Convert(t *testing.T) {
+	clickedAt := time.Now().Add(-time.Hour)
+	click := &entity.Click{
+		ID:           "click-1",
+		Slug:         requestSlug,
+		SourceID:     "source-1",
+		CampaignID:   "campaign-1",
+		AffiliateID:  "affiliate-1",
+		AdvertiserID: "advertiser-1",
+		LandingID:    "landing-1",
+		CreatedAt:    clickedAt,
+	}
+	errStorage := errors.New("storage is down")
+
+	tests := []struct {
+		name           string
+		requestData    *dto.ConversionRequestData
+		unsigned       bool
+		noSecret       bool
+		secretErr      error
+		click          *entity.Click
+		findErr        error
+		expectFind     bool
+		duplicate      bool
+		duplicateErr   error
+		expectDupe     bool
+		saveErr        error
+		expectSave     bool
+		expectedErr    error
+		expectedEvent  string
+		expectedPayout float64
+	}{
+		{
+			name:           "success",
+			requestData:    &dto.ConversionRequestData{RequestID: "conv-1", ClickID: " click-1 ", EventType: "Sale", Payout: 1.5, TransactionID: "tx-1"},
+			click:          click,
+			expectFind:     true,
+			expectDupe:     true,
+			expectSave:     true,
+			expectedEvent:  "sale",
+			expectedPayout: 1.5,
+		},
+		{
+			name: "unsigned pixel conversion",
+			requestData: &dto.ConversionRequestData{
+				RequestID: "conv-1", ClickID: "click-1", EventType: "sale", Payout: 1.5, AllowUnsigned: true,
+			},
+			unsigned:      true,
+			click:         click,
+			expectFind:    true,
+			expectDupe:    true,
+			expectSave:    true,
+			expectedEvent: "sale",
+		},
+		{
+			name:        "unsigned postback",
+			requestData: &dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1", Payout: 1.5},
+			unsigned:    true,
+			click:       click,
+			expectFind:  true,
+			expectedErr: interactor.ErrUnauthorizedConversion,
+		},
+		{
+			name: "invalid signature",
+			requestData: &dto.ConversionRequestData{
+				RequestID: "conv-1", ClickID: "click-1", Signature: "abcd", SignedPayload: "click_id=click-1", AllowUnsigned: true,
+			},
+			unsigned:    true,
+			click:       click,
+			expectFind:  true,
+			expectedErr: interactor.ErrUnauthorizedConversion,
+		},
+		{
+			name:        "signed with another secret",
+			requestData: signConversion(&dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1"}, "another-secret"),
+			unsigned:    true,
+			click:       click,
+			expectFind:  true,
+			expectedErr: interactor.ErrUnauthorizedConversion,
+		},
+		{
+			name:        "advertiser secret is not set",
+			requestData: &dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1"},
+			click:       click,
+			expectFind:  true,
+			noSecret:    true,
+			expectedErr: interactor.ErrUnauthorizedConversion,
+		},
+		{
+			name:        "find secret error",
+			requestData: &dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1"},
+			click:       click,
+			expectFind:  true,
+			secretErr:   errStorage,
+			expectedErr: errStorage,
+		},
+		{
+			name:          "default event type",
+			requestData:   &dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1"},
+			click:         click,
+			expectFind:    true,
+			expectDupe:    true,
+			expectSave:    true,
+			expectedEvent: "conversion",
+		},
+		{
+			name:        "missing click id",
+			requestData: &dto.ConversionRequestData{RequestID: "conv-1", ClickID: " "},
+			expectedErr: interactor.ErrInvalidConversion,
+		},
+		{
+			name:        "negative payout",
+			requestData: &dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1", Payout: -1},
+			expectedErr: interactor.ErrInvalidConversion,
+		},
+		{
+			name:        "infinite payout",
+			requestData: &dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1", Payout: math.Inf(1)},
+			expectedErr: interactor.ErrInvalidConversion,
+		},
+		{
+			name:        "click not found",
+			requestData: &dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1"},
+			expectFind:  true,
+			expectedErr: interactor.ErrClickNotFound,
+		},
+		{
+			name:        "find click error",
+			requestData: &dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1"},
+			findErr:     errStorage,
+			expectFind:  true,
+			expectedErr: errStorage,
+		},
+		{
+			name:        "duplicate",
+			requestData: &dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1", TransactionID: "tx-1"},
+			click:       click,
+			expectFind:  true,
+			duplicate:   true,
+			expectDupe:  true,
+			expectedErr: interactor.ErrDuplicateConversion,
+		},
+		{
+			name:         "conversion key error",
+			requestData:  &dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1"},
+			click:        click,
+			expectFind:   true,
+			duplicateErr: errStorage,
+			expectDupe:   true,
+			expectedErr:  errStorage,
+		},
+		{
+			name:        "save error",
+			requestData: &dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1"},
+			click:       click,
+			expectFind:  true,
+			expectDupe:  true,
+			saveErr:     errStorage,
+			expectSave:  true,
+			expectedErr: errStorage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			clkRepo := mocks.NewMockClicksRepository(ctrl)
+			convRepo := mocks.NewMockConversionsRepository(ctrl)
+			keysRepo := mocks.NewMockConversionKeysRepository(ctrl)
+			secrets := mocks.NewMockPostbackSecretsRepository(ctrl)
+
+			requestData := tt.requestData
+			if !tt.unsigned {
+				requestData = signConversion(tt.requestData, conversionTestSecret)
+			}
+
+			if tt.expectFind {
+				clkRepo.EXPECT().FindClick(gomock.Any(), "click-1").Return(tt.click, tt.findErr)
+			}
+			if tt.click != nil && requestData.Signature != "" {
+				secret := conversionTestSecret
+				if tt.noSecret {
+					secret = ""
+				}
+				secrets.EXPECT().FindSecret(gomock.Any(), click.AdvertiserID).Return(secret, tt.secretErr)
+			}
+			if tt.expectDupe {
+				keysRepo.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(!tt.duplicate, tt.duplicateErr)
+			}
+			if tt.expectSave {
+				convRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(tt.saveErr)
+			}
+			if tt.saveErr != nil {
+				keysRepo.EXPECT().Release(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			conversion, err := interactor.NewConversionInteractor(clkRepo, convRepo, keysRepo, secrets).
+				Convert(context.Background(), requestData)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("expected error %v got %v", tt.expectedErr, err)
+				}
+				if conversion != nil {
+					t.Errorf("unexpected conversion %v", conversion)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if conversion.ID != tt.requestData.RequestID || conversion.ClickID != click.ID {
+				t.Errorf("unexpected conversion ids %s/%s", conversion.ID, conversion.ClickID)
+			}
+			if conversion.EventType != tt.expectedEvent {
+				t.Errorf("unexpected event type. expected %s got %s", tt.expectedEvent, conversion.EventType)
+			}
+			if conversion.Payout != tt.expectedPayout || conversion.TransactionID != tt.requestData.TransactionID {
+				t.Errorf("unexpected payout or transaction id %v/%s", conversion.Payout, conversion.TransactionID)
+			}
+			if conversion.Slug != click.Slug || conversion.SourceID != click.SourceID ||
+				conversion.CampaignID != click.CampaignID || conversion.AffiliateID != click.AffiliateID ||
+				conversion.AdvertiserID != click.AdvertiserID || conversion.LandingID != click.LandingID {
+				t.Errorf("conversion is not attributed to the click: %+v", conversion)
+			}
+			if !conversion.ClickedAt.Equal(clickedAt) {
+				t.Errorf("unexpected clicked at %v", conversion.ClickedAt)
+			}
+			if conversion.Click != click {
+				t.Errorf("unexpected conversion click %v", conversion.Click)
+			}
+		})
+	}
+}
+
+func TestConversionInteractor_Convert_AffiliatePostbacks(t *testing.T) {
+	requestData := signConversion(
+		&dto.ConversionRequestData{RequestID: "conv-1", ClickID: "click-1", EventType: "sale"},
+		conversionTestSecret,
+	)
+
+	for _, enqueueErr := range []error{nil, errors.New("db is down")} {
+		ctrl := gomock.NewController(t)
+		clkRepo := mocks.NewMockClicksRepository(ctrl)
+		convRepo := mocks.NewMockConversionsRepository(ctrl)
+		keysRepo := mocks.NewMockConversionKeysRepository(ctrl)
+		secrets := mocks.NewMockPostbackSecretsRepository(ctrl)
+		postbacks := mocks.NewMockPostbackInteractor(ctrl)
+
+		clkRepo.EXPECT().
+			FindClick(gomock.Any(), "click-1").
+			Return(&entity.Click{ID: "click-1", AffiliateID: "affiliate-1", AdvertiserID: "advertiser-1"}, nil)
+		secrets.EXPECT().FindSecret(gomock.Any(), "advertiser-1").Return(conversionTestSecret, nil)
+		keysRepo.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(true, nil)
+		convRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+		postbacks.EXPECT().
+			Enqueue(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, conversion *entity.Conversion) error {
+				if conversion.ID != "conv-1" || conversion.AffiliateID != "affiliate-1" {
+					t.Errorf("unexpected conversion %+v", conversion)
+				}
+
+				return enqueueErr
+			})
+
+		conversion, err := interactor.NewConversionInteractor(
+			clkRepo,
+			convRepo,
+			keysRepo,
+			secrets,
+			interactor.WithAffiliatePostbacks(postbacks),
+		).Convert(context.Background(), requestData)
+		// stored conversions are reported as successful even if the postback can't be queued
+		if err != nil || conversion == nil {
+			t.Errorf("unexpected result %v, %v", conversion, err)
+		}
+	}
+}
+
+func TestConversionInteractor_Convert_UnsignedAffiliatePostbacks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clkRepo := mocks.NewMockClicksRepository(ctrl)
+	convRepo := mocks.NewMockConversionsRepository(ctrl)
+	keysRepo := mocks.NewMockConversionKeysRepository(ctrl)
+	secrets := mocks.NewMockPostbackSecretsRepository(ctrl)
+	// unsigned conversions are stored, but affiliates are not notified
+	postbacks := mocks.NewMockPostbackInteractor(ctrl)
+
+	clkRepo.EXPECT().FindClick(gomock.Any(), "click-1").Return(&entity.Click{ID: "click-1", AffiliateID: "affiliate-1"}, nil)
+	keysRepo.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(true, nil)
+	convRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+	conversion, err := interactor.NewConversionInteractor(
+		clkRepo,
+		convRepo,
+		keysRepo,
+		secrets,
+		interactor.WithAffiliatePostbacks(postbacks),
+	).Convert(context.Background(), &dto.ConversionRequestData{
+		RequestID:     "conv-1",
+		ClickID:       "click-1",
+		Payout:        10,
+		AllowUnsigned: true,
+	})
+	if err != nil || conversion == nil {
+		t.Fatalf("unexpected result %v, %v", conversion, err)
+	}
+	if conversion.Payout != 0 {
+		t.Errorf("expected payout of unsigned conversion to be ignored, got %v", conversion.Payout)
+	}
+}
@@ -0,0 +1,107 @@
+package interactor
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/lroman242/redirector/domain/entity"
+)
+
+// deeplinkParam is the query param used to pass the deeplink URL.
+const deeplinkParam = "deeplink"
+
+// defaultDeeplinkSchemes are allowed when the tracking link doesn't define its own schemes allow-list.
+var defaultDeeplinkSchemes = []string{"http", "https"}
+
+// forbiddenDeeplinkSchemes are never allowed, even if listed in the tracking link schemes allow-list.
+var forbiddenDeeplinkSchemes = []string{"javascript", "data", "vbscript", "file", "blob"}
+
+// requestedDeeplink function returns the deeplink URL passed with the request, empty if deeplinks are disabled.
+func requestedDeeplink(trackingLink *entity.TrackingLink, params map[string][]string) string {
+	if !trackingLink.AllowDeeplink {
+		return ""
+	}
+
+	if values, ok := params[deeplinkParam]; ok && len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// validateDeeplink function checks the deeplink URL against the tracking link schemes and domains allow-lists.
+// Domains of http(s) deeplinks must match tracking link or advertiser domain patterns ("example.com", "*.example.com"),
+// the host of the tracking link target URL is allowed when no patterns are defined.
+func validateDeeplink(trackingLink *entity.TrackingLink, deeplink string) error {
+	deeplinkURL, err := url.Parse(strings.TrimSpace(deeplink))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDeeplinkNotAllowed, err)
+	}
+
+	scheme := strings.ToLower(deeplinkURL.Scheme)
+	if scheme == "" || containsFold(forbiddenDeeplinkSchemes, scheme) {
+		return fmt.Errorf("%w: scheme %q is forbidden", ErrDeeplinkNotAllowed, scheme)
+	}
+
+	schemes := allowedValues("", trackingLink.AllowedDeeplinkSchemes)
+	if len(schemes) == 0 {
+		schemes = defaultDeeplinkSchemes
+	}
+	if !containsFold(schemes, scheme) {
+		return fmt.Errorf("%w: scheme %q is not allowed", ErrDeeplinkNotAllowed, scheme)
+	}
+
+	if deeplinkURL.User != nil {
+		return fmt.Errorf("%w: user info is not allowed", ErrDeeplinkNotAllowed)
+	}
+
+	// hosts of app deeplinks (myapp://screen) are app specific, so domain patterns are applied to web URLs only
+	if scheme != "http" && scheme != "https" {
+		return nil
+	}
+
+	host := strings.ToLower(deeplinkURL.Hostname())
+	if host == "" {
+		return fmt.Errorf("%w: host is required", ErrDeeplinkNotAllowed)
+	}
+
+	patterns := append(
+		allowedValues("", trackingLink.AllowedDeeplinkDomains),
+		allowedValues("", trackingLink.AdvertiserDeeplinkDomains)...,
+	)
+	if len(patterns) == 0 {
+		if targetURL, err := url.Parse(trackingLink.TargetURLTemplate); err == nil && targetURL.Hostname() != "" {
+			patterns = append(patterns, targetURL.Hostname())
+		}
+	}
+
+	for _, pattern := range patterns {
+		if matchDomainPattern(strings.ToLower(strings.TrimSpace(pattern)), host) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: domain %q is not allowed", ErrDeeplinkNotAllowed, host)
+}
+
+// matchDomainPattern function checks if the host matches the domain pattern.
+// "*.example.com" matches subdomains only, other patterns match the exact host.
+func matchDomainPattern(pattern, host string) bool {
+	if suffix, found := strings.CutPrefix(pattern, "*."); found {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+
+	return pattern == host
+}
+
+// containsFold function reports whether the list contains the value (case-insensitive).
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+
+	return false
+}
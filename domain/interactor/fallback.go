@@ -0,0 +1,88 @@
+package interactor
+
+import (
+	"errors"
+
+	"github.com/lroman242/redirector/domain/dto"
+	"github.com/lroman242/redirector/domain/entity"
+)
+
+const (
+	// NotFoundErrorClass groups errors caused by unknown tracking links.
+	NotFoundErrorClass = "not_found"
+	// DisabledErrorClass groups errors caused by disabled tracking links.
+	DisabledErrorClass = "disabled"
+	// BlockedErrorClass groups errors caused by traffic rejected by tracking link restrictions.
+	BlockedErrorClass = "blocked"
+	// GeoErrorClass groups errors caused by traffic rejected by geo restrictions.
+	GeoErrorClass = "geo"
+	// MisconfiguredErrorClass groups errors caused by invalid redirect rules or redirect chains of tracking links.
+	MisconfiguredErrorClass = "misconfigured"
+)
+
+// errorClasses maps redirect errors to their classes, errors not listed here are considered internal.
+var errorClasses = []struct {
+	err   error
+	class string
+}{
+	{ErrTrackingLinkNotFound, NotFoundErrorClass},
+	{ErrTrackingLinkDisabled, DisabledErrorClass},
+	{ErrUnsupportedGeo, GeoErrorClass},
+	{ErrUnsupportedRegion, GeoErrorClass},
+	{ErrUnsupportedCity, GeoErrorClass},
+	{ErrBlockRedirect, BlockedErrorClass},
+	{ErrIPNotAllowed, BlockedErrorClass},
+	{ErrUnsupportedProtocol, BlockedErrorClass},
+	{ErrUnsupportedDevice, BlockedErrorClass},
+	{ErrUnsupportedOS, BlockedErrorClass},
+	{ErrUnsupportedLanguage, BlockedErrorClass},
+	{ErrBotsNotAllowed, BlockedErrorClass},
+	{ErrDuplicateClick, BlockedErrorClass},
+	{ErrClickCapReached, BlockedErrorClass},
+	{ErrOutOfSchedule, BlockedErrorClass},
+	{ErrDeeplinkNotAllowed, BlockedErrorClass},
+	{ErrRedirectLoop, MisconfiguredErrorClass},
+	{ErrRedirectDepthExceeded, MisconfiguredErrorClass},
+	{ErrInvalidRedirectRules, MisconfiguredErrorClass},
+	{ErrInvalidRedirectType, MisconfiguredErrorClass},
+}
+
+// ErrorClass function returns the class of the redirect error, empty string is returned for internal errors.
+func ErrorClass(err error) string {
+	for _, c := range errorClasses {
+		if errors.Is(err, c.err) {
+			return c.class
+		}
+	}
+
+	return ""
+}
+
+// fallbackResult function returns the result which sends the visitor to the fallback URL of the error class
+// ("not_found", "disabled", "blocked", "geo", "misconfigured"). Advertiser fallback URLs take precedence over global
+// ones, the URL set by WithFallbackURL is used for the misconfigured class when no other URL is defined.
+// Nil is returned when no fallback URL is defined. Fallback redirects are not registered as clicks.
+func (r *redirectInteractor) fallbackResult(trackingLink *entity.TrackingLink, err error) *dto.RedirectResult {
+	class := ErrorClass(err)
+	if class == "" {
+		return nil
+	}
+
+	targetURL := r.fallbackURLs[class]
+	if targetURL == "" && class == MisconfiguredErrorClass {
+		targetURL = r.fallbackURL
+	}
+	if trackingLink != nil && trackingLink.AdvertiserFallbackURLs[class] != "" {
+		targetURL = trackingLink.AdvertiserFallbackURLs[class]
+	}
+
+	if targetURL == "" {
+		return nil
+	}
+
+	return &dto.RedirectResult{
+		TargetURL: targetURL,
+		OutputCh:  skipClick(),
+		Reason:    err,
+	}
+}
@@ -0,0 +1,39 @@
+package interactor
+
+import (
+	"strings"
+
+	"github.com/lroman242/redirector/domain/dto"
+	"github.com/lroman242/redirector/domain/entity"
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+// makeInterstitial function describes the interstitial page of the tracking link, nil is returned if it is not set.
+// Template tokens are resolved the same way as URL tokens, but the values are not URL encoded.
+func (r *redirectInteractor) makeInterstitial(
+	trackingLink *entity.TrackingLink,
+	requestData *dto.RedirectRequestData,
+	ua *valueobject.UserAgent,
+	geo *valueobject.GeoLocation,
+) *dto.Interstitial {
+	if trackingLink.InterstitialTemplate == "" {
+		return nil
+	}
+
+	return &dto.Interstitial{
+		Template: trackingLink.InterstitialTemplate,
+		Token: func(expr string) string {
+			token := parseTokenExpr(expr)
+			value, _ := applyTokenModifiers(tokenValue(token, trackingLink, requestData, ua, geo), token.modifiers)
+
+			return value
+		},
+	}
+}
+
+// parseTokenExpr function builds urlToken from the token expression without braces ("p1|default:none").
+func parseTokenExpr(expr string) urlToken {
+	parts := strings.Split(strings.TrimSpace(expr), "|")
+
+	return urlToken{name: parts[0], modifiers: parts[1:]}
+}
@@ -0,0 +1,153 @@
+package interactor
+
+import (
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+// reservedParams are handled by the redirector itself, so they are not passed to the destination
+// unless explicitly listed in the allow-list. Ad network click identifiers are reserved as well,
+// they are forwarded according to the tracking link ForwardedAdClickIDs switches.
+var reservedParams = []string{"landing", "deeplink", p1Token, p2Token, p3Token, p4Token}
+
+// passThroughParams function merges incoming query params into the target URL according to the policy.
+// Params consumed by tokens of the URL template are skipped unless explicitly listed in the allow-list.
+func passThroughParams(
+	targetURL string,
+	policy valueobject.ParamsPassThrough,
+	params map[string][]string,
+	consumed map[string]bool,
+) string {
+	if !policy.IsEnabled() || len(params) == 0 {
+		return targetURL
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if isPassThroughAllowed(policy, key, consumed) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return targetURL
+	}
+	sort.Strings(keys)
+
+	base, fragment, hasFragment := strings.Cut(targetURL, "#")
+	path, rawQuery, _ := strings.Cut(base, "?")
+	pairs := splitRawQuery(rawQuery)
+
+	existing := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		existing[rawQueryKey(pair)] = true
+	}
+
+	passed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if existing[key] && policy.OnConflict != valueobject.OverrideConflictStrategy &&
+			policy.OnConflict != valueobject.AppendConflictStrategy {
+			continue
+		}
+
+		passed[key] = true
+	}
+
+	if policy.OnConflict == valueobject.OverrideConflictStrategy {
+		pairs = slices.DeleteFunc(pairs, func(pair string) bool {
+			return passed[rawQueryKey(pair)]
+		})
+	}
+
+	for _, key := range keys {
+		if !passed[key] {
+			continue
+		}
+
+		for _, value := range params[key] {
+			pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		}
+	}
+
+	result := path
+	if len(pairs) > 0 {
+		result += "?" + strings.Join(pairs, "&")
+	}
+	if hasFragment {
+		result += "#" + fragment
+	}
+
+	return result
+}
+
+// forwardAdClickIDs function appends ad network click identifiers enabled for the tracking link to the target URL.
+// Identifiers already present in the target URL are kept.
+func forwardAdClickIDs(targetURL string, forwarded map[string]bool, params map[string][]string) string {
+	enabled := make([]string, 0, len(forwarded))
+	for param, isForwarded := range forwarded {
+		if isForwarded && valueobject.IsAdClickID(param) {
+			enabled = append(enabled, param)
+		}
+	}
+
+	policy := valueobject.ParamsPassThrough{Mode: valueobject.AllowListPassThroughMode, Params: enabled}
+
+	return passThroughParams(targetURL, policy, params, nil)
+}
+
+// adClickIDs function collects known ad network click identifiers from the query params.
+func adClickIDs(params map[string][]string) map[string]string {
+	clickIDs := make(map[string]string)
+	for param, values := range params {
+		if valueobject.IsAdClickID(param) && len(values) > 0 && values[0] != "" {
+			clickIDs[param] = values[0]
+		}
+	}
+
+	return clickIDs
+}
+
+// isPassThroughAllowed function checks if the incoming query param might be passed to the destination.
+func isPassThroughAllowed(policy valueobject.ParamsPassThrough, key string, consumed map[string]bool) bool {
+	if policy.Mode == valueobject.AllowListPassThroughMode {
+		return slices.Contains(policy.Params, key)
+	}
+
+	if consumed[key] || slices.Contains(reservedParams, key) || valueobject.IsAdClickID(key) {
+		return false
+	}
+
+	switch policy.Mode {
+	case valueobject.AllPassThroughMode:
+		return true
+	case valueobject.DenyListPassThroughMode:
+		return !slices.Contains(policy.Params, key)
+	default:
+		return false
+	}
+}
+
+// splitRawQuery function splits the raw query string into "key=value" pairs keeping their order and encoding.
+func splitRawQuery(rawQuery string) []string {
+	pairs := make([]string, 0)
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+
+	return pairs
+}
+
+// rawQueryKey function returns the decoded key of the raw "key=value" pair.
+func rawQueryKey(pair string) string {
+	key, _, _ := strings.Cut(pair, "=")
+	if unescaped, err := url.QueryUnescape(key); err == nil {
+		return unescaped
+	}
+
+	return key
+}
@@ -0,0 +1,360 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lroman242/redirector/domain/dto"
+	"github.com/lroman242/redirector/domain/entity"
+	"github.com/lroman242/redirector/domain/repository"
+	"github.com/lroman242/redirector/domain/service"
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+// Conversion tokens available in postback URL templates in addition to the click tokens.
+const (
+	conversionIDToken  = "conversion_id"
+	transactionIDToken = "transaction_id"
+	eventToken         = "event"
+	payoutToken        = "payout"
+)
+
+const (
+	// defaultPostbackBatchSize is the number of postbacks claimed by a single dispatch.
+	defaultPostbackBatchSize = 100
+	// defaultPostbackMaxAttempts is the number of delivery attempts made before the postback is failed.
+	defaultPostbackMaxAttempts = 10
+	// defaultPostbackRetryBackoff is the delay before the first retry, doubled for every next one.
+	defaultPostbackRetryBackoff = 30 * time.Second
+	// defaultPostbackMaxRetryBackoff is the maximum delay between retries.
+	defaultPostbackMaxRetryBackoff = time.Hour
+	// postbackLease is the time claimed postbacks are hidden from other dispatchers while being delivered.
+	postbackLease = 5 * time.Minute
+)
+
+//go:generate mockgen -package=mocks -destination=mocks/mock_postback_interactor.go -source=postback_interactor.go PostbackInteractor
+
+// PostbackInteractor handles the business logic for delivering server-to-server postbacks to affiliate trackers.
+type PostbackInteractor interface {
+	// Enqueue renders the postback URL of the conversion affiliate and adds the postback to the delivery queue.
+	// Nothing is queued if the affiliate has no postback URL.
+	Enqueue(ctx context.Context, conversion *entity.Conversion) error
+	// Dispatch delivers due postbacks and returns the number of processed postbacks.
+	// Failed deliveries are retried with exponential backoff.
+	Dispatch(ctx context.Context) (int, error)
+	// Run dispatches postbacks periodically until the context is done.
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// postbackInteractor implements PostbackInteractor.
+type postbackInteractor struct {
+	postbacksRepository repository.PostbacksRepository
+	sender              service.PostbackSenderInterface
+	tokenRegExp         *regexp.Regexp
+	batchSize           int
+	maxAttempts         int
+	retryBackoff        time.Duration
+	maxRetryBackoff     time.Duration
+}
+
+// PostbackInteractorOption configures optional behaviour of the PostbackInteractor implementation.
+type PostbackInteractorOption func(*postbackInteractor)
+
+// WithPostbackBatchSize sets the number of postbacks claimed by a single dispatch.
+// Non-positive values are ignored and the default batch size is used.
+func WithPostbackBatchSize(batchSize int) PostbackInteractorOption {
+	return func(p *postbackInteractor) {
+		if batchSize > 0 {
+			p.batchSize = batchSize
+		}
+	}
+}
+
+// WithPostbackMaxAttempts sets the number of delivery attempts made before the postback is failed.
+// Non-positive values are ignored and the default number of attempts is used.
+func WithPostbackMaxAttempts(maxAttempts int) PostbackInteractorOption {
+	return func(p *postbackInteractor) {
+		if maxAttempts > 0 {
+			p.maxAttempts = maxAttempts
+		}
+	}
+}
+
+// WithPostbackRetryBackoff sets the delay before the first retry and the maximum delay between retries.
+// Non-positive values are ignored and the default delays are used.
+func WithPostbackRetryBackoff(backoff, maxBackoff time.Duration) PostbackInteractorOption {
+	return func(p *postbackInteractor) {
+		if backoff > 0 {
+			p.retryBackoff = backoff
+		}
+		if maxBackoff > 0 {
+			p.maxRetryBackoff = maxBackoff
+		}
+	}
+}
+
+// NewPostbackInteractor function creates PostbackInteractor implementation.
+func NewPostbackInteractor(
+	postbacksRepository repository.PostbacksRepository,
+	sender service.PostbackSenderInterface,
+	options ...PostbackInteractorOption,
+) PostbackInteractor {
+	p := &postbackInteractor{
+		postbacksRepository: postbacksRepository,
+		sender:              sender,
+		tokenRegExp:         regexp.MustCompile(tokenPattern),
+		batchSize:           defaultPostbackBatchSize,
+		maxAttempts:         defaultPostbackMaxAttempts,
+		retryBackoff:        defaultPostbackRetryBackoff,
+		maxRetryBackoff:     defaultPostbackMaxRetryBackoff,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return p
+}
+
+// Enqueue function adds the affiliate postback of the conversion to the delivery queue.
+// The postback shares the conversion ID, so the same conversion is never queued twice.
+func (p *postbackInteractor) Enqueue(ctx context.Context, conversion *entity.Conversion) error {
+	urlTemplate, err := p.postbacksRepository.FindPostbackURLTemplate(ctx, conversion.AffiliateID, conversion.SourceID)
+	if err != nil {
+		return fmt.Errorf("failed to find postback URL: %w", err)
+	}
+	if urlTemplate == "" {
+		return nil
+	}
+
+	now := time.Now()
+	postback := &entity.Postback{
+		ID:            conversion.ID,
+		ConversionID:  conversion.ID,
+		AffiliateID:   conversion.AffiliateID,
+		SourceID:      conversion.SourceID,
+		URL:           p.renderPostbackURL(urlTemplate, conversion),
+		Status:        valueobject.PendingPostbackStatus,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+
+	if err = p.postbacksRepository.Enqueue(ctx, postback); err != nil {
+		return fmt.Errorf("failed to enqueue postback: %w", err)
+	}
+
+	return nil
+}
+
+// Dispatch function claims due postbacks and makes a delivery attempt for each of them.
+func (p *postbackInteractor) Dispatch(ctx context.Context) (int, error) {
+	postbacks, err := p.postbacksRepository.Dequeue(ctx, time.Now(), p.batchSize, postbackLease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to dequeue postbacks: %w", err)
+	}
+
+	// storage failures of a single postback don't stop the batch, the claim expires and it is retried later
+	for _, postback := range postbacks {
+		p.deliver(ctx, postback)
+	}
+
+	return len(postbacks), nil
+}
+
+// Run function dispatches postbacks on every tick until the queue has no due postbacks.
+func (p *postbackInteractor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				processed, err := p.Dispatch(ctx)
+				if err != nil {
+					slog.Error("an error occurred while dispatching postbacks", slog.String("error", err.Error()))
+				}
+				if err != nil || processed < p.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// deliver function sends the postback, logs the attempt and schedules the retry if the delivery failed.
+// Delivery failures are recorded in the postback status, storage errors are logged.
+func (p *postbackInteractor) deliver(ctx context.Context, postback *entity.Postback) {
+	start := time.Now()
+	statusCode, err := p.sender.Send(ctx, postback.URL)
+	postback.Attempts++
+
+	attempt := &entity.PostbackAttempt{
+		PostbackID:   postback.ID,
+		ConversionID: postback.ConversionID,
+		Attempt:      postback.Attempts,
+		URL:          postback.URL,
+		StatusCode:   statusCode,
+		Duration:     time.Since(start),
+		CreatedAt:    start,
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	switch {
+	case err == nil && statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices:
+		postback.Status = valueobject.DeliveredPostbackStatus
+	case postback.Attempts >= p.maxAttempts || (err == nil && !isRetryablePostbackStatus(statusCode)):
+		postback.Status = valueobject.FailedPostbackStatus
+	default:
+		postback.NextAttemptAt = time.Now().Add(p.retryDelay(postback.Attempts))
+	}
+
+	slog.Info("postback attempt",
+		slog.String("postback_id", postback.ID),
+		slog.String("conversion_id", postback.ConversionID),
+		slog.Int("attempt", attempt.Attempt),
+		slog.Int("status_code", attempt.StatusCode),
+		slog.String("error", attempt.Error),
+		slog.String("status", postback.Status),
+		slog.String("duration", attempt.Duration.String()),
+	)
+
+	if err = p.postbacksRepository.SaveAttempt(ctx, attempt); err != nil {
+		slog.Error("failed to save postback attempt",
+			slog.String("postback_id", postback.ID),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	if err = p.postbacksRepository.Update(ctx, postback); err != nil {
+		slog.Error("failed to update postback",
+			slog.String("postback_id", postback.ID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// retryDelay function returns the delay before the next attempt, doubled after every failed attempt.
+func (p *postbackInteractor) retryDelay(attempts int) time.Duration {
+	delay := p.retryBackoff
+	for i := 1; i < attempts && delay < p.maxRetryBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.maxRetryBackoff)
+}
+
+// isRetryablePostbackStatus function reports whether the tracker might accept the postback later.
+// Client errors other than timeouts and rate limits are permanent.
+func isRetryablePostbackStatus(statusCode int) bool {
+	return statusCode >= http.StatusInternalServerError ||
+		statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusTooManyRequests
+}
+
+// renderPostbackURL function replaces tokens in the postback URL template with conversion and click values.
+// Tokens support the same modifiers and contextual encoding as tracking link URL tokens.
+func (p *postbackInteractor) renderPostbackURL(urlTemplate string, conversion *entity.Conversion) string {
+	matches := p.tokenRegExp.FindAllStringSubmatchIndex(urlTemplate, -1)
+
+	var sb strings.Builder
+	last := 0
+	for _, match := range matches {
+		sb.WriteString(urlTemplate[last:match[0]])
+		last = match[1]
+
+		token := parseToken(urlTemplate, match)
+		value, encoded := applyTokenModifiers(postbackTokenValue(token, conversion), token.modifiers)
+		if !token.raw && !encoded {
+			value = escapeTokenValue(value, tokenURLPart(urlTemplate, match[0]))
+		}
+
+		sb.WriteString(value)
+	}
+	sb.WriteString(urlTemplate[last:])
+
+	return sb.String()
+}
+
+// postbackTokenValue function returns the raw value of the postback token, undefined tokens produce empty string.
+// Click tokens are resolved the same way as for tracking links from the conversion and its click,
+// date tokens are resolved to the conversion time, so retries send the same values.
+func postbackTokenValue(token urlToken, conversion *entity.Conversion) string {
+	switch token.name {
+	case conversionIDToken:
+		return conversion.ID
+	case transactionIDToken:
+		return conversion.TransactionID
+	case eventToken:
+		return conversion.EventType
+	case payoutToken:
+		return strconv.FormatFloat(conversion.Payout, 'f', -1, 64)
+	}
+
+	trackingLink, requestData, ua, geo := conversionTokenSource(conversion)
+
+	return tokenValue(token, trackingLink, requestData, ua, geo)
+}
+
+// conversionTokenSource function rebuilds the click request from the conversion and its click (if it's loaded).
+// Dynamic tokens are not resolved, since the request params, headers and cookies are not stored.
+func conversionTokenSource(conversion *entity.Conversion) (
+	*entity.TrackingLink,
+	*dto.RedirectRequestData,
+	*valueobject.UserAgent,
+	*valueobject.GeoLocation,
+) {
+	trackingLink := &entity.TrackingLink{
+		CampaignID:   conversion.CampaignID,
+		AffiliateID:  conversion.AffiliateID,
+		SourceID:     conversion.SourceID,
+		AdvertiserID: conversion.AdvertiserID,
+	}
+	requestData := &dto.RedirectRequestData{
+		RequestID: conversion.ClickID,
+		Time:      conversion.CreatedAt,
+	}
+	ua := &valueobject.UserAgent{}
+	geo := &valueobject.GeoLocation{}
+
+	click := conversion.Click
+	if click == nil {
+		return trackingLink, requestData, ua, geo
+	}
+
+	requestData.IP = click.IP
+	requestData.UserAgent = click.Agent
+	requestData.Referer = click.Referer
+	requestData.Params = map[string][]string{
+		p1Token: {click.P1},
+		p2Token: {click.P2},
+		p3Token: {click.P3},
+		p4Token: {click.P4},
+	}
+	if click.Language != "" {
+		requestData.Headers = map[string][]string{"Accept-Language": {click.Language}}
+	}
+
+	ua.Device = click.Device
+	ua.Platform = click.Platform
+
+	geo.CountryCode = click.CountryCode
+	geo.Region = click.Region
+	geo.City = click.City
+	geo.ASN = click.ASN
+	geo.ASNOrganization = click.ASNOrganization
+	geo.ISP = click.ISP
+	geo.ConnectionType = click.ConnectionType
+
+	return trackingLink, requestData, ua, geo
+}
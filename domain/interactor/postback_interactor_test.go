@@ -0,0 +1,275 @@
+package interactor_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/lroman242/redirector/domain/entity"
+	"github.com/lroman242/redirector/domain/interactor"
+	"github.com/lroman242/redirector/domain/valueobject"
+	"github.com/lroman242/redirector/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPostbackInteractor_Enqueue(t *testing.T) {
+	conversion := &entity.Conversion{
+		ID:            "conv-1",
+		ClickID:       "click-1",
+		TransactionID: "tx 1",
+		EventType:     "sale",
+		Payout:        2.5,
+		SourceID:      "source-1",
+		CampaignID:    "campaign-1",
+		AffiliateID:   "affiliate-1",
+		AdvertiserID:  "advertiser-1",
+		CreatedAt:     time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		Click: &entity.Click{
+			ID:          "click-1",
+			IP:          net.ParseIP("203.0.113.1"),
+			CountryCode: countryCode,
+			Region:      "CA",
+			Device:      "Mobile",
+			Agent:       "Mozilla/5.0",
+			P1:          "aff click",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		urlTemplate string
+		findErr     error
+		enqueueErr  error
+		expectedURL string
+		expectedErr bool
+	}{
+		{
+			name:        "rendered template",
+			urlTemplate: "https://tracker.com/pb?sub={p1}&tx={transaction_id}&event={event}&payout={payout}&click={click_id}&conv={conversion_id}&geo={country_code}&date={date}&aff={aff_id}",
+			expectedURL: "https://tracker.com/pb?sub=aff+click&tx=tx+1&event=sale&payout=2.5&click=click-1&conv=conv-1&geo=" + countryCode + "&date=2025-03-01&aff=affiliate-1",
+		},
+		{
+			name:        "modifiers and path tokens",
+			urlTemplate: "https://tracker.com/{source_id}/{p2|default:none}?ip={ip}&ts={timestamp}&h={event|sha256}",
+			expectedURL: "https://tracker.com/source-1/none?ip=203.0.113.1&ts=1740823200&h=" + fmt.Sprintf("%x", sha256.Sum256([]byte("sale"))),
+		},
+		{
+			name:        "click tokens",
+			urlTemplate: "https://tracker.com/pb?region={region}&device={device}&ua={user_agent}&ref={referer|default:none}&x={param:x}",
+			expectedURL: "https://tracker.com/pb?region=CA&device=Mobile&ua=Mozilla%2F5.0&ref=none&x=",
+		},
+		{
+			name: "no template",
+		},
+		{
+			name:        "find template error",
+			findErr:     errors.New("db is down"),
+			expectedErr: true,
+		},
+		{
+			name:        "enqueue error",
+			urlTemplate: "https://tracker.com/pb?click={click_id}",
+			enqueueErr:  errors.New("db is down"),
+			expectedURL: "https://tracker.com/pb?click=click-1",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			postbacksRepo := mocks.NewMockPostbacksRepository(ctrl)
+			sender := mocks.NewMockPostbackSenderInterface(ctrl)
+
+			postbacksRepo.EXPECT().
+				FindPostbackURLTemplate(gomock.Any(), conversion.AffiliateID, conversion.SourceID).
+				Return(tt.urlTemplate, tt.findErr)
+			if tt.expectedURL != "" {
+				postbacksRepo.EXPECT().
+					Enqueue(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, postback *entity.Postback) error {
+						if postback.URL != tt.expectedURL {
+							t.Errorf("unexpected postback URL.\nexpected %s\ngot      %s", tt.expectedURL, postback.URL)
+						}
+						if postback.ID != conversion.ID || postback.ConversionID != conversion.ID {
+							t.Errorf("unexpected postback ids %s/%s", postback.ID, postback.ConversionID)
+						}
+						if postback.Status != valueobject.PendingPostbackStatus || postback.Attempts != 0 {
+							t.Errorf("unexpected postback state %s/%d", postback.Status, postback.Attempts)
+						}
+
+						return tt.enqueueErr
+					})
+			}
+
+			err := interactor.NewPostbackInteractor(postbacksRepo, sender).Enqueue(context.Background(), conversion)
+			if tt.expectedErr != (err != nil) {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestPostbackInteractor_Dispatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		attempts       int
+		statusCode     int
+		sendErr        error
+		expectedStatus string
+		expectedDelay  time.Duration
+	}{
+		{
+			name:           "delivered",
+			statusCode:     http.StatusOK,
+			expectedStatus: valueobject.DeliveredPostbackStatus,
+		},
+		{
+			name:           "server error is retried",
+			statusCode:     http.StatusServiceUnavailable,
+			expectedStatus: valueobject.PendingPostbackStatus,
+			expectedDelay:  time.Minute,
+		},
+		{
+			name:           "backoff grows exponentially",
+			attempts:       2,
+			sendErr:        errors.New("connection refused"),
+			expectedStatus: valueobject.PendingPostbackStatus,
+			expectedDelay:  4 * time.Minute,
+		},
+		{
+			name:           "backoff is capped",
+			attempts:       3,
+			statusCode:     http.StatusTooManyRequests,
+			expectedStatus: valueobject.PendingPostbackStatus,
+			expectedDelay:  5 * time.Minute,
+		},
+		{
+			name:           "client error is not retried",
+			statusCode:     http.StatusBadRequest,
+			expectedStatus: valueobject.FailedPostbackStatus,
+		},
+		{
+			name:           "out of attempts",
+			attempts:       4,
+			statusCode:     http.StatusInternalServerError,
+			expectedStatus: valueobject.FailedPostbackStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			postbacksRepo := mocks.NewMockPostbacksRepository(ctrl)
+			sender := mocks.NewMockPostbackSenderInterface(ctrl)
+
+			postback := &entity.Postback{
+				ID:           "conv-1",
+				ConversionID: "conv-1",
+				URL:          "https://tracker.com/pb?click=click-1",
+				Status:       valueobject.PendingPostbackStatus,
+				Attempts:     tt.attempts,
+			}
+
+			postbacksRepo.EXPECT().
+				Dequeue(gomock.Any(), gomock.Any(), 10, gomock.Any()).
+				Return([]*entity.Postback{postback}, nil)
+			sender.EXPECT().Send(gomock.Any(), postback.URL).Return(tt.statusCode, tt.sendErr)
+			postbacksRepo.EXPECT().
+				SaveAttempt(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, attempt *entity.PostbackAttempt) error {
+					if attempt.Attempt != tt.attempts+1 || attempt.StatusCode != tt.statusCode || attempt.URL != postback.URL {
+						t.Errorf("unexpected attempt %+v", attempt)
+					}
+					if (tt.sendErr != nil) != (attempt.Error != "") {
+						t.Errorf("unexpected attempt error %s", attempt.Error)
+					}
+
+					return nil
+				})
+
+			start := time.Now()
+			postbacksRepo.EXPECT().
+				Update(gomock.Any(), postback).
+				DoAndReturn(func(_ context.Context, postback *entity.Postback) error {
+					if postback.Status != tt.expectedStatus {
+						t.Errorf("unexpected status. expected %s got %s", tt.expectedStatus, postback.Status)
+					}
+					if postback.Attempts != tt.attempts+1 {
+						t.Errorf("unexpected attempts. expected %d got %d", tt.attempts+1, postback.Attempts)
+					}
+					if tt.expectedDelay > 0 {
+						delay := postback.NextAttemptAt.Sub(start)
+						if delay < tt.expectedDelay || delay > tt.expectedDelay+time.Second {
+							t.Errorf("unexpected retry delay. expected %s got %s", tt.expectedDelay, delay)
+						}
+					}
+
+					return nil
+				})
+
+			processed, err := interactor.NewPostbackInteractor(
+				postbacksRepo,
+				sender,
+				interactor.WithPostbackBatchSize(10),
+				interactor.WithPostbackMaxAttempts(5),
+				interactor.WithPostbackRetryBackoff(time.Minute, 5*time.Minute),
+			).Dispatch(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if processed != 1 {
+				t.Errorf("unexpected number of processed postbacks %d", processed)
+			}
+		})
+	}
+}
+
+func TestPostbackInteractor_Dispatch_Errors(t *testing.T) {
+	t.Run("dequeue error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		postbacksRepo := mocks.NewMockPostbacksRepository(ctrl)
+		postbacksRepo.EXPECT().
+			Dequeue(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("db is down"))
+		sender := mocks.NewMockPostbackSenderInterface(ctrl)
+
+		if _, err := interactor.NewPostbackInteractor(postbacksRepo, sender).Dispatch(context.Background()); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("update error doesn't stop the batch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		postbacksRepo := mocks.NewMockPostbacksRepository(ctrl)
+		sender := mocks.NewMockPostbackSenderInterface(ctrl)
+		postbacksRepo.EXPECT().
+			Dequeue(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*entity.Postback{
+				{ID: "conv-1", URL: "https://tracker.com/pb?c=1"},
+				{ID: "conv-2", URL: "https://tracker.com/pb?c=2"},
+			}, nil)
+		sender.EXPECT().Send(gomock.Any(), gomock.Any()).Return(http.StatusOK, nil).Times(2)
+		postbacksRepo.EXPECT().SaveAttempt(gomock.Any(), gomock.Any()).Return(errors.New("db is down")).Times(2)
+		postbacksRepo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, postback *entity.Postback) error {
+				if postback.ID == "conv-1" {
+					return errors.New("db is down")
+				}
+
+				return nil
+			}).
+			Times(2)
+
+		processed, err := interactor.NewPostbackInteractor(postbacksRepo, sender).Dispatch(context.Background())
+		if err != nil || processed != 2 {
+			t.Errorf("unexpected result %d, %v", processed, err)
+		}
+	})
+}
@@ -4,9 +4,11 @@ package interactor
 import (
 	"context"
 	"errors"
+	"hash/fnv"
 	"log/slog"
 	"math/rand"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,12 +24,31 @@ import (
 var (
 	// ErrUnsupportedProtocol is returned when the request protocol is not allowed.
 	ErrUnsupportedProtocol = errors.New("protocol is not allowed for that tracking link")
+	// ErrIPNotAllowed is returned when the visitor's IP address is blocked by the tracking link or global IP lists.
+	ErrIPNotAllowed = errors.New("visitor IP address is not allowed for that tracking link")
+	// ErrOutOfSchedule is returned when the request is received outside of the tracking link schedule.
+	ErrOutOfSchedule = errors.New("tracking link is out of schedule")
+	// ErrClickCapReached is returned when the tracking link or campaign click cap is reached
+	// and no overage redirect rules are set.
+	ErrClickCapReached = errors.New("click cap is reached for that tracking link")
+	// ErrDuplicateClick is returned when a duplicate click should be handled and no duplicate redirect rules are set.
+	ErrDuplicateClick = errors.New("duplicate clicks are not allowed for that tracking link")
+	// ErrBotsNotAllowed is returned when the visitor is detected as a bot and bots are not allowed.
+	ErrBotsNotAllowed = errors.New("bot traffic is not allowed for that tracking link")
 	// ErrUnsupportedGeo is returned when the visitor's geo location is not allowed.
 	ErrUnsupportedGeo = errors.New("visitor geo is not allowed for that tracking link")
+	// ErrUnsupportedRegion is returned when the visitor's region (state, province) is not allowed.
+	ErrUnsupportedRegion = errors.New("visitor region is not allowed for that tracking link")
+	// ErrUnsupportedCity is returned when the visitor's city is not allowed.
+	ErrUnsupportedCity = errors.New("visitor city is not allowed for that tracking link")
 	// ErrUnsupportedDevice is returned when the visitor's device type is not allowed.
 	ErrUnsupportedDevice = errors.New("visitor device is not allowed for that tracking link")
 	// ErrUnsupportedOS is returned when the visitor's operating system is not allowed.
 	ErrUnsupportedOS = errors.New("visitor OS is not allowed for that tracking link")
+	// ErrUnsupportedLanguage is returned when none of the visitor's languages is allowed.
+	ErrUnsupportedLanguage = errors.New("visitor language is not allowed for that tracking link")
+	// ErrDeeplinkNotAllowed is returned when the requested deeplink doesn't pass the tracking link scheme and domain checks.
+	ErrDeeplinkNotAllowed = errors.New("deeplink is not allowed for that tracking link")
 	// ErrInvalidRedirectType is returned when the redirect rules contain an invalid type.
 	ErrInvalidRedirectType = errors.New("invalid redirect type is stored in tracking link redirect rules")
 	// ErrBlockRedirect is returned when the redirect should be blocked.
@@ -38,29 +59,40 @@ var (
 	ErrTrackingLinkNotFound = errors.New("no tracking link was found by slug")
 	// ErrInvalidRedirectRules is returned when the redirect rules are not actually set in the tracking link.
 	ErrInvalidRedirectRules = errors.New("redirect rules are not set in tracking link")
+	// ErrRedirectLoop is returned when slug redirect rules point back to an already visited tracking link.
+	ErrRedirectLoop = errors.New("redirect rules produce a loop between tracking links")
+	// ErrRedirectDepthExceeded is returned when the chain of slug redirects is longer than allowed.
+	ErrRedirectDepthExceeded = errors.New("redirect chain exceeds maximum allowed depth")
 )
 
 const (
-	ipAddressToken    = "{ip}"
-	clickIDToken      = "{click_id}"
-	userAgentToken    = "{user_agent}"
-	campaignIDToken   = "{campaign_id}"
-	affiliateIDToken  = "{aff_id}"
-	sourceIDToken     = "{source_id}"
-	advertiserIDToken = "{advertiser_id}"
-	dateToken         = "{date}"
-	dateTimeToken     = "{date_time}"
-	timestampToken    = "{timestamp}"
-	p1Token           = "{p1}"
-	p2Token           = "{p2}"
-	p3Token           = "{p3}"
-	p4Token           = "{p4}"
-	countryCodeToken  = "{country_code}"
-	refererToken      = "{referer}"
-	randomStrToken    = "{random_str}"
-	randomIntToken    = "{random_int}"
-	deviceToken       = "{device}"
-	platformToken     = "{platform}"
+	ipAddressToken    = "ip"
+	clickIDToken      = "click_id"
+	userAgentToken    = "user_agent"
+	campaignIDToken   = "campaign_id"
+	affiliateIDToken  = "aff_id"
+	sourceIDToken     = "source_id"
+	advertiserIDToken = "advertiser_id"
+	dateToken         = "date"
+	dateTimeToken     = "date_time"
+	timestampToken    = "timestamp"
+	p1Token           = "p1"
+	p2Token           = "p2"
+	p3Token           = "p3"
+	p4Token           = "p4"
+	countryCodeToken  = "country_code"
+	refererToken      = "referer"
+	randomStrToken    = "random_str"
+	randomIntToken    = "random_int"
+	deviceToken       = "device"
+	platformToken     = "platform"
+	languageToken     = "language"
+	regionToken       = "region"
+	cityToken         = "city"
+	asnToken          = "asn"
+	asnOrgToken       = "asn_org"
+	ispToken          = "isp"
+	connectionToken   = "connection_type"
 
 	unknownStrValue = "unknown"
 
@@ -68,8 +100,30 @@ const (
 
 	randomMinInt = 10000
 	randomMaxInt = 99999999
+
+	// IPUserAgentUniqueClickKey identifies visitors by IP address and User-Agent while detecting duplicate clicks.
+	IPUserAgentUniqueClickKey = "ip_ua"
+	// VisitorUniqueClickKey identifies visitors by the visitor cookie (IP address and User-Agent if it's not set)
+	// while detecting duplicate clicks.
+	VisitorUniqueClickKey = "visitor"
+
+	// defaultMaxRedirectDepth is the maximum number of slug hops allowed when no other value is configured.
+	defaultMaxRedirectDepth = 5
+
+	// clickProcessingTimeout limits the time click handlers might spend on a single click.
+	clickProcessingTimeout = 10 * time.Second
 )
 
+// redirectChainKey is the context key used to store the list of already visited slugs.
+type redirectChainKey struct{}
+
+// uniqueClickKey is the context key used to store the uniqueness of the click being processed.
+type uniqueClickKey struct{}
+
+// uniqueClickDetectorKeysKey is the context key used to store the unique click detector keys of the visited slugs,
+// they are remembered once the click is registered.
+type uniqueClickDetectorKeysKey struct{}
+
 //go:generate mockgen -package=mocks -destination=mocks/mock_redirect_interactor.go -source=redirect_interactor.go RedirectInteractor
 
 // RedirectInteractor handles the business logic for processing redirect requests.
@@ -90,6 +144,70 @@ type redirectInteractor struct {
 	userAgentParser         service.UserAgentParserInterface
 	tokenRegExp             *regexp.Regexp
 	clickHandlers           []ClickHandlerInterface
+	clickCapsRepository     repository.ClickCapsRepository
+	ipListsRepository       repository.IPListsRepository
+	uniqueClickDetector     service.UniqueClickDetectorInterface
+	uniqueClickKeyType      string
+	maxRedirectDepth        int
+	fallbackURL             string
+	fallbackURLs            map[string]string
+}
+
+// RedirectInteractorOption configures optional behaviour of the RedirectInteractor implementation.
+type RedirectInteractorOption func(*redirectInteractor)
+
+// WithMaxRedirectDepth sets the maximum number of slug hops allowed while processing a single request.
+// Non-positive values are ignored and the default depth is used.
+func WithMaxRedirectDepth(depth int) RedirectInteractorOption {
+	return func(r *redirectInteractor) {
+		if depth > 0 {
+			r.maxRedirectDepth = depth
+		}
+	}
+}
+
+// WithFallbackURL sets the URL used when a redirect chain is rejected because of a loop
+// or because it exceeds the maximum depth, or the redirect rules are invalid. It is the last resort URL
+// of the misconfigured error class, so advertiser URLs and URLs set by WithErrorFallbackURLs take precedence.
+// When empty, the error is returned instead.
+func WithFallbackURL(fallbackURL string) RedirectInteractorOption {
+	return func(r *redirectInteractor) {
+		r.fallbackURL = fallbackURL
+	}
+}
+
+// WithErrorFallbackURLs sets URLs used instead of errors, keyed by the error class (see ErrorClass).
+// Advertiser fallback URLs of the tracking link take precedence over these ones.
+func WithErrorFallbackURLs(fallbackURLs map[string]string) RedirectInteractorOption {
+	return func(r *redirectInteractor) {
+		r.fallbackURLs = fallbackURLs
+	}
+}
+
+// WithClickCaps enables click caps enforcement using the provided counters storage.
+// Click caps are ignored when the option is not set.
+func WithClickCaps(clickCapsRepository repository.ClickCapsRepository) RedirectInteractorOption {
+	return func(r *redirectInteractor) {
+		r.clickCapsRepository = clickCapsRepository
+	}
+}
+
+// WithGlobalIPLists enables IP allow/deny lists applied to all tracking links.
+// Only tracking link lists are checked when the option is not set.
+func WithGlobalIPLists(ipListsRepository repository.IPListsRepository) RedirectInteractorOption {
+	return func(r *redirectInteractor) {
+		r.ipListsRepository = ipListsRepository
+	}
+}
+
+// WithUniqueClickDetector enables detection of duplicate clicks using the provided detector.
+// Key type defines how visitors are identified (IPUserAgentUniqueClickKey or VisitorUniqueClickKey).
+// All clicks are considered unique when the option is not set.
+func WithUniqueClickDetector(detector service.UniqueClickDetectorInterface, keyType string) RedirectInteractorOption {
+	return func(r *redirectInteractor) {
+		r.uniqueClickDetector = detector
+		r.uniqueClickKeyType = keyType
+	}
 }
 
 // NewRedirectInteractor function creates RedirectInteractor implementation.
@@ -98,25 +216,52 @@ func NewRedirectInteractor(
 	ipAddressParser service.IPAddressParserInterface,
 	userAgentParser service.UserAgentParserInterface,
 	clickHandlers []ClickHandlerInterface,
+	options ...RedirectInteractorOption,
 ) RedirectInteractor {
-	compiledRegExp := regexp.MustCompile(`{({)?(\w+)(})?}`)
+	compiledRegExp := regexp.MustCompile(tokenPattern)
 
-	return &redirectInteractor{
+	r := &redirectInteractor{
 		trackingLinksRepository: trkRepo,
 		ipAddressParser:         ipAddressParser,
 		userAgentParser:         userAgentParser,
 		tokenRegExp:             compiledRegExp,
 		clickHandlers:           clickHandlers,
+		maxRedirectDepth:        defaultMaxRedirectDepth,
 	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
 }
 
 // Redirect function handles requests and returns the target URL to redirect traffic to.
+// Rejected traffic is sent to the fallback URL of the error class when one is defined.
 func (r *redirectInteractor) Redirect(
 	ctx context.Context,
 	slug string,
 	requestData *dto.RedirectRequestData,
 ) (*dto.RedirectResult, error) {
 	trackingLink := r.trackingLinksRepository.FindTrackingLink(ctx, slug)
+
+	result, err := r.redirect(ctx, slug, trackingLink, requestData)
+	if err != nil {
+		if fallback := r.fallbackResult(trackingLink, err); fallback != nil {
+			return fallback, nil
+		}
+	}
+
+	return result, err
+}
+
+// redirect function applies tracking link rules and restrictions to the request.
+func (r *redirectInteractor) redirect(
+	ctx context.Context,
+	slug string,
+	trackingLink *entity.TrackingLink,
+	requestData *dto.RedirectRequestData,
+) (*dto.RedirectResult, error) {
 	if trackingLink == nil {
 		return nil, ErrTrackingLinkNotFound
 	}
@@ -125,95 +270,113 @@ func (r *redirectInteractor) Redirect(
 		return nil, ErrTrackingLinkDisabled
 	}
 
-	if len(trackingLink.AllowedProtocols) > 0 && !trackingLink.AllowedProtocols[requestData.Protocol] {
-		return nil, ErrUnsupportedProtocol
+	// IP lists are checked before the visitor lookup, so blocked traffic doesn't waste parsing resources
+	if !r.isIPAllowed(ctx, trackingLink, requestData) {
+		rr := trackingLink.CampaignIPRedirectRules
+		if rr == nil || rr.RedirectType == valueobject.NoRedirectType {
+			return r.handleRedirectRules(ctx, rr, requestData, trackingLink, nil, nil, ErrIPNotAllowed)
+		}
+
+		geo, ua := r.parseGeo(requestData), r.parseUserAgent(requestData)
+
+		return r.handleRedirectRules(ctx, rr, requestData, trackingLink, geo, ua, ErrIPNotAllowed)
 	}
 
-	countryCode, err := r.ipAddressParser.Parse(requestData.IP)
-	if err != nil {
-		slog.Error("an error occurred while parsing ip address", "ip", requestData.IP, "error", err)
-		countryCode = unknownStrValue
+	geo := r.parseGeo(requestData)
+	ua := r.parseUserAgent(requestData)
+
+	unique, detectorKey := r.detectUniqueClick(ctx, trackingLink, requestData)
+	ctx = context.WithValue(ctx, uniqueClickKey{}, unique)
+	if detectorKey != "" {
+		keys := append(slices.Clone(uniqueClickDetectorKeys(ctx)), detectorKey)
+		ctx = context.WithValue(ctx, uniqueClickDetectorKeysKey{}, keys)
 	}
 
-	ua, err := r.userAgentParser.Parse(requestData.UserAgent)
-	if err != nil {
-		slog.Error("an error occurred while parsing user-agent header", "user-agent", requestData.UserAgent, "error", err)
-		ua = &valueobject.UserAgent{
-			SrcString: requestData.UserAgent,
-			Device:    unknownStrValue,
-			Platform:  unknownStrValue,
-			Browser:   unknownStrValue,
+	attrs := makeTargetingAttributes(requestData, ua, geo, unique)
+	for _, rule := range compileTargetingRules(trackingLink) {
+		if rule.Matches(attrs) {
+			result, err := r.handleRedirectRules(ctx, rule.Action, requestData, trackingLink, geo, ua, rule.err)
+			if result != nil && result.Reason == nil {
+				result.Reason = rule.err
+			}
+
+			return result, err
 		}
 	}
 
-	if len(trackingLink.AllowedGeos) > 0 && !trackingLink.AllowedGeos[countryCode] {
+	if r.isClickCapReached(ctx, trackingLink, attrs.Time) {
 		return r.handleRedirectRules(
 			ctx,
-			trackingLink.CampaignGeoRedirectRules,
+			trackingLink.CampaignOverageRedirectRules,
 			requestData,
 			trackingLink,
-			countryCode,
+			geo,
 			ua,
-			ErrUnsupportedGeo,
+			ErrClickCapReached,
 		)
 	}
 
-	if len(trackingLink.AllowedDevices) > 0 && !trackingLink.AllowedDevices[ua.Device] {
-		return r.handleRedirectRules(
-			ctx,
-			trackingLink.CampaignDevicesRedirectRules,
-			requestData,
-			trackingLink,
-			countryCode,
-			ua,
-			ErrUnsupportedDevice,
-		)
+	targetURLTemplate, landingID := r.makeRedirectTemplate(trackingLink, requestData)
+	targetURL := r.renderTokens(targetURLTemplate, trackingLink, requestData, ua, geo)
+	outputCh := r.registerClick(ctx, slug, targetURL, landingID, trackingLink, requestData, ua, geo)
+
+	return &dto.RedirectResult{
+		TargetURL:    targetURL,
+		OutputCh:     outputCh,
+		Method:       trackingLink.RedirectMethod,
+		Cloaked:      trackingLink.CloakReferrer,
+		Interstitial: r.makeInterstitial(trackingLink, requestData, ua, geo),
+	}, nil
+}
+
+// parseGeo function resolves the visitor's geo location, unknown location is returned on failure.
+func (r *redirectInteractor) parseGeo(requestData *dto.RedirectRequestData) *valueobject.GeoLocation {
+	geo, err := r.ipAddressParser.Parse(requestData.IP)
+	if err != nil {
+		slog.Error("an error occurred while parsing ip address", "ip", requestData.IP, "error", err)
+		return &valueobject.GeoLocation{CountryCode: unknownStrValue}
 	}
 
-	if len(trackingLink.AllowedOS) > 0 && !trackingLink.AllowedOS[ua.Platform] {
-		return r.handleRedirectRules(
-			ctx,
-			trackingLink.CampaignOSRedirectRules,
-			requestData,
-			trackingLink,
-			countryCode,
-			ua,
-			ErrUnsupportedOS,
-		)
+	return geo
+}
+
+// parseUserAgent function parses the visitor's User-Agent header, unknown values are used on failure.
+func (r *redirectInteractor) parseUserAgent(requestData *dto.RedirectRequestData) *valueobject.UserAgent {
+	ua, err := r.userAgentParser.Parse(requestData.UserAgent)
+	if err != nil {
+		slog.Error("an error occurred while parsing user-agent header", "user-agent", requestData.UserAgent, "error", err)
+		return &valueobject.UserAgent{
+			SrcString: requestData.UserAgent,
+			Device:    unknownStrValue,
+			Platform:  unknownStrValue,
+			Browser:   unknownStrValue,
+		}
 	}
 
-	if trackingLink.IsCampaignOveraged {
-		return r.handleRedirectRules(
-			ctx,
-			trackingLink.CampaignOverageRedirectRules,
-			requestData,
-			trackingLink,
-			countryCode,
-			ua,
-			nil,
-		)
+	return ua
+}
+
+// isIPAllowed function checks the visitor's IP address against tracking link and global IP lists.
+// Tracking link lists take precedence over global ones, allowed networks take precedence over denied ones.
+func (r *redirectInteractor) isIPAllowed(
+	ctx context.Context,
+	trackingLink *entity.TrackingLink,
+	requestData *dto.RedirectRequestData,
+) bool {
+	linkLists := valueobject.IPLists{Allowed: trackingLink.AllowedIPs, Denied: trackingLink.DeniedIPs}
+	if matched, allowed := linkLists.Check(requestData.IP); matched {
+		return allowed
 	}
 
-	if !trackingLink.IsCampaignActive {
-		return r.handleRedirectRules(
-			ctx,
-			trackingLink.CampaignDisabledRedirectRules,
-			requestData,
-			trackingLink,
-			countryCode,
-			ua,
-			nil,
-		)
+	if r.ipListsRepository == nil {
+		return true
 	}
 
-	targetURLTemplate := r.makeRedirectTemplate(trackingLink, requestData)
-	targetURL := r.renderTokens(targetURLTemplate, trackingLink, requestData, ua, countryCode)
-	outputCh := r.registerClick(ctx, slug, targetURL, trackingLink, requestData, ua, countryCode)
+	if matched, allowed := r.ipListsRepository.FindGlobalIPLists(ctx).Check(requestData.IP); matched {
+		return allowed
+	}
 
-	return &dto.RedirectResult{
-		TargetURL: targetURL,
-		OutputCh:  outputCh,
-	}, nil
+	return true
 }
 
 func (r *redirectInteractor) handleRedirectRules(
@@ -221,7 +384,7 @@ func (r *redirectInteractor) handleRedirectRules(
 	rr *valueobject.RedirectRules,
 	requestData *dto.RedirectRequestData,
 	trackingLink *entity.TrackingLink,
-	countryCode string,
+	geo *valueobject.GeoLocation,
 	userAgent *valueobject.UserAgent,
 	err error,
 ) (*dto.RedirectResult, error) {
@@ -235,18 +398,57 @@ func (r *redirectInteractor) handleRedirectRules(
 
 	switch rr.RedirectType {
 	case valueobject.LinkRedirectType:
+		// the click is counted against the caps, unless it is already redirected by the overage rules
+		if !errors.Is(err, ErrClickCapReached) && r.isClickCapReached(ctx, trackingLink, requestTime(requestData)) {
+			return r.handleRedirectRules(
+				ctx,
+				trackingLink.CampaignOverageRedirectRules,
+				requestData,
+				trackingLink,
+				geo,
+				userAgent,
+				ErrClickCapReached,
+			)
+		}
+
 		return &dto.RedirectResult{
 			TargetURL: rr.RedirectURL,
-			OutputCh:  r.registerClick(ctx, requestData.Slug, rr.RedirectURL, trackingLink, requestData, userAgent, countryCode),
+			OutputCh:  r.registerClick(ctx, requestData.Slug, rr.RedirectURL, "", trackingLink, requestData, userAgent, geo),
+			Method:    redirectMethod(rr, trackingLink),
+			Cloaked:   trackingLink.CloakReferrer,
 		}, nil
 	case valueobject.SlugRedirectType:
-		ctx = context.WithValue(ctx, "slug", requestData.Slug)
-		return r.Redirect(ctx, rr.RedirectSlug, requestData)
+		result, slugErr := r.redirectToSlug(ctx, rr.RedirectSlug, requestData, trackingLink)
+
+		return overrideRedirectMethod(result, rr), slugErr
 	case valueobject.SmartSlugRedirectType:
-		rnd := rand.New(rand.NewSource(time.Now().Unix()))
-		newSlug := rr.RedirectSmartSlug[rnd.Intn(len(rr.RedirectSmartSlug))]
-		ctx = context.WithValue(ctx, "slug", requestData.Slug)
-		return r.Redirect(ctx, newSlug, requestData)
+		newSlug := r.pickSmartSlug(rr, trackingLink, requestData)
+		if newSlug == "" {
+			return nil, ErrInvalidRedirectRules
+		}
+
+		result, slugErr := r.redirectToSlug(ctx, newSlug, requestData, trackingLink)
+
+		return overrideRedirectMethod(result, rr), slugErr
+	case valueobject.NoClickType:
+		targetURL := rr.RedirectURL
+		if targetURL == "" {
+			targetURLTemplate, _ := r.makeRedirectTemplate(trackingLink, requestData)
+			targetURL = r.renderTokens(
+				targetURLTemplate,
+				trackingLink,
+				requestData,
+				userAgent,
+				geo,
+			)
+		}
+
+		return &dto.RedirectResult{
+			TargetURL: targetURL,
+			OutputCh:  skipClick(),
+			Method:    redirectMethod(rr, trackingLink),
+			Cloaked:   trackingLink.CloakReferrer,
+		}, nil
 	case valueobject.NoRedirectType:
 		if err != nil {
 			return nil, err
@@ -258,146 +460,445 @@ func (r *redirectInteractor) handleRedirectRules(
 	}
 }
 
-func (r *redirectInteractor) makeRedirectTemplate(
+// detectUniqueClick function checks if the visitor hasn't clicked the tracking link during the deduplication window
+// and returns the detector key of the click. The key is remembered only when the click is registered,
+// so traffic rejected by the tracking link doesn't open the deduplication window.
+// Detector errors are logged and the click is considered unique.
+func (r *redirectInteractor) detectUniqueClick(
+	ctx context.Context,
+	trackingLink *entity.TrackingLink,
+	requestData *dto.RedirectRequestData,
+) (bool, string) {
+	if r.uniqueClickDetector == nil {
+		return true, ""
+	}
+
+	visitorKey := requestData.IP.String() + "|" + requestData.UserAgent
+	if r.uniqueClickKeyType == VisitorUniqueClickKey {
+		visitorKey = requestData.VisitorKey()
+	}
+
+	key := trackingLink.Slug + "|" + visitorKey
+
+	unique, err := r.uniqueClickDetector.IsUnique(ctx, key)
+	if err != nil {
+		slog.Error("an error occurred while detecting unique click", "slug", trackingLink.Slug, "error", err)
+		return true, key
+	}
+
+	return unique, key
+}
+
+// rememberUniqueClick function remembers detector keys of all tracking links the registered click passed through.
+// Detector errors are logged.
+func (r *redirectInteractor) rememberUniqueClick(ctx context.Context) {
+	for _, key := range uniqueClickDetectorKeys(ctx) {
+		if err := r.uniqueClickDetector.Remember(ctx, key); err != nil {
+			slog.Error("an error occurred while remembering unique click", "error", err)
+		}
+	}
+}
+
+// uniqueClickDetectorKeys function returns the unique click detector keys stored in the context.
+func uniqueClickDetectorKeys(ctx context.Context) []string {
+	if keys, ok := ctx.Value(uniqueClickDetectorKeysKey{}).([]string); ok {
+		return keys
+	}
+
+	return nil
+}
+
+// isUniqueClick function returns the uniqueness of the click stored in the context.
+// Clicks are considered unique if the value is not set.
+func isUniqueClick(ctx context.Context) bool {
+	unique, ok := ctx.Value(uniqueClickKey{}).(bool)
+
+	return !ok || unique
+}
+
+// isClickCapReached function counts the click against the tracking link and campaign caps
+// and reports whether any of the caps is already reached.
+// Counters storage errors are logged and the click is not capped.
+func (r *redirectInteractor) isClickCapReached(
+	ctx context.Context,
+	trackingLink *entity.TrackingLink,
+	at time.Time,
+) bool {
+	if r.clickCapsRepository == nil || (trackingLink.ClickCaps.IsEmpty() && trackingLink.CampaignClickCaps.IsEmpty()) {
+		return false
+	}
+
+	reserved, err := r.clickCapsRepository.Reserve(ctx, trackingLink, at)
+	if err != nil {
+		slog.Error("an error occurred while counting click caps", "slug", trackingLink.Slug, "error", err)
+		return false
+	}
+
+	return !reserved
+}
+
+// normalizeProtocol function converts protocol/scheme representations ("HTTP/1.1", "https://", "HTTPS")
+// to the lower-cased scheme name.
+func normalizeProtocol(protocol string) string {
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	if i := strings.IndexAny(protocol, "/:"); i >= 0 {
+		protocol = protocol[:i]
+	}
+
+	return protocol
+}
+
+// pickSmartSlug function selects one of the smart slug targets according to their weights.
+// Sticky rules map the same visitor to the same target as long as the targets and weights are unchanged.
+func (r *redirectInteractor) pickSmartSlug(
+	rr *valueobject.RedirectRules,
 	trackingLink *entity.TrackingLink,
 	requestData *dto.RedirectRequestData,
 ) string {
-	targetURL := trackingLink.TargetURLTemplate
+	total := rr.RedirectSmartSlug.TotalWeight()
+	if total <= 0 {
+		return ""
+	}
+
+	if !rr.StickySmartSlug {
+		return rr.RedirectSmartSlug.Pick(rand.Intn(total))
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(trackingLink.Slug + "|" + requestData.VisitorKey()))
+
+	return rr.RedirectSmartSlug.Pick(int(h.Sum64() % uint64(total)))
+}
+
+// redirectToSlug function continues request processing with the tracking link identified by slug.
+// The chain of visited slugs is kept in the context, so loops and too long chains are rejected
+// before the next tracking link is loaded.
+func (r *redirectInteractor) redirectToSlug(
+	ctx context.Context,
+	slug string,
+	requestData *dto.RedirectRequestData,
+	trackingLink *entity.TrackingLink,
+) (*dto.RedirectResult, error) {
+	visited := redirectChain(ctx)
+	chain := make([]string, len(visited), len(visited)+1)
+	copy(chain, visited)
+	chain = append(chain, trackingLink.Slug)
+
+	var err error
+	if slices.Contains(chain, slug) {
+		err = ErrRedirectLoop
+	} else if len(chain) > r.maxRedirectDepth {
+		err = ErrRedirectDepthExceeded
+	}
+
+	if err != nil {
+		slog.Error("redirect chain rejected",
+			slog.String("slug", slug),
+			slog.Any("chain", chain),
+			slog.String("error", err.Error()),
+		)
+
+		return nil, err
+	}
+
+	return r.Redirect(context.WithValue(ctx, redirectChainKey{}, chain), slug, requestData)
+}
+
+// redirectMethod function returns the redirect method of the rules, the tracking link method is used if not set.
+func redirectMethod(rr *valueobject.RedirectRules, trackingLink *entity.TrackingLink) string {
+	if rr.RedirectMethod != "" {
+		return rr.RedirectMethod
+	}
 
-	if landingURL, paramExists := requestData.Params["landing"]; paramExists {
-		if landing, landingExists := trackingLink.LandingPages[landingURL[0]]; landingExists {
+	return trackingLink.RedirectMethod
+}
+
+// overrideRedirectMethod function replaces the redirect method of the result with the one set in the rules.
+func overrideRedirectMethod(result *dto.RedirectResult, rr *valueobject.RedirectRules) *dto.RedirectResult {
+	if result != nil && rr.RedirectMethod != "" {
+		result.Method = rr.RedirectMethod
+	}
+
+	return result
+}
+
+// redirectChain function returns the list of slugs visited before the current tracking link.
+func redirectChain(ctx context.Context) []string {
+	if chain, ok := ctx.Value(redirectChainKey{}).([]string); ok {
+		return chain
+	}
+
+	return nil
+}
+
+// makeRedirectTemplate function selects the URL template the visitor is redirected to together with the landing page ID.
+// The landing page requested with "landing" param is used when it exists, otherwise active landing pages are rotated
+// by weight. A valid deeplink takes precedence over landing pages.
+func (r *redirectInteractor) makeRedirectTemplate(
+	trackingLink *entity.TrackingLink,
+	requestData *dto.RedirectRequestData,
+) (targetURL, landingID string) {
+	targetURL = trackingLink.TargetURLTemplate
+
+	landingParam, paramExists := requestData.Params["landing"]
+	if paramExists && len(landingParam) > 0 {
+		if landing, landingExists := trackingLink.LandingPages[landingParam[0]]; landingExists {
 			targetURL = landing.TargetURL
+			landingID = landingParam[0]
 		}
 	}
 
-	if deeplinkURL, ok := requestData.Params["deeplink"]; ok && trackingLink.AllowDeeplink {
-		targetURL = deeplinkURL[0]
+	if deeplink := requestedDeeplink(trackingLink, requestData.Params); deeplink != "" {
+		if validateDeeplink(trackingLink, deeplink) == nil {
+			return deeplink, landingID
+		}
+	}
+
+	if !paramExists {
+		if id, landing := pickLandingPage(trackingLink.LandingPages, rand.Intn); landing != nil {
+			targetURL = landing.TargetURL
+			landingID = id
+		}
+	}
+
+	return targetURL, landingID
+}
+
+// pickLandingPage function selects one of the active landing pages according to their weights.
+// Landing pages are iterated in the order of their IDs, so the same roll always selects the same page.
+func pickLandingPage(
+	landingPages map[string]*entity.LandingPage,
+	roll func(n int) int,
+) (string, *entity.LandingPage) {
+	ids := make([]string, 0, len(landingPages))
+	total := 0
+	for id, landing := range landingPages {
+		if landing == nil || !landing.IsActive || landing.Weight <= 0 {
+			continue
+		}
+
+		ids = append(ids, id)
+		total += landing.Weight
+	}
+
+	if total <= 0 {
+		return "", nil
+	}
+
+	slices.Sort(ids)
+
+	n := roll(total)
+	for _, id := range ids {
+		n -= landingPages[id].Weight
+		if n < 0 {
+			return id, landingPages[id]
+		}
 	}
 
-	return targetURL
+	return "", nil
 }
 
+// renderTokens function replaces tokens in the URL template with request values.
+// Tokens might contain a pipeline of modifiers (e.g. "{referer|urlencode}", "{p1|default:none}").
+// Values are encoded according to the token position in the URL (path, query or fragment),
+// unless the raw form ("{{user_agent}}") or an explicit encoding modifier is used.
+// Incoming query params are merged into the result according to the tracking link pass-through policy
+// and ad network click identifiers enabled for the tracking link are forwarded.
 func (r *redirectInteractor) renderTokens(
 	targetURL string,
 	trackingLink *entity.TrackingLink,
 	requestData *dto.RedirectRequestData,
 	ua *valueobject.UserAgent,
-	countryCode string,
+	geo *valueobject.GeoLocation,
 ) string {
-	tokens := r.tokenRegExp.FindAllString(targetURL, -1)
-	for _, token := range tokens {
-		switch token {
-		case ipAddressToken:
-			targetURL = strings.ReplaceAll(targetURL, token, requestData.IP.String())
-		case clickIDToken:
-			targetURL = strings.ReplaceAll(targetURL, token, requestData.RequestID)
-		case userAgentToken:
-			targetURL = strings.ReplaceAll(targetURL, token, requestData.UserAgent)
-		case campaignIDToken:
-			targetURL = strings.ReplaceAll(targetURL, token, trackingLink.CampaignID)
-		case affiliateIDToken:
-			targetURL = strings.ReplaceAll(targetURL, token, trackingLink.AffiliateID)
-		case sourceIDToken:
-			targetURL = strings.ReplaceAll(targetURL, token, trackingLink.SourceID)
-		case advertiserIDToken:
-			targetURL = strings.ReplaceAll(targetURL, token, trackingLink.AdvertiserID)
-		case dateToken:
-			targetURL = strings.ReplaceAll(targetURL, token, time.Now().Format("2006-01-02"))
-		case dateTimeToken:
-			targetURL = strings.ReplaceAll(targetURL, token, time.Now().Format("2006-01-02T15:04:05"))
-		case timestampToken:
-			targetURL = strings.ReplaceAll(targetURL, token, strconv.FormatInt(time.Now().Unix(), 10))
-		case p1Token:
-			values := requestData.GetParam("p1")
-			targetURL = strings.ReplaceAll(targetURL, token, strings.Join(values, ","))
-		case p2Token:
-			values := requestData.GetParam("p2")
-			targetURL = strings.ReplaceAll(targetURL, token, strings.Join(values, ","))
-		case p3Token:
-			values := requestData.GetParam("p3")
-			targetURL = strings.ReplaceAll(targetURL, token, strings.Join(values, ","))
-		case p4Token:
-			values := requestData.GetParam("p4")
-			targetURL = strings.ReplaceAll(targetURL, token, strings.Join(values, ","))
-		case countryCodeToken:
-			targetURL = strings.ReplaceAll(targetURL, token, countryCode)
-		case refererToken:
-			targetURL = strings.ReplaceAll(targetURL, token, requestData.Referer)
-		case randomStrToken:
-			targetURL = strings.ReplaceAll(targetURL, token, randString(randomStringLen))
-		case randomIntToken:
-			targetURL = strings.ReplaceAll(targetURL, token, strconv.Itoa(rand.Intn(randomMaxInt-randomMinInt)+randomMinInt))
-		case deviceToken:
-			targetURL = strings.ReplaceAll(targetURL, token, ua.Device)
-		case platformToken:
-			targetURL = strings.ReplaceAll(targetURL, token, ua.Platform)
-
-		// replace undefined tokens with empty string
-		default:
-			targetURL = strings.ReplaceAll(targetURL, token, "")
+	matches := r.tokenRegExp.FindAllStringSubmatchIndex(targetURL, -1)
+	consumed := make(map[string]bool)
+
+	var sb strings.Builder
+	last := 0
+	for _, match := range matches {
+		sb.WriteString(targetURL[last:match[0]])
+		last = match[1]
+
+		token := parseToken(targetURL, match)
+		if param := token.param(); param != "" {
+			consumed[param] = true
+		}
+
+		value := tokenValue(token, trackingLink, requestData, ua, geo)
+		value, encoded := applyTokenModifiers(value, token.modifiers)
+		if !token.raw && !encoded {
+			value = escapeTokenValue(value, tokenURLPart(targetURL, match[0]))
 		}
+
+		sb.WriteString(value)
 	}
+	sb.WriteString(targetURL[last:])
 
-	//TODO: append gclid query param if present in requestData.Params
+	renderedURL := passThroughParams(sb.String(), trackingLink.ParamsPassThrough, requestData.Params, consumed)
 
-	return targetURL
+	return forwardAdClickIDs(renderedURL, trackingLink.ForwardedAdClickIDs, requestData.Params)
+}
+
+// tokenValue function returns the raw value of the token, undefined tokens produce empty string.
+// Date tokens are resolved to the request time.
+func tokenValue(
+	token urlToken,
+	trackingLink *entity.TrackingLink,
+	requestData *dto.RedirectRequestData,
+	ua *valueobject.UserAgent,
+	geo *valueobject.GeoLocation,
+) string {
+	switch token.name {
+	case ipAddressToken:
+		if requestData.IP == nil {
+			return ""
+		}
+
+		return requestData.IP.String()
+	case clickIDToken:
+		return requestData.RequestID
+	case userAgentToken:
+		return requestData.UserAgent
+	case campaignIDToken:
+		return trackingLink.CampaignID
+	case affiliateIDToken:
+		return trackingLink.AffiliateID
+	case sourceIDToken:
+		return trackingLink.SourceID
+	case advertiserIDToken:
+		return trackingLink.AdvertiserID
+	case dateToken:
+		return token.formatTime(requestTime(requestData), "2006-01-02")
+	case dateTimeToken:
+		return token.formatTime(requestTime(requestData), "2006-01-02T15:04:05")
+	case timestampToken:
+		now := requestTime(requestData)
+		if _, ok := token.modifier(formatModifier); ok {
+			return token.formatTime(now, "")
+		}
+
+		return strconv.FormatInt(now.Unix(), 10)
+	case p1Token, p2Token, p3Token, p4Token:
+		return strings.Join(requestData.GetParam(token.name), ",")
+	case countryCodeToken:
+		return geo.CountryCode
+	case refererToken:
+		return requestData.Referer
+	case randomStrToken:
+		return randString(randomStringLen)
+	case randomIntToken:
+		return strconv.Itoa(rand.Intn(randomMaxInt-randomMinInt) + randomMinInt)
+	case deviceToken:
+		return ua.Device
+	case platformToken:
+		return ua.Platform
+	case regionToken:
+		return geo.Region
+	case cityToken:
+		return geo.City
+	case asnToken:
+		if geo.ASN > 0 {
+			return strconv.FormatUint(uint64(geo.ASN), 10)
+		}
+
+		return ""
+	case asnOrgToken:
+		return geo.ASNOrganization
+	case ispToken:
+		return geo.ISP
+	case connectionToken:
+		return geo.ConnectionType
+	case languageToken:
+		return preferredLanguage(requestData)
+	default:
+		return dynamicTokenValue(token.name, trackingLink, requestData)
+	}
 }
 
 func (r *redirectInteractor) registerClick(
 	ctx context.Context,
 	slug string,
 	targetURL string,
+	landingID string,
 	trackingLink *entity.TrackingLink,
 	requestData *dto.RedirectRequestData,
 	ua *valueobject.UserAgent,
-	countryCode string,
+	geo *valueobject.GeoLocation,
 ) <-chan *dto.ClickProcessingResult {
 	click := &entity.Click{
-		ID:           requestData.RequestID,
-		TargetURL:    targetURL,
-		Referer:      requestData.Referer,
-		TrkURL:       requestData.URL.String(),
-		Slug:         slug,
-		TRKLink:      trackingLink,
-		SourceID:     trackingLink.SourceID,
-		CampaignID:   trackingLink.CampaignID,
-		AffiliateID:  trackingLink.AffiliateID,
-		AdvertiserID: trackingLink.AdvertiserID,
-		IsParallel:   false,
-		UserAgent:    ua,
-		Agent:        ua.SrcString,
-		Platform:     ua.Platform,
-		Browser:      ua.Browser,
-		Device:       ua.Device,
-		IP:           requestData.IP,
-		CountryCode:  countryCode,
-		P1:           strings.Join(requestData.GetParam("p1"), ","),
-		P2:           strings.Join(requestData.GetParam("p2"), ","),
-		P3:           strings.Join(requestData.GetParam("p3"), ","),
-		P4:           strings.Join(requestData.GetParam("p4"), ","),
-		CreatedAt:    time.Now(),
-	}
-
-	if ctx.Value("slug") != nil {
-		click.ParentSlug = ctx.Value("slug").(string)
-	}
-	if lps, ok := requestData.Params["landing"]; ok && len(lps) > 0 {
-		click.LandingID = requestData.Params["landing"][0]
+		ID:              requestData.RequestID,
+		TargetURL:       targetURL,
+		Referer:         requestData.Referer,
+		TrkURL:          requestData.URL.String(),
+		Slug:            slug,
+		TRKLink:         trackingLink,
+		SourceID:        trackingLink.SourceID,
+		CampaignID:      trackingLink.CampaignID,
+		AffiliateID:     trackingLink.AffiliateID,
+		AdvertiserID:    trackingLink.AdvertiserID,
+		IsParallel:      false,
+		IsUnique:        isUniqueClick(ctx),
+		IsCloaked:       trackingLink.CloakReferrer,
+		UserAgent:       ua,
+		Agent:           ua.SrcString,
+		Platform:        ua.Platform,
+		Browser:         ua.Browser,
+		Device:          ua.Device,
+		Bot:             ua.Bot,
+		IP:              requestData.IP,
+		CountryCode:     geo.CountryCode,
+		Region:          geo.Region,
+		City:            geo.City,
+		PostalCode:      geo.PostalCode,
+		Latitude:        geo.Latitude,
+		Longitude:       geo.Longitude,
+		TimeZone:        geo.TimeZone,
+		ASN:             geo.ASN,
+		ASNOrganization: geo.ASNOrganization,
+		ISP:             geo.ISP,
+		ConnectionType:  geo.ConnectionType,
+		Language:        preferredLanguage(requestData),
+		P1:              strings.Join(requestData.GetParam("p1"), ","),
+		P2:              strings.Join(requestData.GetParam("p2"), ","),
+		P3:              strings.Join(requestData.GetParam("p3"), ","),
+		P4:              strings.Join(requestData.GetParam("p4"), ","),
+		LandingID:       landingID,
+		CreatedAt:       time.Now(),
+	}
+
+	if chain := redirectChain(ctx); len(chain) > 0 {
+		click.ParentSlug = chain[len(chain)-1]
 	}
 	if gclid, ok := requestData.Params["gclid"]; ok && len(gclid) > 0 {
 		click.GCLID = requestData.Params["gclid"][0]
 	}
+	click.AdClickIDs = adClickIDs(requestData.Params)
+
+	r.rememberUniqueClick(ctx)
+
+	// clicks are processed after the response is sent, so they must not be cancelled together with the request
+	clickCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), clickProcessingTimeout)
 
 	outputs := make([]<-chan *dto.ClickProcessingResult, len(r.clickHandlers))
 	for i, handler := range r.clickHandlers {
-		outputs[i] = handler.HandleClick(ctx, click)
+		outputs[i] = handler.HandleClick(clickCtx, click)
 	}
 
-	return merge(outputs)
+	return merge(outputs, cancel)
+}
+
+// skipClick function returns already closed channel, used when the click should not be registered.
+func skipClick() <-chan *dto.ClickProcessingResult {
+	out := make(chan *dto.ClickProcessingResult)
+	close(out)
+
+	return out
 }
 
 // merge function will fan-in the results received from ClickHandlerInterface(s).
-func merge(clkProcessingResultChans []<-chan *dto.ClickProcessingResult) <-chan *dto.ClickProcessingResult {
+// The done function is called once all handlers are finished.
+func merge(clkProcessingResultChans []<-chan *dto.ClickProcessingResult, done func()) <-chan *dto.ClickProcessingResult {
 	var wg sync.WaitGroup
 	out := make(chan *dto.ClickProcessingResult)
 
@@ -415,6 +916,7 @@ func merge(clkProcessingResultChans []<-chan *dto.ClickProcessingResult) <-chan
 
 	go func() {
 		wg.Wait()
+		done()
 		close(out)
 	}()
 
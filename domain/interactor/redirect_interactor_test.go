@@ -2,6 +2,8 @@ package interactor_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net"
@@ -142,7 +144,7 @@ func TestRedirectInteractor_Redirect_DisabledTrackingLink(t *testing.T) {
 }
 
 func TestRedirectInteractor_Redirect_WrongProtocolError(t *testing.T) {
-	ctrl, srv, trkRepo, _, _, _ := setupTest(t)
+	ctrl, srv, trkRepo, ipParser, uaParser, _ := setupTest(t)
 	defer ctrl.Finish()
 
 	td := newTestData()
@@ -155,6 +157,8 @@ func TestRedirectInteractor_Redirect_WrongProtocolError(t *testing.T) {
 	}
 
 	trkRepo.EXPECT().FindTrackingLink(context.Background(), td.slug).Return(trkLink)
+	ipParser.EXPECT().Parse(td.requestData.IP).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
+	uaParser.EXPECT().Parse(td.requestData.UserAgent).Return(td.userAgent, nil)
 
 	result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
 
@@ -183,7 +187,7 @@ func TestRedirectInteractor_Redirect_WrongGeoError(t *testing.T) {
 	}
 
 	trkRepo.EXPECT().FindTrackingLink(context.Background(), td.slug).Return(trkLink)
-	ipParser.EXPECT().Parse(td.requestData.IP).Return("PL", nil)
+	ipParser.EXPECT().Parse(td.requestData.IP).Return(&valueobject.GeoLocation{CountryCode: "PL"}, nil)
 	uaParser.EXPECT().Parse(td.requestData.UserAgent).Return(td.userAgent, nil)
 
 	result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
@@ -214,7 +218,7 @@ func TestRedirectInteractor_Redirect_WrongDeviceError(t *testing.T) {
 	}
 
 	trkRepo.EXPECT().FindTrackingLink(context.Background(), td.slug).Return(trkLink)
-	ipParser.EXPECT().Parse(td.requestData.IP).Return(td.countryCode, nil)
+	ipParser.EXPECT().Parse(td.requestData.IP).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 	uaParser.EXPECT().Parse(td.requestData.UserAgent).Return(td.userAgent, nil)
 
 	result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
@@ -299,7 +303,7 @@ func TestRedirectInteractor_Redirect_CampaignOveraged(t *testing.T) {
 					RedirectType:      valueobject.SmartSlugRedirectType,
 					RedirectURL:       "",
 					RedirectSlug:      "",
-					RedirectSmartSlug: []string{"testSlug000", "testSlug111", "testSlug222"},
+					RedirectSmartSlug: valueobject.SmartSlugs{{Slug: "testSlug000", Weight: 1}, {Slug: "testSlug111", Weight: 1}, {Slug: "testSlug222", Weight: 1}},
 				},
 			},
 			expectedTargetURL: "http://sometarget.url/TestRedirectInteractor_Redirect_CampaignOveraged/OveragedRedirectRulesSmartSlugRedirectType",
@@ -354,7 +358,7 @@ func TestRedirectInteractor_Redirect_CampaignOveraged(t *testing.T) {
 			tc.trkLink.Slug = td.slug
 
 			trkRepo.EXPECT().FindTrackingLink(context.Background(), td.slug).Return(tc.trkLink)
-			ipParser.EXPECT().Parse(td.requestData.IP).Return(td.countryCode, nil)
+			ipParser.EXPECT().Parse(td.requestData.IP).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 			uaParser.EXPECT().Parse(td.requestData.UserAgent).Return(td.userAgent, nil)
 
 			if tc.expectedError == nil {
@@ -393,7 +397,7 @@ func TestRedirectInteractor_Redirect_CampaignOveraged(t *testing.T) {
 							inArray := false
 
 							for _, sl := range tc.trkLink.CampaignOverageRedirectRules.RedirectSmartSlug {
-								if sl == slug {
+								if sl.Slug == slug {
 									inArray = true
 								}
 							}
@@ -414,7 +418,7 @@ func TestRedirectInteractor_Redirect_CampaignOveraged(t *testing.T) {
 							TargetURLTemplate:  "http://sometarget.url/TestRedirectInteractor_Redirect_CampaignOveraged/" + tc.name,
 						}
 					})
-				ipParser.EXPECT().Parse(td.requestData.IP).Return(td.countryCode, nil)
+				ipParser.EXPECT().Parse(td.requestData.IP).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 				uaParser.EXPECT().Parse(td.requestData.UserAgent).Return(td.userAgent, nil)
 			}
 
@@ -507,7 +511,7 @@ func TestRedirectInteractor_Redirect_CampaignDisabled(t *testing.T) {
 					RedirectType:      valueobject.SmartSlugRedirectType,
 					RedirectURL:       "",
 					RedirectSlug:      "",
-					RedirectSmartSlug: []string{"testSlug000", "testSlug111", "testSlug222"},
+					RedirectSmartSlug: valueobject.SmartSlugs{{Slug: "testSlug000", Weight: 1}, {Slug: "testSlug111", Weight: 1}, {Slug: "testSlug222", Weight: 1}},
 				},
 			},
 			expectedTargetURL: "http://sometarget.url/TestRedirectInteractor_Redirect_CampaignDisabled/CampaignDisabledRedirectRulesSmartSlugRedirectType",
@@ -562,7 +566,7 @@ func TestRedirectInteractor_Redirect_CampaignDisabled(t *testing.T) {
 			tc.trkLink.Slug = td.slug
 
 			trkRepo.EXPECT().FindTrackingLink(context.Background(), td.slug).Return(tc.trkLink)
-			ipParser.EXPECT().Parse(td.requestData.IP).Return(td.countryCode, nil)
+			ipParser.EXPECT().Parse(td.requestData.IP).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 			uaParser.EXPECT().Parse(td.requestData.UserAgent).Return(td.userAgent, nil)
 
 			if tc.expectedError == nil {
@@ -600,7 +604,7 @@ func TestRedirectInteractor_Redirect_CampaignDisabled(t *testing.T) {
 							inArray := false
 
 							for _, sl := range tc.trkLink.CampaignDisabledRedirectRules.RedirectSmartSlug {
-								if sl == slug {
+								if sl.Slug == slug {
 									inArray = true
 								}
 							}
@@ -621,7 +625,7 @@ func TestRedirectInteractor_Redirect_CampaignDisabled(t *testing.T) {
 							TargetURLTemplate:  "http://sometarget.url/TestRedirectInteractor_Redirect_CampaignDisabled/" + tc.name,
 						}
 					})
-				ipParser.EXPECT().Parse(td.requestData.IP).Return(td.countryCode, nil)
+				ipParser.EXPECT().Parse(td.requestData.IP).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 				uaParser.EXPECT().Parse(td.requestData.UserAgent).Return(td.userAgent, nil)
 			}
 
@@ -687,7 +691,7 @@ func TestRedirectInteractor_Redirect_RenderTokens(t *testing.T) {
 			trkLink: trackingLink,
 			tokens:  []string{"{user_agent}"},
 			expectedTargetURL: fmt.Sprintf("%s?key0=%s",
-				trackingLink.TargetURLTemplate, userAgent),
+				trackingLink.TargetURLTemplate, url.QueryEscape(userAgent)),
 		},
 		{
 			name:    "RenderTokens_CampaignIDToken",
@@ -729,7 +733,7 @@ func TestRedirectInteractor_Redirect_RenderTokens(t *testing.T) {
 			trkLink: trackingLink,
 			tokens:  []string{"{date_time}"},
 			expectedTargetURL: fmt.Sprintf("%s?key0=%s",
-				trackingLink.TargetURLTemplate, time.Now().Format("2006-01-02T15:04:05")),
+				trackingLink.TargetURLTemplate, url.QueryEscape(time.Now().Format("2006-01-02T15:04:05"))),
 		},
 		{
 			name:    "RenderTokens_TimestampToken",
@@ -756,7 +760,7 @@ func TestRedirectInteractor_Redirect_RenderTokens(t *testing.T) {
 			name:              "RenderTokens_RefererToken",
 			trkLink:           trackingLink,
 			tokens:            []string{"{referer}"},
-			expectedTargetURL: fmt.Sprintf("%s?key0=%s", trackingLink.TargetURLTemplate, referrer),
+			expectedTargetURL: fmt.Sprintf("%s?key0=%s", trackingLink.TargetURLTemplate, url.QueryEscape(referrer)),
 		},
 		/*		{
 					name:              "RenderTokens_RandomStrToken",
@@ -813,7 +817,7 @@ func TestRedirectInteractor_Redirect_RenderTokens(t *testing.T) {
 			}
 
 			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(&tc.trkLink)
-			ipParser.EXPECT().Parse(td.requestData.IP).Return(td.countryCode, nil)
+			ipParser.EXPECT().Parse(td.requestData.IP).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 			uaParser.EXPECT().Parse(td.requestData.UserAgent).Return(td.userAgent, nil)
 
 			clkRepo.EXPECT().
@@ -877,7 +881,7 @@ func TestRedirectInteractor_Redirect_LogErrors(t *testing.T) {
 		TargetURLTemplate:  "http://target.url/path",
 		CampaignID:         "1234",
 	}
-	expectedCountry := ""
+	var expectedGeo *valueobject.GeoLocation
 	expectedIPAddressParseError := errors.New("expected ip address parse error")
 	expectedUserAgentParseError := errors.New("expected user agent parse error")
 	expectedUserAgent := &valueobject.UserAgent{
@@ -888,7 +892,7 @@ func TestRedirectInteractor_Redirect_LogErrors(t *testing.T) {
 	}
 
 	trkRepo.EXPECT().FindTrackingLink(context.Background(), expectedSlug).Return(&expectedTrkLink)
-	ipAddressParser.EXPECT().Parse(expectedDto.IP).Return(expectedCountry, expectedIPAddressParseError)
+	ipAddressParser.EXPECT().Parse(expectedDto.IP).Return(expectedGeo, expectedIPAddressParseError)
 	userAgentParser.EXPECT().Parse(expectedDto.UserAgent).Return(expectedUserAgent, expectedUserAgentParseError)
 
 	rResult, err := srv.Redirect(context.Background(), expectedSlug, expectedDto)
@@ -1057,7 +1061,7 @@ func TestRedirectInteractor_Redirect_OSValidation(t *testing.T) {
 
 			ipParser.EXPECT().
 				Parse(gomock.Any()).
-				Return(td.countryCode, nil)
+				Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 
 			clkRepo.EXPECT().
 				Save(gomock.Any(), gomock.Any()).
@@ -1118,7 +1122,7 @@ func TestRedirectInteractor_Redirect_WithLandingPage(t *testing.T) {
 
 	ipParser.EXPECT().
 		Parse(gomock.Any()).
-		Return(td.countryCode, nil)
+		Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 
 	clkRepo.EXPECT().
 		Save(gomock.Any(), gomock.Any()).
@@ -1158,14 +1162,15 @@ func TestRedirectInteractor_Redirect_WithDeeplink(t *testing.T) {
 	td.requestData.Params["deeplink"] = []string{deeplinkURL}
 
 	trkLink := &entity.TrackingLink{
-		IsActive:           true,
-		IsCampaignActive:   true,
-		IsCampaignOveraged: false,
-		TargetURLTemplate:  "https://example.com/default-page",
-		AllowedProtocols:   make(entity.AllowedListType),
-		AllowedGeos:        make(entity.AllowedListType),
-		AllowedDevices:     make(entity.AllowedListType),
-		AllowDeeplink:      true,
+		IsActive:               true,
+		IsCampaignActive:       true,
+		IsCampaignOveraged:     false,
+		TargetURLTemplate:      "https://example.com/default-page",
+		AllowedProtocols:       make(entity.AllowedListType),
+		AllowedGeos:            make(entity.AllowedListType),
+		AllowedDevices:         make(entity.AllowedListType),
+		AllowDeeplink:          true,
+		AllowedDeeplinkSchemes: entity.AllowedListType{"app": true},
 	}
 
 	// Configure mocks
@@ -1179,7 +1184,7 @@ func TestRedirectInteractor_Redirect_WithDeeplink(t *testing.T) {
 
 	ipParser.EXPECT().
 		Parse(gomock.Any()).
-		Return(td.countryCode, nil)
+		Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 
 	clkRepo.EXPECT().
 		Save(gomock.Any(), gomock.Any()).
@@ -1238,7 +1243,7 @@ func TestRedirectInteractor_Redirect_WithDeeplinkDisabled(t *testing.T) {
 
 	ipParser.EXPECT().
 		Parse(gomock.Any()).
-		Return(td.countryCode, nil)
+		Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 
 	clkRepo.EXPECT().
 		Save(gomock.Any(), gomock.Any()).
@@ -1296,7 +1301,7 @@ func TestRedirectInteractor_Redirect_WithGCLID(t *testing.T) {
 
 	ipParser.EXPECT().
 		Parse(gomock.Any()).
-		Return(td.countryCode, nil)
+		Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 
 	clkRepo.EXPECT().
 		Save(gomock.Any(), gomock.Any()).
@@ -1337,14 +1342,15 @@ func TestRedirectInteractor_Redirect_WithMultipleParams(t *testing.T) {
 	td.requestData.Params["deeplink"] = []string{deeplinkURL}
 
 	trkLink := &entity.TrackingLink{
-		IsActive:           true,
-		IsCampaignActive:   true,
-		IsCampaignOveraged: false,
-		TargetURLTemplate:  "https://example.com/default-page",
-		AllowedProtocols:   make(entity.AllowedListType),
-		AllowedGeos:        make(entity.AllowedListType),
-		AllowedDevices:     make(entity.AllowedListType),
-		AllowDeeplink:      true,
+		IsActive:               true,
+		IsCampaignActive:       true,
+		IsCampaignOveraged:     false,
+		TargetURLTemplate:      "https://example.com/default-page",
+		AllowedProtocols:       make(entity.AllowedListType),
+		AllowedGeos:            make(entity.AllowedListType),
+		AllowedDevices:         make(entity.AllowedListType),
+		AllowDeeplink:          true,
+		AllowedDeeplinkSchemes: entity.AllowedListType{"app": true},
 		LandingPages: map[string]*entity.LandingPage{
 			landingPage: {
 				ID:        "landing-id-456",
@@ -1364,7 +1370,7 @@ func TestRedirectInteractor_Redirect_WithMultipleParams(t *testing.T) {
 
 	ipParser.EXPECT().
 		Parse(gomock.Any()).
-		Return(td.countryCode, nil)
+		Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 
 	clkRepo.EXPECT().
 		Save(gomock.Any(), gomock.Any()).
@@ -1404,6 +1410,7 @@ func TestRedirectInteractor_Redirect_TokenReplacement(t *testing.T) {
 	tests := []struct {
 		name              string
 		token             string
+		acceptLanguage    string
 		expectedValue     string
 		targetURLTemplate string
 	}{
@@ -1416,7 +1423,7 @@ func TestRedirectInteractor_Redirect_TokenReplacement(t *testing.T) {
 		{
 			name:              "user agent token",
 			token:             "{user_agent}",
-			expectedValue:     "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36",
+			expectedValue:     "Mozilla%2F5.0+%28X11%3B+Linux+x86_64%29+AppleWebKit%2F537.36",
 			targetURLTemplate: "https://example.com/track",
 		},
 		{
@@ -1443,6 +1450,79 @@ func TestRedirectInteractor_Redirect_TokenReplacement(t *testing.T) {
 			expectedValue:     "Mobile/Android/US",
 			targetURLTemplate: "https://example.com/track",
 		},
+		{
+			name:              "language token",
+			token:             "{language}",
+			acceptLanguage:    "pt-BR,pt;q=0.9,en;q=0.8",
+			expectedValue:     "pt",
+			targetURLTemplate: "https://example.com/track",
+		},
+		{
+			name:              "language token without Accept-Language header",
+			token:             "{language}",
+			expectedValue:     "",
+			targetURLTemplate: "https://example.com/track",
+		},
+		{
+			name:              "raw token",
+			token:             "{{referer}}",
+			expectedValue:     "https://referrer.com",
+			targetURLTemplate: "https://example.com/track",
+		},
+		{
+			name:              "raw modifier",
+			token:             "{referer|raw}",
+			expectedValue:     "https://referrer.com",
+			targetURLTemplate: "https://example.com/track",
+		},
+		{
+			name:              "urlencode modifier is not encoded twice",
+			token:             "{referer|urlencode}",
+			expectedValue:     "https%3A%2F%2Freferrer.com",
+			targetURLTemplate: "https://example.com/track",
+		},
+		{
+			name:              "default modifier",
+			token:             "{language|default:none}",
+			expectedValue:     "none",
+			targetURLTemplate: "https://example.com/track",
+		},
+		{
+			name:              "default modifier with not empty value",
+			token:             "{p1|default:none}",
+			expectedValue:     p1,
+			targetURLTemplate: "https://example.com/track",
+		},
+		{
+			name:              "sha256 modifier",
+			token:             "{ip|sha256}",
+			expectedValue:     fmt.Sprintf("%x", sha256.Sum256([]byte(ipAddress))),
+			targetURLTemplate: "https://example.com/track",
+		},
+		{
+			name:              "base64 modifier",
+			token:             "{user_agent|base64}",
+			expectedValue:     url.QueryEscape(base64.StdEncoding.EncodeToString([]byte(userAgent))),
+			targetURLTemplate: "https://example.com/track",
+		},
+		{
+			name:              "modifiers pipeline",
+			token:             "{p5|default:fallback|sha256}",
+			expectedValue:     fmt.Sprintf("%x", sha256.Sum256([]byte("fallback"))),
+			targetURLTemplate: "https://example.com/track",
+		},
+		{
+			name:              "format modifier",
+			token:             "{date|format:20060102}",
+			expectedValue:     time.Now().Format("20060102"),
+			targetURLTemplate: "https://example.com/track",
+		},
+		{
+			name:              "unknown token with default modifier",
+			token:             "{unknown|default:x}",
+			expectedValue:     "x",
+			targetURLTemplate: "https://example.com/track",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1451,6 +1531,9 @@ func TestRedirectInteractor_Redirect_TokenReplacement(t *testing.T) {
 			defer ctrl.Finish()
 
 			td := newTestData()
+			if tt.acceptLanguage != "" {
+				td.requestData.Headers["Accept-Language"] = []string{tt.acceptLanguage}
+			}
 
 			trkLink := &entity.TrackingLink{
 				IsActive:           true,
@@ -1473,7 +1556,7 @@ func TestRedirectInteractor_Redirect_TokenReplacement(t *testing.T) {
 
 			ipParser.EXPECT().
 				Parse(gomock.Any()).
-				Return(td.countryCode, nil)
+				Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 
 			clkRepo.EXPECT().
 				Save(gomock.Any(), gomock.Any()).
@@ -1536,7 +1619,7 @@ func TestRedirectInteractor_Redirect_RandomStrTokenReplacement(t *testing.T) {
 
 			ipParser.EXPECT().
 				Parse(gomock.Any()).
-				Return(td.countryCode, nil)
+				Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 
 			clkRepo.EXPECT().
 				Save(gomock.Any(), gomock.Any()).
@@ -1597,7 +1680,7 @@ func TestRedirectInteractor_Redirect_RandomIntTokenReplacement(t *testing.T) {
 
 			ipParser.EXPECT().
 				Parse(gomock.Any()).
-				Return(td.countryCode, nil)
+				Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
 
 			clkRepo.EXPECT().
 				Save(gomock.Any(), gomock.Any()).
@@ -1619,3 +1702,2393 @@ func TestRedirectInteractor_Redirect_RandomIntTokenReplacement(t *testing.T) {
 		})
 	}
 }
+
+func TestRedirectInteractor_Redirect_RedirectChain(t *testing.T) {
+	fallbackURL := "https://fallback.example.com"
+
+	slugLink := func(slug, nextSlug string) *entity.TrackingLink {
+		return &entity.TrackingLink{
+			IsActive:           true,
+			IsCampaignActive:   true,
+			IsCampaignOveraged: true,
+			Slug:               slug,
+			TargetURLTemplate:  "https://example.com/" + slug,
+			CampaignOverageRedirectRules: &valueobject.RedirectRules{
+				RedirectType: valueobject.SlugRedirectType,
+				RedirectSlug: nextSlug,
+			},
+		}
+	}
+	targetLink := &entity.TrackingLink{
+		IsActive:          true,
+		IsCampaignActive:  true,
+		Slug:              "target",
+		TargetURLTemplate: "https://example.com/target",
+	}
+
+	tests := []struct {
+		name              string
+		links             map[string]*entity.TrackingLink
+		options           []interactor.RedirectInteractorOption
+		expectedTargetURL string
+		expectedParent    string
+		expectedError     error
+	}{
+		{
+			name: "chain within depth",
+			links: map[string]*entity.TrackingLink{
+				"a":      slugLink("a", "b"),
+				"b":      slugLink("b", "target"),
+				"target": targetLink,
+			},
+			expectedTargetURL: "https://example.com/target",
+			expectedParent:    "b",
+		},
+		{
+			name: "self loop",
+			links: map[string]*entity.TrackingLink{
+				"a": slugLink("a", "a"),
+			},
+			expectedError: interactor.ErrRedirectLoop,
+		},
+		{
+			name: "loop between links",
+			links: map[string]*entity.TrackingLink{
+				"a": slugLink("a", "b"),
+				"b": slugLink("b", "a"),
+			},
+			expectedError: interactor.ErrRedirectLoop,
+		},
+		{
+			name: "loop with fallback url",
+			links: map[string]*entity.TrackingLink{
+				"a": slugLink("a", "b"),
+				"b": slugLink("b", "a"),
+			},
+			options:           []interactor.RedirectInteractorOption{interactor.WithFallbackURL(fallbackURL)},
+			expectedTargetURL: fallbackURL,
+		},
+		{
+			name: "depth exceeded",
+			links: map[string]*entity.TrackingLink{
+				"a":      slugLink("a", "b"),
+				"b":      slugLink("b", "c"),
+				"c":      slugLink("c", "target"),
+				"target": targetLink,
+			},
+			options:       []interactor.RedirectInteractorOption{interactor.WithMaxRedirectDepth(2)},
+			expectedError: interactor.ErrRedirectDepthExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			clkRepo := mocks.NewMockClicksRepository(ctrl)
+			trkRepo := mocks.NewMockTrackingLinksRepositoryInterface(ctrl)
+			ipParser := mocks.NewMockIPAddressParserInterface(ctrl)
+			uaParser := mocks.NewMockUserAgentParserInterface(ctrl)
+
+			srv := interactor.NewRedirectInteractor(
+				trkRepo,
+				ipParser,
+				uaParser,
+				[]interactor.ClickHandlerInterface{interactor.NewStoreClickHandler(clkRepo)},
+				tt.options...,
+			)
+
+			td := newTestData()
+
+			trkRepo.EXPECT().
+				FindTrackingLink(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, slug string) *entity.TrackingLink {
+					return tt.links[slug]
+				}).
+				AnyTimes()
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil).AnyTimes()
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil).AnyTimes()
+
+			// fallback redirects are not registered as clicks
+			if tt.expectedError == nil && tt.expectedParent != "" {
+				clkRepo.EXPECT().
+					Save(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, click *entity.Click) error {
+						if click.ParentSlug != tt.expectedParent {
+							t.Errorf("unexpected parent slug. expected %s but got %s", tt.expectedParent, click.ParentSlug)
+						}
+
+						return nil
+					})
+			}
+
+			result, err := srv.Redirect(context.Background(), "a", td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+				if result != nil {
+					t.Error("expected nil result")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_SmartSlugDistribution(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     *valueobject.RedirectRules
+		visitors  []string
+		checkPick func(t *testing.T, picked map[string]string)
+	}{
+		{
+			name: "zero weight target never selected",
+			rules: &valueobject.RedirectRules{
+				RedirectType:      valueobject.SmartSlugRedirectType,
+				RedirectSmartSlug: valueobject.SmartSlugs{{Slug: "a", Weight: 1}, {Slug: "b", Weight: 0}},
+			},
+			visitors: []string{"v1", "v2", "v3", "v4", "v5"},
+			checkPick: func(t *testing.T, picked map[string]string) {
+				for visitor, slug := range picked {
+					if slug != "a" {
+						t.Errorf("unexpected slug for visitor %s. expected a got %s", visitor, slug)
+					}
+				}
+			},
+		},
+		{
+			name: "sticky visitor lands on the same target",
+			rules: &valueobject.RedirectRules{
+				RedirectType:      valueobject.SmartSlugRedirectType,
+				RedirectSmartSlug: valueobject.SmartSlugs{{Slug: "a", Weight: 50}, {Slug: "b", Weight: 50}},
+				StickySmartSlug:   true,
+			},
+			visitors: []string{"v1", "v1", "v1", "v1", "v1"},
+			checkPick: func(t *testing.T, picked map[string]string) {
+				if len(picked) != 1 {
+					t.Errorf("expected a single visitor, got %v", picked)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			trkLink := &entity.TrackingLink{
+				IsActive:                     true,
+				IsCampaignActive:             true,
+				IsCampaignOveraged:           true,
+				Slug:                         requestSlug,
+				CampaignOverageRedirectRules: tt.rules,
+			}
+
+			picked := make(map[string]string)
+			currentVisitor := ""
+
+			trkRepo.EXPECT().
+				FindTrackingLink(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, slug string) *entity.TrackingLink {
+					if slug == requestSlug {
+						return trkLink
+					}
+
+					if prev, ok := picked[currentVisitor]; ok && prev != slug {
+						t.Errorf("visitor %s moved from %s to %s", currentVisitor, prev, slug)
+					}
+					picked[currentVisitor] = slug
+
+					return &entity.TrackingLink{
+						IsActive:          true,
+						IsCampaignActive:  true,
+						Slug:              slug,
+						TargetURLTemplate: "https://example.com/" + slug,
+					}
+				}).
+				AnyTimes()
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil).AnyTimes()
+			uaParser.EXPECT().Parse(gomock.Any()).Return(newTestData().userAgent, nil).AnyTimes()
+			clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+			for _, visitor := range tt.visitors {
+				td := newTestData()
+				td.requestData.VisitorID = visitor
+				currentVisitor = visitor
+
+				result, err := srv.Redirect(context.Background(), requestSlug, td.requestData)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				<-result.OutputCh
+			}
+
+			tt.checkPick(t, picked)
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_SmartSlugWithoutTargets(t *testing.T) {
+	ctrl, srv, trkRepo, ipParser, uaParser, _ := setupTest(t)
+	defer ctrl.Finish()
+
+	td := newTestData()
+	trkLink := &entity.TrackingLink{
+		IsActive:           true,
+		IsCampaignActive:   true,
+		IsCampaignOveraged: true,
+		Slug:               td.slug,
+		CampaignOverageRedirectRules: &valueobject.RedirectRules{
+			RedirectType:      valueobject.SmartSlugRedirectType,
+			RedirectSmartSlug: valueobject.SmartSlugs{{Slug: "a", Weight: 0}},
+		},
+	}
+
+	trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+	ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
+	uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+
+	result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+	if !errors.Is(err, interactor.ErrInvalidRedirectRules) {
+		t.Errorf("expected ErrInvalidRedirectRules, got %v", err)
+	}
+	if result != nil {
+		t.Error("expected nil result")
+	}
+}
+
+func TestRedirectInteractor_Redirect_ProtocolRedirectRules(t *testing.T) {
+	upgradeURL := "https://secure.example.com/upgrade"
+
+	tests := []struct {
+		name              string
+		protocol          string
+		allowedProtocols  entity.AllowedListType
+		rules             *valueobject.RedirectRules
+		expectedTargetURL string
+		expectedError     error
+	}{
+		{
+			name:              "allowed protocol",
+			protocol:          "https",
+			allowedProtocols:  entity.AllowedListType{"https": true},
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name:              "allowed protocol in different notation",
+			protocol:          "HTTP/1.1",
+			allowedProtocols:  entity.AllowedListType{"http": true},
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name:              "allowed protocol with upper-cased allowed list",
+			protocol:          "https",
+			allowedProtocols:  entity.AllowedListType{"HTTPS": true},
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name:             "disallowed protocol redirects to url",
+			protocol:         "http",
+			allowedProtocols: entity.AllowedListType{"https": true},
+			rules: &valueobject.RedirectRules{
+				RedirectType: valueobject.LinkRedirectType,
+				RedirectURL:  upgradeURL,
+			},
+			expectedTargetURL: upgradeURL,
+		},
+		{
+			name:             "protocol disabled in allowed list",
+			protocol:         "http",
+			allowedProtocols: entity.AllowedListType{"http": false, "https": true},
+			rules: &valueobject.RedirectRules{
+				RedirectType: valueobject.NoRedirectType,
+			},
+			expectedError: interactor.ErrUnsupportedProtocol,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+			td.requestData.Protocol = tt.protocol
+
+			trkLink := &entity.TrackingLink{
+				IsActive:                      true,
+				IsCampaignActive:              true,
+				Slug:                          td.slug,
+				AllowedProtocols:              tt.allowedProtocols,
+				CampaignProtocolRedirectRules: tt.rules,
+				TargetURLTemplate:             redirectURL,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			if tt.expectedError == nil {
+				clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_NoClickRedirectType(t *testing.T) {
+	tests := []struct {
+		name              string
+		rules             *valueobject.RedirectRules
+		expectedTargetURL string
+	}{
+		{
+			name: "redirect url is set",
+			rules: &valueobject.RedirectRules{
+				RedirectType: valueobject.NoClickType,
+				RedirectURL:  "https://noclick.example.com",
+			},
+			expectedTargetURL: "https://noclick.example.com",
+		},
+		{
+			name: "tracking link target is used",
+			rules: &valueobject.RedirectRules{
+				RedirectType: valueobject.NoClickType,
+			},
+			expectedTargetURL: "https://example.com/target?country=PL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// clicks repository has no expectations, so any attempt to save a click fails the test
+			ctrl, srv, trkRepo, ipParser, uaParser, _ := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+			trkLink := &entity.TrackingLink{
+				IsActive:                 true,
+				IsCampaignActive:         true,
+				Slug:                     td.slug,
+				AllowedGeos:              entity.AllowedListType{"US": true},
+				CampaignGeoRedirectRules: tt.rules,
+				TargetURLTemplate:        "https://example.com/target?country={country_code}",
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: "PL"}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			select {
+			case res, ok := <-result.OutputCh:
+				if ok {
+					t.Errorf("expected closed output channel, got %v", res)
+				}
+			case <-time.After(time.Second):
+				t.Error("output channel is not closed")
+			}
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_BotTraffic(t *testing.T) {
+	botRedirectURL := "https://bots.example.com"
+
+	tests := []struct {
+		name              string
+		allowBots         bool
+		rules             *valueobject.RedirectRules
+		expectedTargetURL string
+		expectedError     error
+	}{
+		{
+			name:              "bots allowed",
+			allowBots:         true,
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name:          "bots not allowed without rules",
+			expectedError: interactor.ErrBotsNotAllowed,
+		},
+		{
+			name: "bots redirected by rules",
+			rules: &valueobject.RedirectRules{
+				RedirectType: valueobject.LinkRedirectType,
+				RedirectURL:  botRedirectURL,
+			},
+			expectedTargetURL: botRedirectURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+			td.userAgent.Bot = true
+
+			trkLink := &entity.TrackingLink{
+				IsActive:                  true,
+				IsCampaignActive:          true,
+				Slug:                      td.slug,
+				AllowBots:                 tt.allowBots,
+				CampaignBotsRedirectRules: tt.rules,
+				TargetURLTemplate:         redirectURL,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			if tt.expectedError == nil {
+				clkRepo.EXPECT().
+					Save(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, click *entity.Click) error {
+						if !click.Bot {
+							t.Error("expected click to be marked as bot")
+						}
+
+						return nil
+					})
+			}
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_TargetingRules(t *testing.T) {
+	usRule := &valueobject.TargetingRule{
+		Priority: 1,
+		Conditions: valueobject.TargetingConditions{
+			{Field: valueobject.CountryConditionField, Operator: valueobject.InOperator, Values: []string{"US"}},
+			{Field: valueobject.QueryParamConditionField, Operator: valueobject.InOperator, Key: "src", Values: []string{"fb"}},
+		},
+		Action: &valueobject.RedirectRules{RedirectType: valueobject.LinkRedirectType, RedirectURL: "https://us-fb.example.com"},
+	}
+	blockRule := &valueobject.TargetingRule{
+		Priority: 2,
+		Logic:    valueobject.OrLogic,
+		Conditions: valueobject.TargetingConditions{
+			{Field: valueobject.RefererConditionField, Operator: valueobject.ContainsOperator, Values: []string{"spam"}},
+			{Field: valueobject.LanguageConditionField, Operator: valueobject.InOperator, Values: []string{"ru"}},
+		},
+		Action: &valueobject.RedirectRules{RedirectType: valueobject.NoRedirectType},
+	}
+	catchAllRule := &valueobject.TargetingRule{
+		Priority: 3,
+		Action:   &valueobject.RedirectRules{RedirectType: valueobject.LinkRedirectType, RedirectURL: "https://catch-all.example.com"},
+	}
+
+	tests := []struct {
+		name              string
+		params            map[string][]string
+		referer           string
+		acceptLanguage    string
+		bot               bool
+		allowedOS         entity.AllowedListType
+		campaignInactive  bool
+		campaignOveraged  bool
+		expectedTargetURL string
+		expectedError     error
+	}{
+		{
+			name:              "first matching rule is applied",
+			params:            map[string][]string{"src": {"fb"}},
+			referer:           "https://spam.example.com",
+			expectedTargetURL: "https://us-fb.example.com",
+		},
+		{
+			name:          "or conditions block traffic by referer",
+			referer:       "https://spam.example.com",
+			expectedError: interactor.ErrBlockRedirect,
+		},
+		{
+			name:           "or conditions block traffic by language",
+			acceptLanguage: "ru-RU,ru;q=0.9",
+			expectedError:  interactor.ErrBlockRedirect,
+		},
+		{
+			name:              "catch-all rule is applied",
+			expectedTargetURL: "https://catch-all.example.com",
+		},
+		{
+			name:          "fixed restrictions are applied before targeting rules",
+			allowedOS:     entity.AllowedListType{"iOS": true},
+			expectedError: interactor.ErrUnsupportedOS,
+		},
+		{
+			name:          "bots are blocked before targeting rules",
+			bot:           true,
+			expectedError: interactor.ErrBotsNotAllowed,
+		},
+		{
+			name:              "overaged campaign is handled before targeting rules",
+			campaignOveraged:  true,
+			expectedTargetURL: "https://overage.example.com",
+		},
+		{
+			name:              "inactive campaign is handled before targeting rules",
+			campaignInactive:  true,
+			expectedTargetURL: "https://disabled.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+			td.requestData.Params = tt.params
+			td.requestData.Referer = tt.referer
+			td.requestData.Headers["Accept-Language"] = []string{tt.acceptLanguage}
+			td.userAgent.Bot = tt.bot
+
+			trkLink := &entity.TrackingLink{
+				IsActive:           true,
+				IsCampaignActive:   !tt.campaignInactive,
+				IsCampaignOveraged: tt.campaignOveraged,
+				Slug:               td.slug,
+				AllowedOS:          tt.allowedOS,
+				CampaignDisabledRedirectRules: &valueobject.RedirectRules{
+					RedirectType: valueobject.LinkRedirectType,
+					RedirectURL:  "https://disabled.example.com",
+				},
+				CampaignOverageRedirectRules: &valueobject.RedirectRules{
+					RedirectType: valueobject.LinkRedirectType,
+					RedirectURL:  "https://overage.example.com",
+				},
+				TargetingRules:    []*valueobject.TargetingRule{usRule, blockRule, catchAllRule},
+				TargetURLTemplate: redirectURL,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: "US"}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			if tt.expectedError == nil {
+				clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_Schedule(t *testing.T) {
+	schedule := valueobject.Schedule{
+		Timezone: "Europe/Berlin",
+		Windows:  []valueobject.ScheduleWindow{{Weekdays: []string{"mon", "tue", "wed", "thu", "fri"}, From: "09:00", To: "18:00"}},
+	}
+
+	tests := []struct {
+		name              string
+		requestTime       time.Time
+		redirectRules     *valueobject.RedirectRules
+		expectedTargetURL string
+		expectedError     error
+	}{
+		{
+			name:              "inside schedule",
+			requestTime:       time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC),
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name:          "out of schedule without redirect rules",
+			requestTime:   time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC),
+			expectedError: interactor.ErrOutOfSchedule,
+		},
+		{
+			name:              "out of schedule with redirect rules",
+			requestTime:       time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+			redirectRules:     &valueobject.RedirectRules{RedirectType: valueobject.LinkRedirectType, RedirectURL: "https://closed.example.com"},
+			expectedTargetURL: "https://closed.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+			td.requestData.Time = tt.requestTime
+
+			trkLink := &entity.TrackingLink{
+				IsActive:                      true,
+				IsCampaignActive:              true,
+				Slug:                          td.slug,
+				Schedule:                      schedule,
+				CampaignScheduleRedirectRules: tt.redirectRules,
+				TargetURLTemplate:             redirectURL,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			if tt.expectedError == nil {
+				clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_ClickCaps(t *testing.T) {
+	overageRules := &valueobject.RedirectRules{RedirectType: valueobject.LinkRedirectType, RedirectURL: "https://overage.example.com"}
+
+	tests := []struct {
+		name              string
+		clickCaps         valueobject.ClickCaps
+		overageRules      *valueobject.RedirectRules
+		reserved          bool
+		reserveErr        error
+		expectReserve     bool
+		expectedTargetURL string
+		expectedError     error
+	}{
+		{
+			name:              "caps are not set",
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name:              "cap is not reached",
+			clickCaps:         valueobject.ClickCaps{Daily: 100},
+			overageRules:      overageRules,
+			reserved:          true,
+			expectReserve:     true,
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name:              "cap is reached",
+			clickCaps:         valueobject.ClickCaps{Hourly: 10},
+			overageRules:      overageRules,
+			expectReserve:     true,
+			expectedTargetURL: "https://overage.example.com",
+		},
+		{
+			name:          "cap is reached without overage rules",
+			clickCaps:     valueobject.ClickCaps{Lifetime: 1000},
+			expectReserve: true,
+			expectedError: interactor.ErrClickCapReached,
+		},
+		{
+			name:              "counters storage error",
+			clickCaps:         valueobject.ClickCaps{Daily: 100},
+			overageRules:      overageRules,
+			reserveErr:        errors.New("connection refused"),
+			expectReserve:     true,
+			expectedTargetURL: redirectURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			clkRepo := mocks.NewMockClicksRepository(ctrl)
+			capsRepo := mocks.NewMockClickCapsRepository(ctrl)
+			trkRepo := mocks.NewMockTrackingLinksRepositoryInterface(ctrl)
+			ipParser := mocks.NewMockIPAddressParserInterface(ctrl)
+			uaParser := mocks.NewMockUserAgentParserInterface(ctrl)
+			srv := interactor.NewRedirectInteractor(
+				trkRepo,
+				ipParser,
+				uaParser,
+				[]interactor.ClickHandlerInterface{interactor.NewStoreClickHandler(clkRepo)},
+				interactor.WithClickCaps(capsRepo),
+			)
+
+			td := newTestData()
+			td.requestData.Time = time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+			trkLink := &entity.TrackingLink{
+				IsActive:                     true,
+				IsCampaignActive:             true,
+				Slug:                         td.slug,
+				ClickCaps:                    tt.clickCaps,
+				CampaignOverageRedirectRules: tt.overageRules,
+				TargetURLTemplate:            redirectURL,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			if tt.expectReserve {
+				capsRepo.EXPECT().Reserve(gomock.Any(), trkLink, td.requestData.Time).Return(tt.reserved, tt.reserveErr)
+			}
+			if tt.expectedError == nil {
+				clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_ClickCapsRedirectRules(t *testing.T) {
+	tests := []struct {
+		name              string
+		reserved          bool
+		expectedTargetURL string
+	}{
+		{
+			name:              "cap is not reached",
+			reserved:          true,
+			expectedTargetURL: "https://bots.example.com",
+		},
+		{
+			name:              "cap is reached",
+			expectedTargetURL: "https://overage.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			clkRepo := mocks.NewMockClicksRepository(ctrl)
+			capsRepo := mocks.NewMockClickCapsRepository(ctrl)
+			trkRepo := mocks.NewMockTrackingLinksRepositoryInterface(ctrl)
+			ipParser := mocks.NewMockIPAddressParserInterface(ctrl)
+			uaParser := mocks.NewMockUserAgentParserInterface(ctrl)
+			srv := interactor.NewRedirectInteractor(
+				trkRepo,
+				ipParser,
+				uaParser,
+				[]interactor.ClickHandlerInterface{interactor.NewStoreClickHandler(clkRepo)},
+				interactor.WithClickCaps(capsRepo),
+			)
+
+			td := newTestData()
+			td.userAgent.Bot = true
+			td.requestData.Time = time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+			trkLink := &entity.TrackingLink{
+				IsActive:         true,
+				IsCampaignActive: true,
+				Slug:             td.slug,
+				ClickCaps:        valueobject.ClickCaps{Daily: 100},
+				CampaignOverageRedirectRules: &valueobject.RedirectRules{
+					RedirectType: valueobject.LinkRedirectType,
+					RedirectURL:  "https://overage.example.com",
+				},
+				CampaignBotsRedirectRules: &valueobject.RedirectRules{
+					RedirectType: valueobject.LinkRedirectType,
+					RedirectURL:  "https://bots.example.com",
+				},
+				TargetURLTemplate: redirectURL,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			capsRepo.EXPECT().Reserve(gomock.Any(), trkLink, td.requestData.Time).Return(tt.reserved, nil)
+			clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_DuplicateClicks(t *testing.T) {
+	duplicateRules := &valueobject.RedirectRules{RedirectType: valueobject.LinkRedirectType, RedirectURL: "https://duplicate.example.com"}
+
+	tests := []struct {
+		name                  string
+		keyType               string
+		visitorID             string
+		expectedKey           string
+		unique                bool
+		handleDuplicateClicks bool
+		duplicateRules        *valueobject.RedirectRules
+		allowedGeos           entity.AllowedListType
+		expectedTargetURL     string
+		expectedError         error
+	}{
+		{
+			name:              "unique click",
+			keyType:           interactor.IPUserAgentUniqueClickKey,
+			expectedKey:       requestSlug + "|" + ipAddress + "|" + userAgent,
+			unique:            true,
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name:              "duplicate click is not handled",
+			keyType:           interactor.VisitorUniqueClickKey,
+			visitorID:         "visitor-1",
+			expectedKey:       requestSlug + "|visitor-1",
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name:                  "duplicate click with redirect rules",
+			keyType:               interactor.IPUserAgentUniqueClickKey,
+			visitorID:             "visitor-1",
+			expectedKey:           requestSlug + "|" + ipAddress + "|" + userAgent,
+			handleDuplicateClicks: true,
+			duplicateRules:        duplicateRules,
+			expectedTargetURL:     "https://duplicate.example.com",
+		},
+		{
+			name:                  "duplicate click without redirect rules",
+			keyType:               interactor.VisitorUniqueClickKey,
+			expectedKey:           requestSlug + "|" + ipAddress + "|" + userAgent,
+			handleDuplicateClicks: true,
+			expectedError:         interactor.ErrDuplicateClick,
+		},
+		{
+			name:          "rejected click is not remembered",
+			keyType:       interactor.IPUserAgentUniqueClickKey,
+			expectedKey:   requestSlug + "|" + ipAddress + "|" + userAgent,
+			unique:        true,
+			allowedGeos:   entity.AllowedListType{"CA": true},
+			expectedError: interactor.ErrUnsupportedGeo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			clkRepo := mocks.NewMockClicksRepository(ctrl)
+			detector := mocks.NewMockUniqueClickDetectorInterface(ctrl)
+			trkRepo := mocks.NewMockTrackingLinksRepositoryInterface(ctrl)
+			ipParser := mocks.NewMockIPAddressParserInterface(ctrl)
+			uaParser := mocks.NewMockUserAgentParserInterface(ctrl)
+			srv := interactor.NewRedirectInteractor(
+				trkRepo,
+				ipParser,
+				uaParser,
+				[]interactor.ClickHandlerInterface{interactor.NewStoreClickHandler(clkRepo)},
+				interactor.WithUniqueClickDetector(detector, tt.keyType),
+			)
+
+			td := newTestData()
+			td.requestData.VisitorID = tt.visitorID
+
+			trkLink := &entity.TrackingLink{
+				IsActive:                       true,
+				IsCampaignActive:               true,
+				Slug:                           td.slug,
+				HandleDuplicateClicks:          tt.handleDuplicateClicks,
+				CampaignDuplicateRedirectRules: tt.duplicateRules,
+				AllowedGeos:                    tt.allowedGeos,
+				TargetURLTemplate:              redirectURL,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			detector.EXPECT().IsUnique(gomock.Any(), tt.expectedKey).Return(tt.unique, nil)
+			if tt.expectedError == nil {
+				detector.EXPECT().Remember(gomock.Any(), tt.expectedKey).Return(nil)
+				clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, click *entity.Click) error {
+					if click.IsUnique != tt.unique {
+						t.Errorf("unexpected click uniqueness. expected %v but got %v", tt.unique, click.IsUnique)
+					}
+
+					return nil
+				})
+			}
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_LanguageValidation(t *testing.T) {
+	tests := []struct {
+		name              string
+		acceptLanguage    string
+		redirectRules     *valueobject.RedirectRules
+		expectedTargetURL string
+		expectedLanguage  string
+		expectedError     error
+	}{
+		{
+			name:              "regional language matches primary language",
+			acceptLanguage:    "de-AT,de;q=0.9",
+			expectedTargetURL: redirectURL,
+			expectedLanguage:  "de",
+		},
+		{
+			name:              "less preferred language is allowed",
+			acceptLanguage:    "fr-FR,en;q=0.5",
+			expectedTargetURL: redirectURL,
+			expectedLanguage:  "fr",
+		},
+		{
+			name:           "language is not allowed",
+			acceptLanguage: "fr-FR,fr;q=0.9",
+			expectedError:  interactor.ErrUnsupportedLanguage,
+		},
+		{
+			name:              "language is not allowed with redirect rules",
+			acceptLanguage:    "es",
+			redirectRules:     &valueobject.RedirectRules{RedirectType: valueobject.LinkRedirectType, RedirectURL: "https://es.example.com"},
+			expectedTargetURL: "https://es.example.com",
+			expectedLanguage:  "es",
+		},
+		{
+			name:          "language is unknown",
+			expectedError: interactor.ErrUnsupportedLanguage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+			td.requestData.Headers["Accept-Language"] = []string{tt.acceptLanguage}
+
+			trkLink := &entity.TrackingLink{
+				IsActive:                      true,
+				IsCampaignActive:              true,
+				Slug:                          td.slug,
+				AllowedLanguages:              entity.AllowedListType{"de": true, "en": true},
+				CampaignLanguageRedirectRules: tt.redirectRules,
+				TargetURLTemplate:             redirectURL,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			if tt.expectedError == nil {
+				clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, click *entity.Click) error {
+					if click.Language != tt.expectedLanguage {
+						t.Errorf("unexpected click language. expected %s but got %s", tt.expectedLanguage, click.Language)
+					}
+
+					return nil
+				})
+			}
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_RegionAndCityValidation(t *testing.T) {
+	californiaGeo := &valueobject.GeoLocation{CountryCode: "US", Region: "CA", City: "San Francisco", PostalCode: "94107"}
+	texasGeo := &valueobject.GeoLocation{CountryCode: "US", Region: "TX", City: "Austin"}
+	cityRules := &valueobject.RedirectRules{RedirectType: valueobject.LinkRedirectType, RedirectURL: "https://city.example.com"}
+
+	tests := []struct {
+		name              string
+		geo               *valueobject.GeoLocation
+		allowedRegions    entity.AllowedListType
+		allowedCities     entity.AllowedListType
+		targetURLTemplate string
+		expectedTargetURL string
+		expectedError     error
+	}{
+		{
+			name:              "region is allowed",
+			geo:               californiaGeo,
+			allowedRegions:    entity.AllowedListType{"US-CA": true, "US-NY": true},
+			targetURLTemplate: redirectURL + "?region={region}&city={city}",
+			expectedTargetURL: redirectURL + "?region=CA&city=San+Francisco",
+		},
+		{
+			name:           "region is not allowed",
+			geo:            texasGeo,
+			allowedRegions: entity.AllowedListType{"CA": true},
+			expectedError:  interactor.ErrUnsupportedRegion,
+		},
+		{
+			name:              "city is not allowed with redirect rules",
+			geo:               texasGeo,
+			allowedCities:     entity.AllowedListType{"San Francisco": true},
+			expectedTargetURL: "https://city.example.com",
+		},
+		{
+			name:           "region is unknown",
+			geo:            &valueobject.GeoLocation{CountryCode: "US"},
+			allowedRegions: entity.AllowedListType{"US-CA": true},
+			expectedError:  interactor.ErrUnsupportedRegion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+
+			targetURLTemplate := tt.targetURLTemplate
+			if targetURLTemplate == "" {
+				targetURLTemplate = redirectURL
+			}
+
+			trkLink := &entity.TrackingLink{
+				IsActive:                  true,
+				IsCampaignActive:          true,
+				Slug:                      td.slug,
+				AllowedRegions:            tt.allowedRegions,
+				AllowedCities:             tt.allowedCities,
+				CampaignCityRedirectRules: cityRules,
+				TargetURLTemplate:         targetURLTemplate,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(tt.geo, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			if tt.expectedError == nil {
+				clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, click *entity.Click) error {
+					if click.Region != tt.geo.Region || click.City != tt.geo.City || click.PostalCode != tt.geo.PostalCode {
+						t.Errorf("unexpected click geo. expected %+v but got %s/%s/%s",
+							tt.geo, click.Region, click.City, click.PostalCode)
+					}
+
+					return nil
+				})
+			}
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_NetworkTargeting(t *testing.T) {
+	datacenterRule := &valueobject.TargetingRule{
+		Conditions: valueobject.TargetingConditions{
+			{Field: valueobject.ASNConditionField, Operator: valueobject.InOperator, Values: []string{"AS16509", "AS14061"}},
+		},
+		Action: &valueobject.RedirectRules{RedirectType: valueobject.NoRedirectType},
+	}
+	carrierRule := &valueobject.TargetingRule{
+		Conditions: valueobject.TargetingConditions{
+			{Field: valueobject.ConnectionTypeConditionField, Operator: valueobject.InOperator, Values: []string{"Cellular"}},
+		},
+		Action: &valueobject.RedirectRules{
+			RedirectType: valueobject.LinkRedirectType,
+			RedirectURL:  "https://mobile.example.com",
+		},
+	}
+
+	tests := []struct {
+		name              string
+		geo               *valueobject.GeoLocation
+		expectedTargetURL string
+		expectedError     error
+	}{
+		{
+			name:          "datacenter asn is blocked",
+			geo:           &valueobject.GeoLocation{CountryCode: countryCode, ASN: 16509, ASNOrganization: "AMAZON-02"},
+			expectedError: interactor.ErrBlockRedirect,
+		},
+		{
+			name:              "mobile carrier traffic",
+			geo:               &valueobject.GeoLocation{CountryCode: countryCode, ASN: 7018, ConnectionType: "Cellular"},
+			expectedTargetURL: "https://mobile.example.com",
+		},
+		{
+			name:              "network tokens",
+			geo:               &valueobject.GeoLocation{CountryCode: countryCode, ASN: 7922, ASNOrganization: "COMCAST", ISP: "Comcast", ConnectionType: "Cable/DSL"},
+			expectedTargetURL: redirectURL + "?asn=7922&org=COMCAST&isp=Comcast&type=Cable%2FDSL",
+		},
+		{
+			name:              "network data is unknown",
+			geo:               &valueobject.GeoLocation{CountryCode: countryCode},
+			expectedTargetURL: redirectURL + "?asn=&org=&isp=&type=",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+
+			trkLink := &entity.TrackingLink{
+				IsActive:          true,
+				IsCampaignActive:  true,
+				Slug:              td.slug,
+				TargetingRules:    []*valueobject.TargetingRule{datacenterRule, carrierRule},
+				TargetURLTemplate: redirectURL + "?asn={asn}&org={asn_org}&isp={isp}&type={connection_type}",
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(tt.geo, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			if tt.expectedError == nil {
+				clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, click *entity.Click) error {
+					if click.ASN != tt.geo.ASN || click.ConnectionType != tt.geo.ConnectionType {
+						t.Errorf("unexpected click network data. expected %d/%s but got %d/%s",
+							tt.geo.ASN, tt.geo.ConnectionType, click.ASN, click.ConnectionType)
+					}
+
+					return nil
+				})
+			}
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_IPLists(t *testing.T) {
+	mustCIDRList := func(cidrs ...string) valueobject.CIDRList {
+		list, err := valueobject.NewCIDRList(cidrs)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return list
+	}
+	ipRules := &valueobject.RedirectRules{RedirectType: valueobject.LinkRedirectType, RedirectURL: "https://blocked.example.com"}
+
+	tests := []struct {
+		name              string
+		allowedIPs        valueobject.CIDRList
+		deniedIPs         valueobject.CIDRList
+		globalLists       valueobject.IPLists
+		ipRules           *valueobject.RedirectRules
+		expectedTargetURL string
+		expectedError     error
+	}{
+		{
+			name:              "lists are not set",
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name:          "denied by tracking link list",
+			deniedIPs:     mustCIDRList(ipAddress + "/24"),
+			expectedError: interactor.ErrIPNotAllowed,
+		},
+		{
+			name:              "denied by tracking link list with redirect rules",
+			deniedIPs:         mustCIDRList(ipAddress + "/24"),
+			ipRules:           ipRules,
+			expectedTargetURL: "https://blocked.example.com",
+		},
+		{
+			name:          "denied by global list",
+			globalLists:   valueobject.IPLists{Denied: mustCIDRList("0.0.0.0/0", "::/0")},
+			ipRules:       &valueobject.RedirectRules{RedirectType: valueobject.NoRedirectType},
+			expectedError: interactor.ErrIPNotAllowed,
+		},
+		{
+			name:              "tracking link allow list takes precedence over global deny list",
+			allowedIPs:        mustCIDRList(ipAddress),
+			globalLists:       valueobject.IPLists{Denied: mustCIDRList("0.0.0.0/0")},
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name:              "global allow list takes precedence over global deny list",
+			globalLists:       valueobject.IPLists{Allowed: mustCIDRList(ipAddress), Denied: mustCIDRList("0.0.0.0/0")},
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name:              "other networks are not affected",
+			deniedIPs:         mustCIDRList("10.0.0.0/8", "2001:db8::/32"),
+			expectedTargetURL: redirectURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			clkRepo := mocks.NewMockClicksRepository(ctrl)
+			ipListsRepo := mocks.NewMockIPListsRepository(ctrl)
+			trkRepo := mocks.NewMockTrackingLinksRepositoryInterface(ctrl)
+			ipParser := mocks.NewMockIPAddressParserInterface(ctrl)
+			uaParser := mocks.NewMockUserAgentParserInterface(ctrl)
+			srv := interactor.NewRedirectInteractor(
+				trkRepo,
+				ipParser,
+				uaParser,
+				[]interactor.ClickHandlerInterface{interactor.NewStoreClickHandler(clkRepo)},
+				interactor.WithGlobalIPLists(ipListsRepo),
+			)
+
+			td := newTestData()
+
+			trkLink := &entity.TrackingLink{
+				IsActive:                true,
+				IsCampaignActive:        true,
+				Slug:                    td.slug,
+				AllowedIPs:              tt.allowedIPs,
+				DeniedIPs:               tt.deniedIPs,
+				CampaignIPRedirectRules: tt.ipRules,
+				TargetURLTemplate:       redirectURL,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipListsRepo.EXPECT().FindGlobalIPLists(gomock.Any()).Return(tt.globalLists).AnyTimes()
+			if tt.expectedError == nil {
+				// visitor lookup is skipped for blocked traffic
+				ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+				uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+				clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_TokenEncodingByPosition(t *testing.T) {
+	tests := []struct {
+		name              string
+		targetURLTemplate string
+		params            map[string][]string
+		expectedTargetURL string
+	}{
+		{
+			name:              "query",
+			targetURLTemplate: "https://example.com/track?ua={user_agent}",
+			expectedTargetURL: "https://example.com/track?ua=Mozilla%2F5.0+%28X11%3B+Linux+x86_64%29+AppleWebKit%2F537.36",
+		},
+		{
+			name:              "path",
+			targetURLTemplate: "https://example.com/track/{user_agent}",
+			expectedTargetURL: "https://example.com/track/Mozilla%2F5.0%20%28X11%3B%20Linux%20x86_64%29%20AppleWebKit%2F537.36",
+		},
+		{
+			name:              "fragment",
+			targetURLTemplate: "https://example.com/track#ref={referer}",
+			expectedTargetURL: "https://example.com/track#ref=https%3A%2F%2Freferrer.com",
+		},
+		{
+			name:              "host",
+			targetURLTemplate: "https://{country_code}.example.com/track",
+			expectedTargetURL: "https://US.example.com/track",
+		},
+		{
+			name:              "host with path",
+			targetURLTemplate: "https://{p1}.example.com/track",
+			params:            map[string][]string{"p1": {"evil.com/"}},
+			expectedTargetURL: "https://.example.com/track",
+		},
+		{
+			name:              "host with credentials",
+			targetURLTemplate: "https://{p1}example.com/track",
+			params:            map[string][]string{"p1": {"evil.com@"}},
+			expectedTargetURL: "https://example.com/track",
+		},
+		{
+			name:              "host with port and fragment",
+			targetURLTemplate: "https://sub{p1}.example.com/track",
+			params:            map[string][]string{"p1": {".evil.com:443#"}},
+			expectedTargetURL: "https://sub.example.com/track",
+		},
+		{
+			name:              "whole URL",
+			targetURLTemplate: "{referer}",
+			expectedTargetURL: referrer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+			if tt.params != nil {
+				td.requestData.Params = tt.params
+			}
+			trkLink := &entity.TrackingLink{
+				IsActive:          true,
+				IsCampaignActive:  true,
+				Slug:              td.slug,
+				TargetURLTemplate: tt.targetURLTemplate,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_DynamicTokens(t *testing.T) {
+	tests := []struct {
+		name              string
+		allowedTokenKeys  entity.AllowedListType
+		targetURLTemplate string
+		expectedTargetURL string
+	}{
+		{
+			name:              "allowed param",
+			allowedTokenKeys:  entity.AllowedListType{"param:utm_source": true},
+			targetURLTemplate: "https://example.com?src={param:utm_source}",
+			expectedTargetURL: "https://example.com?src=google+ads",
+		},
+		{
+			name:              "all params are allowed",
+			allowedTokenKeys:  entity.AllowedListType{"param:*": true},
+			targetURLTemplate: "https://example.com?src={param:utm_source}&sub={param:sub.id}",
+			expectedTargetURL: "https://example.com?src=google+ads&sub=42",
+		},
+		{
+			name:              "param is not allowed",
+			allowedTokenKeys:  entity.AllowedListType{"param:utm_medium": true, "param:utm_source": false},
+			targetURLTemplate: "https://example.com?src={param:utm_source}",
+			expectedTargetURL: "https://example.com?src=",
+		},
+		{
+			name:              "allowed header (case-insensitive)",
+			allowedTokenKeys:  entity.AllowedListType{"header:x-device-id": true},
+			targetURLTemplate: "https://example.com?device={header:X-Device-Id}",
+			expectedTargetURL: "https://example.com?device=device-1",
+		},
+		{
+			name:              "allowed cookie with modifier",
+			allowedTokenKeys:  entity.AllowedListType{"cookie:vid": true},
+			targetURLTemplate: "https://example.com?vid={cookie:vid|default:none}&x={cookie:missing|default:none}",
+			expectedTargetURL: "https://example.com?vid=visitor-1&x=none",
+		},
+		{
+			name:              "cookie is not allowed",
+			targetURLTemplate: "https://example.com?vid={cookie:vid}",
+			expectedTargetURL: "https://example.com?vid=",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+			td.requestData.Params["utm_source"] = []string{"google ads"}
+			td.requestData.Params["sub.id"] = []string{"42"}
+			td.requestData.Headers["X-Device-Id"] = []string{"device-1"}
+			td.requestData.Cookies = map[string]string{"vid": "visitor-1"}
+
+			trkLink := &entity.TrackingLink{
+				IsActive:          true,
+				IsCampaignActive:  true,
+				Slug:              td.slug,
+				AllowedTokenKeys:  tt.allowedTokenKeys,
+				TargetURLTemplate: tt.targetURLTemplate,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_ParamsPassThrough(t *testing.T) {
+	tests := []struct {
+		name              string
+		policy            valueobject.ParamsPassThrough
+		targetURLTemplate string
+		expectedTargetURL string
+	}{
+		{
+			name:              "pass-through is disabled",
+			targetURLTemplate: "https://example.com/offer?aff=1",
+			expectedTargetURL: "https://example.com/offer?aff=1",
+		},
+		{
+			name:              "all params except reserved and consumed ones",
+			policy:            valueobject.ParamsPassThrough{Mode: valueobject.AllPassThroughMode},
+			targetURLTemplate: "https://example.com/offer?src={param:utm_source}",
+			expectedTargetURL: "https://example.com/offer?src=google&sub_id=a+b&utm_medium=cpc",
+		},
+		{
+			name: "allow-list",
+			policy: valueobject.ParamsPassThrough{
+				Mode:   valueobject.AllowListPassThroughMode,
+				Params: []string{"utm_medium", "gclid"},
+			},
+			targetURLTemplate: "https://example.com/offer",
+			expectedTargetURL: "https://example.com/offer?gclid=abc&utm_medium=cpc",
+		},
+		{
+			name: "deny-list",
+			policy: valueobject.ParamsPassThrough{
+				Mode:   valueobject.DenyListPassThroughMode,
+				Params: []string{"utm_source"},
+			},
+			targetURLTemplate: "https://example.com/offer#top",
+			expectedTargetURL: "https://example.com/offer?sub_id=a+b&utm_medium=cpc#top",
+		},
+		{
+			name: "template value is kept on conflict by default",
+			policy: valueobject.ParamsPassThrough{
+				Mode:   valueobject.AllowListPassThroughMode,
+				Params: []string{"utm_medium"},
+			},
+			targetURLTemplate: "https://example.com/offer?utm_medium=email&aff=1",
+			expectedTargetURL: "https://example.com/offer?utm_medium=email&aff=1",
+		},
+		{
+			name: "template value is overridden on conflict",
+			policy: valueobject.ParamsPassThrough{
+				Mode:       valueobject.AllowListPassThroughMode,
+				Params:     []string{"utm_medium"},
+				OnConflict: valueobject.OverrideConflictStrategy,
+			},
+			targetURLTemplate: "https://example.com/offer?utm_medium=email&aff=1",
+			expectedTargetURL: "https://example.com/offer?aff=1&utm_medium=cpc",
+		},
+		{
+			name: "values are appended on conflict",
+			policy: valueobject.ParamsPassThrough{
+				Mode:       valueobject.AllowListPassThroughMode,
+				Params:     []string{"utm_medium"},
+				OnConflict: valueobject.AppendConflictStrategy,
+			},
+			targetURLTemplate: "https://example.com/offer?utm_medium=email",
+			expectedTargetURL: "https://example.com/offer?utm_medium=email&utm_medium=cpc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+			td.requestData.Params["utm_source"] = []string{"google"}
+			td.requestData.Params["utm_medium"] = []string{"cpc"}
+			td.requestData.Params["sub_id"] = []string{"a b"}
+			td.requestData.Params["gclid"] = []string{"abc"}
+
+			trkLink := &entity.TrackingLink{
+				IsActive:          true,
+				IsCampaignActive:  true,
+				Slug:              td.slug,
+				AllowedTokenKeys:  entity.AllowedListType{"param:utm_source": true},
+				ParamsPassThrough: tt.policy,
+				TargetURLTemplate: tt.targetURLTemplate,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_AdClickIDs(t *testing.T) {
+	tests := []struct {
+		name                string
+		forwardedAdClickIDs entity.AllowedListType
+		targetURLTemplate   string
+		expectedTargetURL   string
+	}{
+		{
+			name:              "click identifiers are not forwarded by default",
+			targetURLTemplate: "https://example.com/offer",
+			expectedTargetURL: "https://example.com/offer",
+		},
+		{
+			name:                "enabled click identifiers are forwarded",
+			forwardedAdClickIDs: entity.AllowedListType{"gclid": true, "fbclid": true, "msclkid": false},
+			targetURLTemplate:   "https://example.com/offer?aff=1",
+			expectedTargetURL:   "https://example.com/offer?aff=1&fbclid=fb.1&gclid=Cj0KCQ",
+		},
+		{
+			name:                "template value is kept",
+			forwardedAdClickIDs: entity.AllowedListType{"gclid": true},
+			targetURLTemplate:   "https://example.com/offer?gclid=static",
+			expectedTargetURL:   "https://example.com/offer?gclid=static",
+		},
+		{
+			name:                "unknown params are not forwarded",
+			forwardedAdClickIDs: entity.AllowedListType{"sub_id": true},
+			targetURLTemplate:   "https://example.com/offer",
+			expectedTargetURL:   "https://example.com/offer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+			td.requestData.Params["gclid"] = []string{"Cj0KCQ"}
+			td.requestData.Params["fbclid"] = []string{"fb.1"}
+			td.requestData.Params["msclkid"] = []string{"ms-1"}
+			td.requestData.Params["sub_id"] = []string{"42"}
+
+			trkLink := &entity.TrackingLink{
+				IsActive:            true,
+				IsCampaignActive:    true,
+				Slug:                td.slug,
+				ForwardedAdClickIDs: tt.forwardedAdClickIDs,
+				TargetURLTemplate:   tt.targetURLTemplate,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, click *entity.Click) error {
+				expected := map[string]string{"gclid": "Cj0KCQ", "fbclid": "fb.1", "msclkid": "ms-1"}
+				if len(click.AdClickIDs) != len(expected) {
+					t.Errorf("unexpected click identifiers: %v", click.AdClickIDs)
+				}
+				for param, value := range expected {
+					if click.AdClickIDs[param] != value {
+						t.Errorf("unexpected %s value. expected %s but got %s", param, value, click.AdClickIDs[param])
+					}
+				}
+				if click.GCLID != "Cj0KCQ" {
+					t.Errorf("unexpected gclid. expected %s but got %s", "Cj0KCQ", click.GCLID)
+				}
+
+				return nil
+			})
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_DeeplinkValidation(t *testing.T) {
+	deeplinkRules := &valueobject.RedirectRules{RedirectType: valueobject.LinkRedirectType, RedirectURL: "https://rejected.example.com"}
+
+	tests := []struct {
+		name              string
+		deeplink          string
+		allowedSchemes    entity.AllowedListType
+		allowedDomains    entity.AllowedListType
+		advertiserDomains entity.AllowedListType
+		deeplinkRules     *valueobject.RedirectRules
+		expectedTargetURL string
+		expectedError     error
+	}{
+		{
+			name:              "target URL host is allowed by default",
+			deeplink:          "https://example.com/product/1",
+			expectedTargetURL: "https://example.com/product/1",
+		},
+		{
+			name:          "other hosts are rejected by default",
+			deeplink:      "https://evil.com/phishing",
+			expectedError: interactor.ErrDeeplinkNotAllowed,
+		},
+		{
+			name:              "allowed subdomain pattern",
+			deeplink:          "https://m.shop.com/product/1",
+			allowedDomains:    entity.AllowedListType{"*.shop.com": true},
+			expectedTargetURL: "https://m.shop.com/product/1",
+		},
+		{
+			name:           "subdomain pattern doesn't match similar domains",
+			deeplink:       "https://evilshop.com/product/1",
+			allowedDomains: entity.AllowedListType{"*.shop.com": true},
+			deeplinkRules:  deeplinkRules,
+			// rejected deeplink redirect rules are applied
+			expectedTargetURL: "https://rejected.example.com",
+		},
+		{
+			name:              "advertiser domain",
+			deeplink:          "https://brand.com/sale",
+			allowedDomains:    entity.AllowedListType{"shop.com": true},
+			advertiserDomains: entity.AllowedListType{"brand.com": true},
+			expectedTargetURL: "https://brand.com/sale",
+		},
+		{
+			name:           "user info is rejected",
+			deeplink:       "https://shop.com@evil.com/",
+			allowedDomains: entity.AllowedListType{"shop.com": true, "evil.com": false},
+			expectedError:  interactor.ErrDeeplinkNotAllowed,
+		},
+		{
+			name:           "javascript scheme is always rejected",
+			deeplink:       "javascript:alert(1)",
+			allowedSchemes: entity.AllowedListType{"javascript": true},
+			expectedError:  interactor.ErrDeeplinkNotAllowed,
+		},
+		{
+			name:          "app scheme is rejected by default",
+			deeplink:      "myapp://product/1",
+			expectedError: interactor.ErrDeeplinkNotAllowed,
+		},
+		{
+			name:              "allowed app scheme",
+			deeplink:          "myapp://product/1",
+			allowedSchemes:    entity.AllowedListType{"myapp": true},
+			expectedTargetURL: "myapp://product/1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+			td.requestData.Params["deeplink"] = []string{tt.deeplink}
+
+			trkLink := &entity.TrackingLink{
+				IsActive:                      true,
+				IsCampaignActive:              true,
+				Slug:                          td.slug,
+				AllowDeeplink:                 true,
+				AllowedDeeplinkSchemes:        tt.allowedSchemes,
+				AllowedDeeplinkDomains:        tt.allowedDomains,
+				AdvertiserDeeplinkDomains:     tt.advertiserDomains,
+				CampaignDeeplinkRedirectRules: tt.deeplinkRules,
+				TargetURLTemplate:             "https://example.com/default",
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			if tt.expectedError == nil {
+				clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+			if tt.deeplinkRules != nil && !errors.Is(result.Reason, interactor.ErrDeeplinkNotAllowed) {
+				t.Errorf("unexpected reason. expected %v but got %v", interactor.ErrDeeplinkNotAllowed, result.Reason)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_LandingPageRotation(t *testing.T) {
+	tests := []struct {
+		name              string
+		landingParam      string
+		landingPages      map[string]*entity.LandingPage
+		expectedTargetURL string
+		expectedLandingID string
+	}{
+		{
+			name:              "target url is used without active landing pages",
+			landingPages:      map[string]*entity.LandingPage{"lp1": {ID: "lp1", TargetURL: "https://lp1.com", Weight: 1}},
+			expectedTargetURL: redirectURL,
+		},
+		{
+			name: "only active landing pages with positive weight are rotated",
+			landingPages: map[string]*entity.LandingPage{
+				"lp1": {ID: "lp1", TargetURL: "https://lp1.com", Weight: 10},
+				"lp2": {ID: "lp2", TargetURL: "https://lp2.com", Weight: 0, IsActive: true},
+				"lp3": {ID: "lp3", TargetURL: "https://lp3.com", Weight: 5, IsActive: true},
+			},
+			expectedTargetURL: "https://lp3.com",
+			expectedLandingID: "lp3",
+		},
+		{
+			name:         "requested landing page is not rotated",
+			landingParam: "lp1",
+			landingPages: map[string]*entity.LandingPage{
+				"lp1": {ID: "lp1", TargetURL: "https://lp1.com"},
+				"lp3": {ID: "lp3", TargetURL: "https://lp3.com", Weight: 5, IsActive: true},
+			},
+			expectedTargetURL: "https://lp1.com",
+			expectedLandingID: "lp1",
+		},
+		{
+			name:         "unknown landing page falls back to target url",
+			landingParam: "unknown",
+			landingPages: map[string]*entity.LandingPage{
+				"lp3": {ID: "lp3", TargetURL: "https://lp3.com", Weight: 5, IsActive: true},
+			},
+			expectedTargetURL: redirectURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+			if tt.landingParam != "" {
+				td.requestData.Params["landing"] = []string{tt.landingParam}
+			}
+
+			trkLink := &entity.TrackingLink{
+				IsActive:          true,
+				IsCampaignActive:  true,
+				Slug:              td.slug,
+				TargetURLTemplate: redirectURL,
+				LandingPages:      tt.landingPages,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, click *entity.Click) error {
+				if click.LandingID != tt.expectedLandingID {
+					t.Errorf("unexpected landing id. expected %s but got %s", tt.expectedLandingID, click.LandingID)
+				}
+
+				return nil
+			})
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_ErrorFallbackURLs(t *testing.T) {
+	fallbackURLs := map[string]string{
+		interactor.NotFoundErrorClass:      "https://fallback.com/not-found",
+		interactor.DisabledErrorClass:      "https://fallback.com/disabled",
+		interactor.GeoErrorClass:           "https://fallback.com/geo",
+		interactor.MisconfiguredErrorClass: "https://fallback.com/misconfigured",
+	}
+	misconfiguredLink := func(advertiserFallbackURLs map[string]string) *entity.TrackingLink {
+		return &entity.TrackingLink{
+			IsActive:                      true,
+			IsCampaignActive:              true,
+			Slug:                          requestSlug,
+			AllowedProtocols:              entity.AllowedListType{"ftp": true},
+			CampaignProtocolRedirectRules: &valueobject.RedirectRules{RedirectType: "unknown"},
+			AdvertiserFallbackURLs:        advertiserFallbackURLs,
+		}
+	}
+
+	tests := []struct {
+		name              string
+		trkLink           *entity.TrackingLink
+		fallbackURLs      map[string]string
+		fallbackURL       string
+		expectedTargetURL string
+		expectedError     error
+	}{
+		{
+			name:              "not found",
+			fallbackURLs:      fallbackURLs,
+			expectedTargetURL: "https://fallback.com/not-found",
+		},
+		{
+			name:              "disabled",
+			trkLink:           &entity.TrackingLink{Slug: requestSlug},
+			fallbackURLs:      fallbackURLs,
+			expectedTargetURL: "https://fallback.com/disabled",
+		},
+		{
+			name: "advertiser fallback url takes precedence",
+			trkLink: &entity.TrackingLink{
+				Slug:                   requestSlug,
+				AdvertiserFallbackURLs: map[string]string{interactor.DisabledErrorClass: "https://advertiser.com/disabled"},
+			},
+			fallbackURLs:      fallbackURLs,
+			expectedTargetURL: "https://advertiser.com/disabled",
+		},
+		{
+			name: "geo",
+			trkLink: &entity.TrackingLink{
+				IsActive:         true,
+				IsCampaignActive: true,
+				Slug:             requestSlug,
+				AllowedGeos:      entity.AllowedListType{"CA": true},
+			},
+			fallbackURLs:      fallbackURLs,
+			expectedTargetURL: "https://fallback.com/geo",
+		},
+		{
+			name:              "misconfigured",
+			trkLink:           misconfiguredLink(nil),
+			fallbackURLs:      fallbackURLs,
+			fallbackURL:       "https://fallback.com/redirect",
+			expectedTargetURL: "https://fallback.com/misconfigured",
+		},
+		{
+			name:              "misconfigured without misconfigured fallback url",
+			trkLink:           misconfiguredLink(nil),
+			fallbackURL:       "https://fallback.com/redirect",
+			expectedTargetURL: "https://fallback.com/redirect",
+		},
+		{
+			name:              "advertiser misconfigured fallback url takes precedence",
+			trkLink:           misconfiguredLink(map[string]string{interactor.MisconfiguredErrorClass: "https://advertiser.com/misconfigured"}),
+			fallbackURLs:      fallbackURLs,
+			fallbackURL:       "https://fallback.com/redirect",
+			expectedTargetURL: "https://advertiser.com/misconfigured",
+		},
+		{
+			name: "error is returned without fallback url of the class",
+			trkLink: &entity.TrackingLink{
+				IsActive:         true,
+				IsCampaignActive: true,
+				Slug:             requestSlug,
+				AllowedProtocols: entity.AllowedListType{"ftp": true},
+			},
+			fallbackURLs:  fallbackURLs,
+			expectedError: interactor.ErrUnsupportedProtocol,
+		},
+		{
+			name:          "error is returned without fallback urls",
+			expectedError: interactor.ErrTrackingLinkNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			clkRepo := mocks.NewMockClicksRepository(ctrl)
+			trkRepo := mocks.NewMockTrackingLinksRepositoryInterface(ctrl)
+			ipParser := mocks.NewMockIPAddressParserInterface(ctrl)
+			uaParser := mocks.NewMockUserAgentParserInterface(ctrl)
+
+			srv := interactor.NewRedirectInteractor(
+				trkRepo,
+				ipParser,
+				uaParser,
+				[]interactor.ClickHandlerInterface{interactor.NewStoreClickHandler(clkRepo)},
+				interactor.WithErrorFallbackURLs(tt.fallbackURLs),
+				interactor.WithFallbackURL(tt.fallbackURL),
+			)
+
+			td := newTestData()
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(tt.trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil).AnyTimes()
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil).AnyTimes()
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("unexpected error. expected %v but got %v", tt.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TargetURL != tt.expectedTargetURL {
+				t.Errorf("unexpected target url. expected %s but got %s", tt.expectedTargetURL, result.TargetURL)
+			}
+			if interactor.ErrorClass(result.Reason) == "" {
+				t.Errorf("expected rejection reason to be set, got %v", result.Reason)
+			}
+
+			// fallback redirects are not registered as clicks
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_RedirectMethod(t *testing.T) {
+	tests := []struct {
+		name           string
+		links          map[string]*entity.TrackingLink
+		expectedMethod string
+	}{
+		{
+			name: "tracking link method",
+			links: map[string]*entity.TrackingLink{
+				"a": {IsActive: true, IsCampaignActive: true, Slug: "a", TargetURLTemplate: redirectURL, RedirectMethod: valueobject.FoundRedirectMethod},
+			},
+			expectedMethod: valueobject.FoundRedirectMethod,
+		},
+		{
+			name: "tracking link method is used by rules without method",
+			links: map[string]*entity.TrackingLink{
+				"a": {
+					IsActive:                      true,
+					Slug:                          "a",
+					RedirectMethod:                valueobject.MetaRefreshRedirectMethod,
+					CampaignDisabledRedirectRules: &valueobject.RedirectRules{RedirectType: valueobject.LinkRedirectType, RedirectURL: redirectURL},
+				},
+			},
+			expectedMethod: valueobject.MetaRefreshRedirectMethod,
+		},
+		{
+			name: "rules method overrides tracking link method",
+			links: map[string]*entity.TrackingLink{
+				"a": {
+					IsActive:       true,
+					Slug:           "a",
+					RedirectMethod: valueobject.FoundRedirectMethod,
+					CampaignDisabledRedirectRules: &valueobject.RedirectRules{
+						RedirectType:   valueobject.LinkRedirectType,
+						RedirectURL:    redirectURL,
+						RedirectMethod: valueobject.JavaScriptRedirectMethod,
+					},
+				},
+			},
+			expectedMethod: valueobject.JavaScriptRedirectMethod,
+		},
+		{
+			name: "target tracking link method is used for slug redirects",
+			links: map[string]*entity.TrackingLink{
+				"a": {
+					IsActive:                      true,
+					Slug:                          "a",
+					RedirectMethod:                valueobject.FoundRedirectMethod,
+					CampaignDisabledRedirectRules: &valueobject.RedirectRules{RedirectType: valueobject.SlugRedirectType, RedirectSlug: "b"},
+				},
+				"b": {IsActive: true, IsCampaignActive: true, Slug: "b", TargetURLTemplate: redirectURL, RedirectMethod: valueobject.TemporaryRedirectMethod},
+			},
+			expectedMethod: valueobject.TemporaryRedirectMethod,
+		},
+		{
+			name: "rules method overrides target tracking link method",
+			links: map[string]*entity.TrackingLink{
+				"a": {
+					IsActive: true,
+					Slug:     "a",
+					CampaignDisabledRedirectRules: &valueobject.RedirectRules{
+						RedirectType:   valueobject.SlugRedirectType,
+						RedirectSlug:   "b",
+						RedirectMethod: valueobject.PermanentRedirectMethod,
+					},
+				},
+				"b": {IsActive: true, IsCampaignActive: true, Slug: "b", TargetURLTemplate: redirectURL, RedirectMethod: valueobject.TemporaryRedirectMethod},
+			},
+			expectedMethod: valueobject.PermanentRedirectMethod,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+
+			trkRepo.EXPECT().
+				FindTrackingLink(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, slug string) *entity.TrackingLink {
+					return tt.links[slug]
+				}).
+				AnyTimes()
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil).AnyTimes()
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil).AnyTimes()
+			clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+			result, err := srv.Redirect(context.Background(), "a", td.requestData)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Method != tt.expectedMethod {
+				t.Errorf("unexpected redirect method. expected %s but got %s", tt.expectedMethod, result.Method)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_CloakReferrer(t *testing.T) {
+	for _, cloak := range []bool{true, false} {
+		t.Run(fmt.Sprintf("cloak referrer %t", cloak), func(t *testing.T) {
+			ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+			defer ctrl.Finish()
+
+			td := newTestData()
+
+			trkLink := &entity.TrackingLink{
+				IsActive:          true,
+				IsCampaignActive:  true,
+				Slug:              td.slug,
+				TargetURLTemplate: redirectURL,
+				CloakReferrer:     cloak,
+			}
+
+			trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+			ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+			uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+			clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, click *entity.Click) error {
+				if click.IsCloaked != cloak {
+					t.Errorf("unexpected click cloaked flag. expected %t but got %t", cloak, click.IsCloaked)
+				}
+				if click.Referer != td.requestData.Referer {
+					t.Errorf("unexpected click referer. expected %s but got %s", td.requestData.Referer, click.Referer)
+				}
+
+				return nil
+			})
+
+			result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Cloaked != cloak {
+				t.Errorf("unexpected result cloaked flag. expected %t but got %t", cloak, result.Cloaked)
+			}
+
+			<-result.OutputCh
+		})
+	}
+}
+
+func TestRedirectInteractor_Redirect_Interstitial(t *testing.T) {
+	ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+	defer ctrl.Finish()
+
+	td := newTestData()
+	td.requestData.Params["utm_source"] = []string{"news letter"}
+
+	trkLink := &entity.TrackingLink{
+		IsActive:             true,
+		IsCampaignActive:     true,
+		Slug:                 td.slug,
+		TargetURLTemplate:    redirectURL + "?country={country_code}",
+		InterstitialTemplate: "age_gate",
+		AllowedTokenKeys:     entity.AllowedListType{"param:utm_source": true},
+	}
+
+	trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(trkLink)
+	ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+	uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+	clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+	result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-result.OutputCh
+
+	if result.TargetURL != redirectURL+"?country="+countryCode {
+		t.Errorf("unexpected target url %s", result.TargetURL)
+	}
+	if result.Interstitial == nil || result.Interstitial.Template != "age_gate" {
+		t.Fatalf("unexpected interstitial %v", result.Interstitial)
+	}
+
+	tokens := map[string]string{
+		"country_code":                  countryCode,
+		"p1":                            td.requestData.Params["p1"][0],
+		"param:utm_source":              "news letter",
+		"param:utm_campaign|default:na": "na",
+		"cookie:vid":                    "",
+		"referer|sha256|default:x":      fmt.Sprintf("%x", sha256.Sum256([]byte(td.requestData.Referer))),
+	}
+	for expr, expected := range tokens {
+		if value := result.Interstitial.Token(expr); value != expected {
+			t.Errorf("unexpected %s token value. expected %q but got %q", expr, expected, value)
+		}
+	}
+}
+
+func TestRedirectInteractor_Redirect_WithoutInterstitial(t *testing.T) {
+	ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+	defer ctrl.Finish()
+
+	td := newTestData()
+
+	trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(&entity.TrackingLink{
+		IsActive:          true,
+		IsCampaignActive:  true,
+		Slug:              td.slug,
+		TargetURLTemplate: redirectURL,
+	})
+	ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: countryCode}, nil)
+	uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+	clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+	result, err := srv.Redirect(context.Background(), td.slug, td.requestData)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-result.OutputCh
+
+	if result.Interstitial != nil {
+		t.Errorf("unexpected interstitial %v", result.Interstitial)
+	}
+}
+
+func TestRedirectInteractor_Redirect_ClickOutlivesRequest(t *testing.T) {
+	ctrl, srv, trkRepo, ipParser, uaParser, clkRepo := setupTest(t)
+	defer ctrl.Finish()
+
+	td := newTestData()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	trkRepo.EXPECT().FindTrackingLink(gomock.Any(), td.slug).Return(&entity.TrackingLink{
+		IsActive:          true,
+		IsCampaignActive:  true,
+		Slug:              td.slug,
+		TargetURLTemplate: redirectURL,
+	})
+	ipParser.EXPECT().Parse(gomock.Any()).Return(&valueobject.GeoLocation{CountryCode: td.countryCode}, nil)
+	uaParser.EXPECT().Parse(gomock.Any()).Return(td.userAgent, nil)
+	clkRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ *entity.Click) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("expected click processing to be limited in time")
+		}
+
+		return ctx.Err()
+	})
+
+	result, err := srv.Redirect(ctx, td.slug, td.requestData)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the response is sent and the request is finished before the click is processed
+	cancel()
+
+	for clickResult := range result.OutputCh {
+		if clickResult.Err != nil {
+			t.Errorf("expected click to be stored after the request is finished, got %v", clickResult.Err)
+		}
+	}
+}
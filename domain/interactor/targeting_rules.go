@@ -0,0 +1,225 @@
+// Package interactor contains all use-case interactors preformed by the application.
+package interactor
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/lroman242/redirector/domain/dto"
+	"github.com/lroman242/redirector/domain/entity"
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+// targetingRule is a rule evaluated by the interactor together with the error
+// reported when the rule blocks the traffic (or has no redirect rules).
+type targetingRule struct {
+	*valueobject.TargetingRule
+	err error
+	// matches overrides conditions of the rule when the check can't be described by targeting conditions
+	matches func(attrs *valueobject.TargetingAttributes) bool
+}
+
+// Matches function checks if the provided attributes satisfy the rule.
+func (tr targetingRule) Matches(attrs *valueobject.TargetingAttributes) bool {
+	if tr.matches != nil {
+		return tr.matches(attrs)
+	}
+
+	return tr.TargetingRule.Matches(attrs)
+}
+
+// compileTargetingRules function builds the ordered list of rules evaluated for the tracking link.
+// Rules compiled from the fixed tracking link restrictions go first (bots, duplicate clicks, protocol, geo, region,
+// city, device, OS, language, deeplink, schedule, campaign overage and campaign state), followed by the rules stored
+// with the tracking link. Stored rules route only the traffic passing all restrictions, so even a catch-all rule
+// never sends traffic to a disabled or capped campaign, outside of the schedule, or lets blocked bots through.
+func compileTargetingRules(trackingLink *entity.TrackingLink) []targetingRule {
+	rules := make([]targetingRule, 0, len(trackingLink.TargetingRules)+13)
+
+	if !trackingLink.AllowBots {
+		rules = append(rules, targetingRule{
+			TargetingRule: &valueobject.TargetingRule{
+				Conditions: valueobject.TargetingConditions{{
+					Field:    valueobject.BotConditionField,
+					Operator: valueobject.InOperator,
+					Values:   []string{"true"},
+				}},
+				Action: trackingLink.CampaignBotsRedirectRules,
+			},
+			err: ErrBotsNotAllowed,
+		})
+	}
+
+	if trackingLink.HandleDuplicateClicks {
+		rules = append(rules, targetingRule{
+			TargetingRule: &valueobject.TargetingRule{
+				Conditions: valueobject.TargetingConditions{{
+					Field:    valueobject.UniqueConditionField,
+					Operator: valueobject.InOperator,
+					Values:   []string{"false"},
+				}},
+				Action: trackingLink.CampaignDuplicateRedirectRules,
+			},
+			err: ErrDuplicateClick,
+		})
+	}
+
+	restrictions := []struct {
+		field   string
+		allowed entity.AllowedListType
+		action  *valueobject.RedirectRules
+		err     error
+	}{
+		{valueobject.ProtocolConditionField, trackingLink.AllowedProtocols, trackingLink.CampaignProtocolRedirectRules, ErrUnsupportedProtocol},
+		{valueobject.CountryConditionField, trackingLink.AllowedGeos, trackingLink.CampaignGeoRedirectRules, ErrUnsupportedGeo},
+		{valueobject.RegionConditionField, trackingLink.AllowedRegions, trackingLink.CampaignRegionRedirectRules, ErrUnsupportedRegion},
+		{valueobject.CityConditionField, trackingLink.AllowedCities, trackingLink.CampaignCityRedirectRules, ErrUnsupportedCity},
+		{valueobject.DeviceConditionField, trackingLink.AllowedDevices, trackingLink.CampaignDevicesRedirectRules, ErrUnsupportedDevice},
+		{valueobject.OSConditionField, trackingLink.AllowedOS, trackingLink.CampaignOSRedirectRules, ErrUnsupportedOS},
+		{
+			valueobject.LanguageConditionField,
+			trackingLink.AllowedLanguages,
+			trackingLink.CampaignLanguageRedirectRules,
+			ErrUnsupportedLanguage,
+		},
+	}
+
+	for _, restriction := range restrictions {
+		if len(restriction.allowed) == 0 {
+			continue
+		}
+
+		rules = append(rules, targetingRule{
+			TargetingRule: &valueobject.TargetingRule{
+				Conditions: valueobject.TargetingConditions{{
+					Field:    restriction.field,
+					Operator: valueobject.NotInOperator,
+					Values:   allowedValues(restriction.field, restriction.allowed),
+				}},
+				Action: restriction.action,
+			},
+			err: restriction.err,
+		})
+	}
+
+	if trackingLink.AllowDeeplink {
+		rules = append(rules, targetingRule{
+			TargetingRule: &valueobject.TargetingRule{Action: trackingLink.CampaignDeeplinkRedirectRules},
+			err:           ErrDeeplinkNotAllowed,
+			matches: func(attrs *valueobject.TargetingAttributes) bool {
+				deeplink := requestedDeeplink(trackingLink, attrs.Params)
+				return deeplink != "" && validateDeeplink(trackingLink, deeplink) != nil
+			},
+		})
+	}
+
+	if !trackingLink.Schedule.IsEmpty() {
+		schedule := trackingLink.Schedule
+		rules = append(rules, targetingRule{
+			TargetingRule: &valueobject.TargetingRule{Action: trackingLink.CampaignScheduleRedirectRules},
+			err:           ErrOutOfSchedule,
+			matches: func(attrs *valueobject.TargetingAttributes) bool {
+				return !schedule.Contains(attrs.Time)
+			},
+		})
+	}
+
+	if trackingLink.IsCampaignOveraged {
+		rules = append(rules, targetingRule{
+			TargetingRule: &valueobject.TargetingRule{Action: trackingLink.CampaignOverageRedirectRules},
+		})
+	}
+
+	if !trackingLink.IsCampaignActive {
+		rules = append(rules, targetingRule{
+			TargetingRule: &valueobject.TargetingRule{Action: trackingLink.CampaignDisabledRedirectRules},
+		})
+	}
+
+	for _, rule := range trackingLink.TargetingRules {
+		if rule != nil {
+			rules = append(rules, targetingRule{TargetingRule: rule})
+		}
+	}
+
+	return rules
+}
+
+// allowedValues function returns the list of enabled values from the allowed list.
+func allowedValues(field string, allowedList entity.AllowedListType) []string {
+	values := make([]string, 0, len(allowedList))
+	for value, allowed := range allowedList {
+		if !allowed {
+			continue
+		}
+
+		if field == valueobject.ProtocolConditionField {
+			value = normalizeProtocol(value)
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// makeTargetingAttributes function collects visitor attributes used to evaluate targeting rules.
+func makeTargetingAttributes(
+	requestData *dto.RedirectRequestData,
+	ua *valueobject.UserAgent,
+	geo *valueobject.GeoLocation,
+	unique bool,
+) *valueobject.TargetingAttributes {
+	return &valueobject.TargetingAttributes{
+		Country:         geo.CountryCode,
+		Region:          geo.RegionCode(),
+		City:            geo.City,
+		ASN:             geo.ASN,
+		ASNOrganization: geo.ASNOrganization,
+		ISP:             geo.ISP,
+		ConnectionType:  geo.ConnectionType,
+		Device:          ua.Device,
+		OS:              ua.Platform,
+		Browser:         ua.Browser,
+		Referer:         requestData.Referer,
+		Protocol:        normalizeProtocol(requestData.Protocol),
+		Bot:             ua.Bot,
+		Unique:          unique,
+		Params:          requestData.Params,
+		Languages:       acceptedLanguages(requestData),
+		Time:            requestTime(requestData),
+	}
+}
+
+// acceptedLanguages function returns languages accepted by the visitor in order of preference.
+// Primary language subtags are added after regional tags, so "en" allow-lists match "en-US" visitors.
+func acceptedLanguages(requestData *dto.RedirectRequestData) []string {
+	languages := make([]string, 0)
+	for _, tag := range valueobject.ParseAcceptLanguage(http.Header(requestData.Headers).Get("Accept-Language")) {
+		languages = append(languages, tag)
+		if primary := valueobject.PrimaryLanguage(tag); primary != tag {
+			languages = append(languages, primary)
+		}
+	}
+
+	return languages
+}
+
+// preferredLanguage function returns the primary subtag of the visitor's most preferred language, might be empty.
+func preferredLanguage(requestData *dto.RedirectRequestData) string {
+	tags := valueobject.ParseAcceptLanguage(http.Header(requestData.Headers).Get("Accept-Language"))
+	if len(tags) == 0 {
+		return ""
+	}
+
+	return valueobject.PrimaryLanguage(tags[0])
+}
+
+// requestTime function returns the moment the request was received, current time is used if it's not set.
+func requestTime(requestData *dto.RedirectRequestData) time.Time {
+	if requestData.Time.IsZero() {
+		return time.Now()
+	}
+
+	return requestData.Time
+}
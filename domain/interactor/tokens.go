@@ -0,0 +1,237 @@
+package interactor
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lroman242/redirector/domain/dto"
+	"github.com/lroman242/redirector/domain/entity"
+)
+
+// tokenPattern matches URL template tokens ("{p1}", "{{user_agent}}", "{param:utm_source|default:none}").
+const tokenPattern = `{({)?(\w+(?::[\w.\-]+)?)((?:\|[^{}|]*)*)(})?}`
+
+// Predefined sources of dynamic tokens ("{param:utm_source}", "{header:X-Device-Id}", "{cookie:vid}").
+const (
+	paramTokenSource  = "param"
+	headerTokenSource = "header"
+	cookieTokenSource = "cookie"
+
+	// anyTokenKey allows all keys of the source, e.g. "param:*"
+	anyTokenKey = "*"
+)
+
+// Predefined token modifiers.
+const (
+	// urlEncodeModifier escapes the value for usage in the URL query, contextual encoding is skipped.
+	urlEncodeModifier = "urlencode"
+	// rawModifier disables contextual encoding, same as the double-brace form ("{{user_agent}}").
+	rawModifier = "raw"
+	// defaultModifier replaces empty value with the modifier argument ("{p1|default:none}").
+	defaultModifier = "default"
+	// sha256Modifier replaces the value with its hex-encoded SHA-256 hash.
+	sha256Modifier = "sha256"
+	// base64Modifier replaces the value with its base64 representation.
+	base64Modifier = "base64"
+	// formatModifier defines Go time layout used by date tokens ("{date|format:20060102}").
+	formatModifier = "format"
+)
+
+// urlPart identifies the part of the URL a token is placed in.
+type urlPart int
+
+const (
+	// urlPartURL is the very beginning of the URL template, values are used as the whole URL or its scheme
+	// and they are not encoded there.
+	urlPartURL urlPart = iota
+	// urlPartAuthority is the host part, values which might change the host are dropped there.
+	urlPartAuthority
+	// urlPartPath is the URL path.
+	urlPartPath
+	// urlPartQuery is the URL query string.
+	urlPartQuery
+	// urlPartFragment is the URL fragment.
+	urlPartFragment
+)
+
+// urlToken describes a single token found in the URL template.
+type urlToken struct {
+	// name is the token name without braces and modifiers
+	name string
+	// modifiers is the list of modifiers in order of application, e.g. "default:none"
+	modifiers []string
+	// raw indicates the double-brace form which disables contextual encoding
+	raw bool
+}
+
+// parseToken function builds urlToken from tokenRegExp submatch indexes.
+func parseToken(template string, match []int) urlToken {
+	token := urlToken{
+		name: template[match[4]:match[5]],
+		raw:  match[2] >= 0 && match[8] >= 0,
+	}
+
+	if match[7] > match[6] {
+		token.modifiers = strings.Split(template[match[6]+1:match[7]], "|")
+	}
+
+	return token
+}
+
+// modifier function returns the argument of the first modifier with provided name.
+func (t urlToken) modifier(name string) (string, bool) {
+	for _, modifier := range t.modifiers {
+		modifierName, arg, _ := strings.Cut(modifier, ":")
+		if strings.TrimSpace(modifierName) == name {
+			return arg, true
+		}
+	}
+
+	return "", false
+}
+
+// param function returns the name of the query param the token is resolved from, empty for other tokens.
+func (t urlToken) param() string {
+	switch t.name {
+	case p1Token, p2Token, p3Token, p4Token:
+		return t.name
+	}
+
+	if source, key, found := strings.Cut(t.name, ":"); found && source == paramTokenSource {
+		return key
+	}
+
+	return ""
+}
+
+// formatTime function formats the moment using the token format modifier or the default layout.
+func (t urlToken) formatTime(moment time.Time, defaultLayout string) string {
+	if layout, ok := t.modifier(formatModifier); ok && layout != "" {
+		return moment.Format(layout)
+	}
+
+	return moment.Format(defaultLayout)
+}
+
+// applyTokenModifiers function applies modifiers to the value in order.
+// Returns true if the value was explicitly encoded (or marked as raw), so contextual encoding must be skipped.
+// Unknown modifiers are ignored.
+func applyTokenModifiers(value string, modifiers []string) (string, bool) {
+	encoded := false
+
+	for _, modifier := range modifiers {
+		name, arg, _ := strings.Cut(modifier, ":")
+
+		switch strings.TrimSpace(name) {
+		case defaultModifier:
+			if value == "" {
+				value = arg
+			}
+		case sha256Modifier:
+			hash := sha256.Sum256([]byte(value))
+			value = hex.EncodeToString(hash[:])
+		case base64Modifier:
+			value = base64.StdEncoding.EncodeToString([]byte(value))
+		case urlEncodeModifier:
+			value = url.QueryEscape(value)
+			encoded = true
+		case rawModifier:
+			encoded = true
+		}
+	}
+
+	return value, encoded
+}
+
+// tokenURLPart function detects the part of the URL template the token at the provided position belongs to.
+func tokenURLPart(template string, position int) urlPart {
+	prefix := template[:position]
+	if prefix == "" {
+		return urlPartURL
+	}
+
+	if strings.Contains(prefix, "#") {
+		return urlPartFragment
+	}
+	if strings.Contains(prefix, "?") {
+		return urlPartQuery
+	}
+
+	if _, afterScheme, found := strings.Cut(prefix, "://"); found {
+		prefix = afterScheme
+	}
+	if !strings.Contains(prefix, "/") {
+		return urlPartAuthority
+	}
+
+	return urlPartPath
+}
+
+// escapeTokenValue function encodes the value according to the URL part it's inserted into.
+func escapeTokenValue(value string, part urlPart) string {
+	switch part {
+	case urlPartPath:
+		return url.PathEscape(value)
+	case urlPartQuery, urlPartFragment:
+		return url.QueryEscape(value)
+	case urlPartAuthority:
+		if !isSafeHostValue(value) {
+			return ""
+		}
+
+		return value
+	default:
+		return value
+	}
+}
+
+// isSafeHostValue function reports whether the value can't end the host or add credentials or port to it,
+// so a token placed into the host never redirects traffic to another domain.
+func isSafeHostValue(value string) bool {
+	return !strings.ContainsFunc(value, func(r rune) bool {
+		return strings.ContainsRune(`/\?#@:[]%`, r) || r <= ' ' || r == 0x7f
+	})
+}
+
+// dynamicTokenValue function resolves "{param:x}", "{header:x}" and "{cookie:x}" tokens.
+// Empty string is returned for unknown tokens and keys which are not allowed by the tracking link.
+func dynamicTokenValue(name string, trackingLink *entity.TrackingLink, requestData *dto.RedirectRequestData) string {
+	source, key, found := strings.Cut(name, ":")
+	if !found || key == "" || !isTokenKeyAllowed(trackingLink.AllowedTokenKeys, source, key) {
+		return ""
+	}
+
+	switch source {
+	case paramTokenSource:
+		return strings.Join(requestData.GetParam(key), ",")
+	case headerTokenSource:
+		return requestData.GetHeader(key)
+	case cookieTokenSource:
+		return requestData.GetCookie(key)
+	default:
+		return ""
+	}
+}
+
+// isTokenKeyAllowed function checks the tracking link allow-list, header names are case-insensitive.
+func isTokenKeyAllowed(allowed entity.AllowedListType, source, key string) bool {
+	if allowed[source+":"+key] || allowed[source+":"+anyTokenKey] {
+		return true
+	}
+
+	if source != headerTokenSource {
+		return false
+	}
+
+	for allowedKey, isAllowed := range allowed {
+		if isAllowed && strings.EqualFold(allowedKey, source+":"+key) {
+			return true
+		}
+	}
+
+	return false
+}
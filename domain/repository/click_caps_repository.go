@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lroman242/redirector/domain/entity"
+)
+
+//go:generate mockgen -package=mocks -destination=mocks/mock_click_caps_repository.go -source=click_caps_repository.go ClickCapsRepository
+
+// ClickCapsRepository interface describes storage of click counters used to enforce click caps.
+type ClickCapsRepository interface {
+	// Reserve function atomically counts the click against the tracking link and campaign caps.
+	// The click is not counted and false is returned if any of the caps is already reached.
+	Reserve(ctx context.Context, trackingLink *entity.TrackingLink, at time.Time) (bool, error)
+}
@@ -13,4 +13,6 @@ import (
 type ClicksRepository interface {
 	// Save function insert or update provided click to the storage.
 	Save(ctx context.Context, click *entity.Click) error
+	// FindClick function returns the click by ID, nil is returned if the click doesn't exist.
+	FindClick(ctx context.Context, id string) (*entity.Click, error)
 }
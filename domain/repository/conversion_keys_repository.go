@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lroman242/redirector/domain/entity"
+)
+
+//go:generate mockgen -package=mocks -destination=mocks/mock_conversion_keys_repository.go -source=conversion_keys_repository.go ConversionKeysRepository
+
+// ConversionKeysRepository interface describes storage of conversion deduplication keys.
+type ConversionKeysRepository interface {
+	// Reserve function atomically stores the deduplication key of the conversion.
+	// False is returned if the key is already reserved by another conversion.
+	Reserve(ctx context.Context, conversion *entity.Conversion) (bool, error)
+	// Release function removes the deduplication key reserved by the conversion, so it might be registered again.
+	Release(ctx context.Context, conversion *entity.Conversion) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lroman242/redirector/domain/entity"
+)
+
+//go:generate mockgen -package=mocks -destination=mocks/mock_conversions_repository.go -source=conversions_repository.go ConversionsRepository
+
+// ConversionsRepository interface describes conversions storage repository.
+type ConversionsRepository interface {
+	// Save function inserts provided conversion to the storage.
+	Save(ctx context.Context, conversion *entity.Conversion) error
+}
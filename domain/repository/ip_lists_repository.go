@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+//go:generate mockgen -package=mocks -destination=mocks/mock_ip_lists_repository.go -source=ip_lists_repository.go IPListsRepository
+
+// IPListsRepository interface describes storage of global IP allow/deny lists.
+type IPListsRepository interface {
+	// FindGlobalIPLists function returns lists applied to all tracking links.
+	// It's called for every redirect request, so implementations are expected to keep lists in memory.
+	FindGlobalIPLists(ctx context.Context) valueobject.IPLists
+}
@@ -0,0 +1,11 @@
+package repository
+
+import "context"
+
+//go:generate mockgen -package=mocks -destination=mocks/mock_postback_secrets_repository.go -source=postback_secrets_repository.go PostbackSecretsRepository
+
+// PostbackSecretsRepository interface describes storage of secrets advertisers sign conversion postbacks with.
+type PostbackSecretsRepository interface {
+	// FindSecret function returns the postback secret of the advertiser, empty string is returned if it's not set.
+	FindSecret(ctx context.Context, advertiserID string) (string, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lroman242/redirector/domain/entity"
+)
+
+//go:generate mockgen -package=mocks -destination=mocks/mock_postbacks_repository.go -source=postbacks_repository.go PostbacksRepository
+
+// PostbacksRepository interface describes affiliate postbacks storage, including the durable delivery queue.
+type PostbacksRepository interface {
+	// FindPostbackURLTemplate function returns the postback URL template of the affiliate.
+	// The template of the traffic source takes precedence over the affiliate one, empty string is returned if none is set.
+	FindPostbackURLTemplate(ctx context.Context, affiliateID, sourceID string) (string, error)
+	// Enqueue function adds the postback to the delivery queue, already queued postbacks are ignored.
+	Enqueue(ctx context.Context, postback *entity.Postback) error
+	// Dequeue function claims up to limit pending postbacks due by now. Claimed postbacks are not returned again
+	// until the lease expires, so postbacks of crashed workers are delivered later.
+	Dequeue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*entity.Postback, error)
+	// Update function stores the delivery status, attempts and the next attempt time of the postback.
+	Update(ctx context.Context, postback *entity.Postback) error
+	// SaveAttempt function stores the delivery attempt log entry.
+	SaveAttempt(ctx context.Context, attempt *entity.PostbackAttempt) error
+}
@@ -1,12 +1,16 @@
 // Package service contains types which provide some specific functionality used for business logic.
 package service
 
-import "net"
+import (
+	"net"
+
+	"github.com/lroman242/redirector/domain/valueobject"
+)
 
 //go:generate mockgen -package=mocks -destination=mocks/mock_ip_address_parser.go -source=ip_address_parser.go IPAddressParserInterface
 
-// IPAddressParserInterface describes service that parses country code from the provided IP address.
+// IPAddressParserInterface describes service that resolves geo location from the provided IP address.
 type IPAddressParserInterface interface {
-	// Parse function parses country code from the provided IP address.
-	Parse(ip net.IP) (countryCode string, err error)
+	// Parse function resolves geo location (country, region, city, etc.) from the provided IP address.
+	Parse(ip net.IP) (*valueobject.GeoLocation, error)
 }
@@ -0,0 +1,12 @@
+package service
+
+import "context"
+
+//go:generate mockgen -package=mocks -destination=mocks/mock_postback_sender.go -source=postback_sender.go PostbackSenderInterface
+
+// PostbackSenderInterface describes service that delivers postbacks to affiliate trackers.
+type PostbackSenderInterface interface {
+	// Send function requests the postback URL and returns the response status code.
+	// An error is returned if no response was received.
+	Send(ctx context.Context, url string) (int, error)
+}
@@ -0,0 +1,14 @@
+package service
+
+import "context"
+
+//go:generate mockgen -package=mocks -destination=mocks/mock_unique_click_detector.go -source=unique_click_detector.go UniqueClickDetectorInterface
+
+// UniqueClickDetectorInterface describes service that detects repeated clicks of the same visitor
+// during the deduplication window.
+type UniqueClickDetectorInterface interface {
+	// IsUnique function reports whether the key wasn't remembered during the deduplication window.
+	IsUnique(ctx context.Context, key string) (bool, error)
+	// Remember function remembers the key for the deduplication window.
+	Remember(ctx context.Context, key string) error
+}
@@ -0,0 +1,27 @@
+// Package valueobject contains immutable value objects that represent business concepts.
+// These objects are defined by their attributes and are considered equal when all their attributes match.
+package valueobject
+
+// AdClickIDs maps query params used by ad networks to pass their click identifiers to the network names.
+var AdClickIDs = map[string]string{
+	"gclid":     "google",
+	"gbraid":    "google",
+	"wbraid":    "google",
+	"dclid":     "google",
+	"fbclid":    "meta",
+	"msclkid":   "microsoft",
+	"ttclid":    "tiktok",
+	"twclid":    "x",
+	"li_fat_id": "linkedin",
+	"ScCid":     "snapchat",
+	"epik":      "pinterest",
+	"rdt_cid":   "reddit",
+	"yclid":     "yandex",
+}
+
+// IsAdClickID function checks if the query param is a known ad network click identifier.
+func IsAdClickID(param string) bool {
+	_, ok := AdClickIDs[param]
+
+	return ok
+}
@@ -0,0 +1,204 @@
+// Package valueobject contains immutable value objects that represent business concepts.
+// These objects are defined by their attributes and are considered equal when all their attributes match.
+package valueobject
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// cidrNode is a node of the binary prefix trie, terminal nodes mark the end of a network prefix.
+type cidrNode struct {
+	children [2]*cidrNode
+	terminal bool
+}
+
+// insert function adds network prefix of the provided length to the trie.
+func (n *cidrNode) insert(ip []byte, prefixLen int) {
+	node := n
+	for i := 0; i < prefixLen && !node.terminal; i++ {
+		bit := ip[i/8] >> (7 - i%8) & 1
+		if node.children[bit] == nil {
+			node.children[bit] = new(cidrNode)
+		}
+
+		node = node.children[bit]
+	}
+
+	// shorter prefix covers all longer prefixes, so they can be dropped
+	node.terminal = true
+	node.children = [2]*cidrNode{}
+}
+
+// contains function checks if the address belongs to any prefix stored in the trie.
+func (n *cidrNode) contains(ip []byte) bool {
+	node := n
+	for i := 0; i < len(ip)*8; i++ {
+		if node.terminal {
+			return true
+		}
+
+		node = node.children[ip[i/8]>>(7-i%8)&1]
+		if node == nil {
+			return false
+		}
+	}
+
+	return node.terminal
+}
+
+// CIDRList is a list of IPv4 and IPv6 networks with fast (prefix trie) address lookup.
+// Plain IP addresses are treated as single address networks.
+type CIDRList struct {
+	cidrs []string
+	v4    *cidrNode
+	v6    *cidrNode
+}
+
+// NewCIDRList function parses provided networks (e.g. "10.0.0.0/8", "2001:db8::/32", "192.168.1.1").
+func NewCIDRList(cidrs []string) (CIDRList, error) {
+	list := CIDRList{
+		cidrs: make([]string, 0, len(cidrs)),
+		v4:    new(cidrNode),
+		v6:    new(cidrNode),
+	}
+
+	for _, cidr := range cidrs {
+		if err := list.add(strings.TrimSpace(cidr)); err != nil {
+			return CIDRList{}, err
+		}
+	}
+
+	return list, nil
+}
+
+// add function parses and adds the network to the list.
+func (l *CIDRList) add(cidr string) error {
+	if !strings.Contains(cidr, "/") {
+		ip := net.ParseIP(cidr)
+		if ip == nil {
+			return fmt.Errorf("invalid IP address %q", cidr)
+		}
+
+		if ip.To4() != nil {
+			cidr += "/32"
+		} else {
+			cidr += "/128"
+		}
+	}
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+	}
+
+	prefixLen, bits := network.Mask.Size()
+	if bits == net.IPv6len*8 && network.IP.To4() != nil && prefixLen >= 96 {
+		// IPv4-mapped IPv6 networks (::ffff:10.0.0.0/104) are stored as IPv4 ones
+		prefixLen -= 96
+		bits = net.IPv4len * 8
+	}
+
+	if ip := network.IP.To4(); ip != nil && bits == net.IPv4len*8 {
+		l.v4.insert(ip, prefixLen)
+	} else {
+		l.v6.insert(network.IP.To16(), prefixLen)
+	}
+
+	l.cidrs = append(l.cidrs, cidr)
+
+	return nil
+}
+
+// Contains function checks if the IP address belongs to any network of the list.
+func (l CIDRList) Contains(ip net.IP) bool {
+	if ip == nil || l.IsEmpty() {
+		return false
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return l.v4.contains(ip4)
+	}
+
+	return l.v6.contains(ip.To16())
+}
+
+// IsEmpty returns true when the list contains no networks.
+func (l CIDRList) IsEmpty() bool {
+	return len(l.cidrs) == 0
+}
+
+// Strings returns the list of networks in CIDR notation.
+func (l CIDRList) Strings() []string {
+	return l.cidrs
+}
+
+// MarshalJSON encodes the list as JSON array of networks.
+func (l CIDRList) MarshalJSON() ([]byte, error) {
+	if l.cidrs == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal(l.cidrs)
+}
+
+// UnmarshalJSON decodes the list from JSON array of networks.
+func (l *CIDRList) UnmarshalJSON(data []byte) error {
+	cidrs := make([]string, 0)
+	if err := json.Unmarshal(data, &cidrs); err != nil {
+		return err
+	}
+
+	list, err := NewCIDRList(cidrs)
+	if err != nil {
+		return err
+	}
+
+	*l = list
+	return nil
+}
+
+// Value returns the JSON-encoded representation.
+func (l CIDRList) Value() (driver.Value, error) {
+	return l.MarshalJSON()
+}
+
+// Scan decodes a JSON-encoded value. NULL values produce an empty list.
+func (l *CIDRList) Scan(value interface{}) error {
+	if value == nil {
+		*l = CIDRList{}
+		return nil
+	}
+
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return l.UnmarshalJSON(b)
+}
+
+// IPLists contains allowed and denied networks, allowed networks take precedence over denied ones.
+type IPLists struct {
+	// Allowed is the list of networks which are always allowed
+	Allowed CIDRList
+	// Denied is the list of networks which are blocked
+	Denied CIDRList
+}
+
+// Check function reports whether the IP address is explicitly allowed or denied by the lists.
+// Matched is false when the address doesn't belong to any of the lists.
+func (l IPLists) Check(ip net.IP) (matched, allowed bool) {
+	if l.Allowed.Contains(ip) {
+		return true, true
+	}
+	if l.Denied.Contains(ip) {
+		return true, false
+	}
+
+	return false, false
+}
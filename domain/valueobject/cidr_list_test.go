@@ -0,0 +1,111 @@
+package valueobject_test
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+func TestCIDRList_Contains(t *testing.T) {
+	list, err := valueobject.NewCIDRList([]string{
+		"10.0.0.0/8",
+		"192.168.1.0/24",
+		"192.168.1.128/25",
+		"203.0.113.7",
+		"2001:db8::/32",
+		"::1",
+		"::ffff:172.16.0.0/108",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ip   string
+		want bool
+	}{
+		{name: "inside /8 network", ip: "10.20.30.40", want: true},
+		{name: "outside /8 network", ip: "11.0.0.1", want: false},
+		{name: "inside /24 network", ip: "192.168.1.1", want: true},
+		{name: "inside nested network", ip: "192.168.1.200", want: true},
+		{name: "next to /24 network", ip: "192.168.2.1", want: false},
+		{name: "single IPv4 address", ip: "203.0.113.7", want: true},
+		{name: "neighbour of single IPv4 address", ip: "203.0.113.8", want: false},
+		{name: "IPv4-mapped IPv6 address", ip: "::ffff:10.1.1.1", want: true},
+		{name: "inside IPv6 network", ip: "2001:db8:1::1", want: true},
+		{name: "outside IPv6 network", ip: "2001:db9::1", want: false},
+		{name: "single IPv6 address", ip: "::1", want: true},
+		{name: "neighbour of single IPv6 address", ip: "::2", want: false},
+		{name: "inside IPv4-mapped IPv6 network", ip: "172.16.5.5", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := list.Contains(net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("Contains(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCIDRList_Invalid(t *testing.T) {
+	for _, cidr := range []string{"10.0.0.0/33", "not-an-ip", "2001:db8::/129"} {
+		if _, err := valueobject.NewCIDRList([]string{cidr}); err == nil {
+			t.Errorf("expected error for %q", cidr)
+		}
+	}
+}
+
+func TestCIDRList_Scan(t *testing.T) {
+	var list valueobject.CIDRList
+	if err := list.Scan([]byte(`["10.0.0.0/8", "2001:db8::/32"]`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !list.Contains(net.ParseIP("10.1.2.3")) || !list.Contains(net.ParseIP("2001:db8::1")) {
+		t.Errorf("scanned list doesn't contain expected addresses")
+	}
+
+	encoded, err := json.Marshal(list)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(encoded) != `["10.0.0.0/8","2001:db8::/32"]` {
+		t.Errorf("unexpected JSON: %s", encoded)
+	}
+
+	if err := list.Scan(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !list.IsEmpty() || list.Contains(net.ParseIP("10.1.2.3")) {
+		t.Errorf("expected empty list after scanning NULL")
+	}
+}
+
+func TestIPLists_Check(t *testing.T) {
+	allowed, _ := valueobject.NewCIDRList([]string{"10.1.0.0/16"})
+	denied, _ := valueobject.NewCIDRList([]string{"10.0.0.0/8"})
+	lists := valueobject.IPLists{Allowed: allowed, Denied: denied}
+
+	tests := []struct {
+		ip          string
+		wantMatched bool
+		wantAllowed bool
+	}{
+		{ip: "10.1.1.1", wantMatched: true, wantAllowed: true},
+		{ip: "10.2.1.1", wantMatched: true, wantAllowed: false},
+		{ip: "8.8.8.8", wantMatched: false, wantAllowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			matched, allowed := lists.Check(net.ParseIP(tt.ip))
+			if matched != tt.wantMatched || allowed != tt.wantAllowed {
+				t.Errorf("Check(%s) = (%v, %v), want (%v, %v)", tt.ip, matched, allowed, tt.wantMatched, tt.wantAllowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Package valueobject contains immutable value objects that represent business concepts.
+// These objects are defined by their attributes and are considered equal when all their attributes match.
+package valueobject
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// ClickCaps describes the maximum number of clicks allowed per period, zero means unlimited.
+// Hourly and daily periods are calculated in UTC.
+type ClickCaps struct {
+	// Hourly is the maximum number of clicks allowed per hour
+	Hourly int64 `json:"hourly,omitempty"`
+	// Daily is the maximum number of clicks allowed per day
+	Daily int64 `json:"daily,omitempty"`
+	// Lifetime is the maximum number of clicks allowed in total
+	Lifetime int64 `json:"lifetime,omitempty"`
+}
+
+// IsEmpty returns true when no caps are defined.
+func (c ClickCaps) IsEmpty() bool {
+	return c.Hourly <= 0 && c.Daily <= 0 && c.Lifetime <= 0
+}
+
+// Value returns the JSON-encoded representation.
+func (c ClickCaps) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan decodes a JSON-encoded value. NULL values produce empty caps.
+func (c *ClickCaps) Scan(value interface{}) error {
+	if value == nil {
+		*c = ClickCaps{}
+		return nil
+	}
+
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	x := ClickCaps{}
+	if err := json.Unmarshal(b, &x); err != nil {
+		return err
+	}
+
+	*c = x
+	return nil
+}
@@ -0,0 +1,40 @@
+// Package valueobject contains immutable value objects that represent business concepts.
+// These objects are defined by their attributes and are considered equal when all their attributes match.
+package valueobject
+
+// GeoLocation contains geographic and network information resolved from the visitor's IP address.
+// Fields which can't be resolved (e.g. when only country database is available) are left empty.
+type GeoLocation struct {
+	// CountryCode is the ISO 3166-1 country code ("US")
+	CountryCode string
+	// Region is the ISO 3166-2 code of the top-level subdivision without country prefix ("CA" for California)
+	Region string
+	// City is the English name of the city
+	City string
+	// PostalCode is the postal (zip) code
+	PostalCode string
+	// Latitude is the approximate latitude of the location
+	Latitude float64
+	// Longitude is the approximate longitude of the location
+	Longitude float64
+	// TimeZone is the IANA timezone name of the location
+	TimeZone string
+
+	// ASN is the autonomous system number of the network
+	ASN uint
+	// ASNOrganization is the organization the autonomous system is registered to
+	ASNOrganization string
+	// ISP is the name of the internet service provider
+	ISP string
+	// ConnectionType is the connection type ("Cable/DSL", "Cellular", "Corporate", "Satellite")
+	ConnectionType string
+}
+
+// RegionCode returns the region code prefixed with the country code ("US-CA"), empty if the region is unknown.
+func (g *GeoLocation) RegionCode() string {
+	if g.Region == "" {
+		return ""
+	}
+
+	return g.CountryCode + "-" + g.Region
+}
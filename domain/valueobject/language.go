@@ -0,0 +1,61 @@
+// Package valueobject contains immutable value objects that represent business concepts.
+// These objects are defined by their attributes and are considered equal when all their attributes match.
+package valueobject
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseAcceptLanguage parses Accept-Language header value and returns lower-cased language tags
+// ordered by preference. The wildcard and tags with zero quality are skipped.
+func ParseAcceptLanguage(header string) []string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+
+	preferences := make([]preference, 0)
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+
+			quality = parsed
+		}
+
+		if quality <= 0 {
+			continue
+		}
+
+		preferences = append(preferences, preference{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	tags := make([]string, len(preferences))
+	for i, p := range preferences {
+		tags[i] = p.tag
+	}
+
+	return tags
+}
+
+// PrimaryLanguage returns the primary language subtag ("en" for "en-US").
+func PrimaryLanguage(tag string) string {
+	primary, _, _ := strings.Cut(tag, "-")
+
+	return strings.ToLower(primary)
+}
@@ -0,0 +1,73 @@
+// Package valueobject contains immutable value objects that represent business concepts.
+// These objects are defined by their attributes and are considered equal when all their attributes match.
+package valueobject
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// Predefined query params pass-through modes.
+const (
+	// NonePassThroughMode drops all incoming query params which are not consumed by tokens.
+	NonePassThroughMode = "none"
+	// AllPassThroughMode passes all incoming query params to the destination.
+	AllPassThroughMode = "all"
+	// AllowListPassThroughMode passes only the listed query params.
+	AllowListPassThroughMode = "allow"
+	// DenyListPassThroughMode passes all query params except the listed ones.
+	DenyListPassThroughMode = "deny"
+)
+
+// Predefined strategies used when the target URL already contains the passed query param.
+const (
+	// KeepConflictStrategy keeps the target URL value and drops the incoming one.
+	KeepConflictStrategy = "keep"
+	// OverrideConflictStrategy replaces the target URL value with the incoming one.
+	OverrideConflictStrategy = "override"
+	// AppendConflictStrategy keeps both the target URL and the incoming values.
+	AppendConflictStrategy = "append"
+)
+
+// ParamsPassThrough describes which incoming query params are merged into the target URL.
+type ParamsPassThrough struct {
+	// Mode is one of the pass-through modes, NonePassThroughMode is used by default.
+	Mode string `json:"mode,omitempty"`
+	// Params is the list of query params used by allow-list and deny-list modes.
+	Params []string `json:"params,omitempty"`
+	// OnConflict defines how params already present in the target URL are handled,
+	// KeepConflictStrategy is used by default.
+	OnConflict string `json:"on_conflict,omitempty"`
+}
+
+// IsEnabled returns true when incoming query params might be passed to the destination.
+func (p ParamsPassThrough) IsEnabled() bool {
+	return p.Mode == AllPassThroughMode || p.Mode == AllowListPassThroughMode || p.Mode == DenyListPassThroughMode
+}
+
+// Value returns the JSON-encoded representation.
+func (p ParamsPassThrough) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan decodes a JSON-encoded value. NULL values disable pass-through.
+func (p *ParamsPassThrough) Scan(value interface{}) error {
+	if value == nil {
+		*p = ParamsPassThrough{}
+		return nil
+	}
+
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	x := ParamsPassThrough{}
+	if err := json.Unmarshal(b, &x); err != nil {
+		return err
+	}
+
+	*p = x
+	return nil
+}
@@ -0,0 +1,11 @@
+package valueobject
+
+// Delivery statuses of affiliate postbacks.
+const (
+	// PendingPostbackStatus marks postbacks waiting for the next delivery attempt.
+	PendingPostbackStatus = "pending"
+	// DeliveredPostbackStatus marks postbacks accepted by the affiliate tracker.
+	DeliveredPostbackStatus = "delivered"
+	// FailedPostbackStatus marks postbacks rejected by the affiliate tracker or out of attempts.
+	FailedPostbackStatus = "failed"
+)
@@ -12,10 +12,28 @@ const (
 	SmartSlugRedirectType = "smart"
 	// NoRedirectType indicates that traffic should be blocked.
 	NoRedirectType = "block"
-	// NoClickType indicates that no click should be recorded.
+	// NoClickType indicates that traffic should be redirected without recording a click.
+	// RedirectURL is used as a target when set, otherwise the tracking link target is used.
 	NoClickType = "no-click"
 )
 
+// Predefined redirect methods that determine how the visitor is sent to the target URL.
+// The "303 See Other" redirect is used when the method is not set.
+const (
+	// MovedPermanentlyRedirectMethod indicates "301 Moved Permanently" redirect.
+	MovedPermanentlyRedirectMethod = "301"
+	// FoundRedirectMethod indicates "302 Found" redirect.
+	FoundRedirectMethod = "302"
+	// TemporaryRedirectMethod indicates "307 Temporary Redirect" redirect.
+	TemporaryRedirectMethod = "307"
+	// PermanentRedirectMethod indicates "308 Permanent Redirect" redirect.
+	PermanentRedirectMethod = "308"
+	// MetaRefreshRedirectMethod indicates an HTML page with meta refresh, the referrer is not sent to the target.
+	MetaRefreshRedirectMethod = "meta_refresh"
+	// JavaScriptRedirectMethod indicates an HTML page which redirects the visitor with JavaScript.
+	JavaScriptRedirectMethod = "javascript"
+)
+
 // RedirectRules type describes a set of options used to handle traffic redirect correctly
 // in case traffic doesn't satisfy campaign requirements.
 type RedirectRules struct {
@@ -23,10 +41,15 @@ type RedirectRules struct {
 	// Valid values are defined in the constants above.
 	RedirectType string
 
-	// RedirectURL is the target URL for LinkRedirectType.
+	// RedirectURL is the target URL for LinkRedirectType and NoClickType.
 	RedirectURL string
 	// RedirectSlug is the target tracking link slug for SlugRedirectType.
 	RedirectSlug string
-	// RedirectSmartSlug is a list of possible target slugs for SmartSlugRedirectType.
-	RedirectSmartSlug []string
+	// RedirectSmartSlug is a list of possible weighted target slugs for SmartSlugRedirectType.
+	RedirectSmartSlug SmartSlugs
+	// StickySmartSlug indicates that a returning visitor should always land on the same smart slug target.
+	StickySmartSlug bool
+	// RedirectMethod overrides the tracking link redirect method when set.
+	// Valid values are defined in the constants above.
+	RedirectMethod string
 }
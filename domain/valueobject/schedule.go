@@ -0,0 +1,194 @@
+// Package valueobject contains immutable value objects that represent business concepts.
+// These objects are defined by their attributes and are considered equal when all their attributes match.
+package valueobject
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// minutesPerDay is the number of minutes in a day.
+const minutesPerDay = 24 * 60
+
+// weekdayNames maps short weekday names used in schedule windows to time.Weekday.
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ScheduleWindow describes a range of hours on specific days of the week.
+type ScheduleWindow struct {
+	// Weekdays is the list of days ("mon", "tue", ...) the window is active on, every day if empty.
+	Weekdays []string `json:"weekdays,omitempty"`
+	// From is the start of the window in "HH:MM" format (inclusive).
+	From string `json:"from"`
+	// To is the end of the window in "HH:MM" format (exclusive).
+	// Windows where To is before From end on the next day.
+	To string `json:"to"`
+}
+
+// Schedule describes when a tracking link is running.
+type Schedule struct {
+	// Timezone is the IANA timezone name used to evaluate windows, UTC is used by default.
+	Timezone string `json:"timezone,omitempty"`
+	// Windows is the list of time windows the tracking link is running in.
+	Windows []ScheduleWindow `json:"windows,omitempty"`
+
+	// location is the resolved timezone, it is set when the schedule is decoded
+	location *time.Location
+}
+
+// IsEmpty returns true when no windows are defined, i.e. the tracking link is always running.
+func (s Schedule) IsEmpty() bool {
+	return len(s.Windows) == 0
+}
+
+// Contains function checks if the provided moment is inside one of the schedule windows.
+// Empty schedule contains any moment. Windows of a schedule with unknown timezone are evaluated in UTC,
+// invalid window definitions never match.
+func (s Schedule) Contains(moment time.Time) bool {
+	if s.IsEmpty() {
+		return true
+	}
+
+	location := s.location
+	if location == nil {
+		location = scheduleLocation(s.Timezone)
+	}
+
+	moment = moment.In(location)
+	minute := moment.Hour()*60 + moment.Minute()
+	weekday := moment.Weekday()
+	previousWeekday := (weekday + 6) % 7
+
+	for _, window := range s.Windows {
+		from, okFrom := parseClock(window.From)
+		to, okTo := parseClock(window.To)
+		if !okFrom || !okTo {
+			continue
+		}
+
+		if from <= to {
+			if window.isActiveOn(weekday) && minute >= from && minute < to {
+				return true
+			}
+
+			continue
+		}
+
+		// window wraps around midnight, so it belongs to the day it starts on
+		if window.isActiveOn(weekday) && minute >= from {
+			return true
+		}
+		if window.isActiveOn(previousWeekday) && minute < to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isActiveOn function checks if the window is active on the provided day of the week.
+func (w ScheduleWindow) isActiveOn(weekday time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(w.Weekdays, func(name string) bool {
+		day, ok := parseWeekday(name)
+		return ok && day == weekday
+	})
+}
+
+// parseWeekday function parses weekday name, both short ("mon") and full ("Monday") names are accepted.
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) > 3 {
+		name = name[:3]
+	}
+
+	day, ok := weekdayNames[name]
+
+	return day, ok
+}
+
+// UnmarshalJSON decodes the schedule and resolves its timezone once, UTC is used for unknown timezones.
+func (s *Schedule) UnmarshalJSON(data []byte) error {
+	type schedule Schedule
+
+	x := schedule{}
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+
+	x.location = scheduleLocation(x.Timezone)
+	*s = Schedule(x)
+
+	return nil
+}
+
+// Value returns the JSON-encoded representation.
+func (s Schedule) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan decodes a JSON-encoded value. NULL values produce an empty schedule.
+func (s *Schedule) Scan(value interface{}) error {
+	if value == nil {
+		*s = Schedule{}
+		return nil
+	}
+
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	x := Schedule{}
+	if err := json.Unmarshal(b, &x); err != nil {
+		return err
+	}
+
+	*s = x
+	return nil
+}
+
+// scheduleLocation function resolves the schedule timezone, unknown timezone is reported and UTC is used instead,
+// so a typo in the timezone doesn't take the tracking link down.
+func scheduleLocation(name string) *time.Location {
+	location, err := loadLocation(name)
+	if err != nil {
+		slog.Warn("unknown schedule timezone, UTC is used instead",
+			slog.String("timezone", name),
+			slog.String("error", err.Error()),
+		)
+		return time.UTC
+	}
+
+	return location
+}
+
+// parseClock function parses "HH:MM" value into minutes since midnight. "24:00" is accepted as the end of a day.
+func parseClock(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	if value == "24:00" {
+		return minutesPerDay, true
+	}
+
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, false
+	}
+
+	return clock.Hour()*60 + clock.Minute(), true
+}
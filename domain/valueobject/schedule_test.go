@@ -0,0 +1,142 @@
+package valueobject_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+func TestSchedule_Contains(t *testing.T) {
+	businessHours := valueobject.Schedule{
+		Timezone: "America/New_York",
+		Windows: []valueobject.ScheduleWindow{
+			{Weekdays: []string{"mon", "tue", "wed", "thu", "fri"}, From: "09:00", To: "17:00"},
+		},
+	}
+	nightShift := valueobject.Schedule{
+		Windows: []valueobject.ScheduleWindow{
+			{Weekdays: []string{"Friday"}, From: "22:00", To: "02:00"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		schedule valueobject.Schedule
+		moment   time.Time
+		want     bool
+	}{
+		{
+			name:     "empty schedule",
+			schedule: valueobject.Schedule{},
+			moment:   time.Date(2025, 3, 1, 3, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			// Monday 10:00 in New York
+			name:     "inside business hours",
+			schedule: businessHours,
+			moment:   time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			// Monday 08:00 in New York
+			name:     "before business hours",
+			schedule: businessHours,
+			moment:   time.Date(2025, 3, 3, 13, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			// Monday 17:00 in New York
+			name:     "end of window is exclusive",
+			schedule: businessHours,
+			moment:   time.Date(2025, 3, 3, 22, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			// Saturday 10:00 in New York
+			name:     "weekend",
+			schedule: businessHours,
+			moment:   time.Date(2025, 3, 1, 15, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "window wrapping midnight on start day",
+			schedule: nightShift,
+			moment:   time.Date(2025, 2, 28, 23, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "window wrapping midnight on next day",
+			schedule: nightShift,
+			moment:   time.Date(2025, 3, 1, 1, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "window wrapping midnight on wrong day",
+			schedule: nightShift,
+			moment:   time.Date(2025, 3, 2, 1, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name: "invalid timezone falls back to UTC",
+			schedule: valueobject.Schedule{
+				Timezone: "Invalid/Zone",
+				Windows:  []valueobject.ScheduleWindow{{From: "09:00", To: "11:00"}},
+			},
+			moment: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Contains(tt.moment); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_ValueScan(t *testing.T) {
+	schedule := valueobject.Schedule{
+		Timezone: "Europe/Kyiv",
+		Windows:  []valueobject.ScheduleWindow{{Weekdays: []string{"sat", "sun"}, From: "10:00", To: "20:00"}},
+	}
+
+	value, err := schedule.Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var scanned valueobject.Schedule
+	if err := scanned.Scan(value); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if scanned.Timezone != schedule.Timezone || len(scanned.Windows) != 1 || scanned.Windows[0].From != "10:00" {
+		t.Errorf("unexpected scanned schedule: %+v", scanned)
+	}
+
+	// 09:00 UTC is 11:00 in Kyiv
+	if !scanned.Contains(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Error("expected scanned schedule to use its timezone")
+	}
+
+	if err := scanned.Scan([]byte(`{"timezone":"Invalid/Zone","windows":[{"from":"09:00","to":"11:00"}]}`)); err != nil {
+		t.Errorf("unexpected error on invalid timezone: %v", err)
+	}
+
+	if !scanned.Contains(time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)) ||
+		scanned.Contains(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Error("expected schedule with invalid timezone to be evaluated in UTC")
+	}
+
+	if err := scanned.Scan(nil); err != nil || !scanned.IsEmpty() {
+		t.Errorf("expected empty schedule on NULL value, got %+v (err %v)", scanned, err)
+	}
+
+	if err := scanned.Scan("invalid"); err == nil {
+		t.Error("expected error on non []byte value")
+	}
+}
@@ -0,0 +1,106 @@
+// Package valueobject contains immutable value objects that represent business concepts.
+// These objects are defined by their attributes and are considered equal when all their attributes match.
+package valueobject
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// defaultSmartSlugWeight is the weight assigned to targets stored without an explicit weight.
+const defaultSmartSlugWeight = 1
+
+// WeightedSlug describes one of the possible targets of SmartSlugRedirectType.
+type WeightedSlug struct {
+	// Slug is the target tracking link slug.
+	Slug string `json:"slug"`
+	// Weight is the relative share of traffic sent to the Slug.
+	// Targets with non-positive weight never receive traffic.
+	Weight int `json:"weight"`
+}
+
+// UnmarshalJSON decodes a weighted slug from either an object ({"slug":"a","weight":70})
+// or a plain string ("a"), the latter receives the default weight.
+func (ws *WeightedSlug) UnmarshalJSON(data []byte) error {
+	var slug string
+	if err := json.Unmarshal(data, &slug); err == nil {
+		ws.Slug = slug
+		ws.Weight = defaultSmartSlugWeight
+
+		return nil
+	}
+
+	type weightedSlug WeightedSlug
+	aux := weightedSlug{Weight: defaultSmartSlugWeight}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*ws = WeightedSlug(aux)
+
+	return nil
+}
+
+// SmartSlugs is a list of weighted targets used by SmartSlugRedirectType.
+type SmartSlugs []WeightedSlug
+
+// TotalWeight returns the sum of all positive weights.
+func (s SmartSlugs) TotalWeight() int {
+	total := 0
+	for _, ws := range s {
+		if ws.Weight > 0 {
+			total += ws.Weight
+		}
+	}
+
+	return total
+}
+
+// Pick returns the slug whose weight range contains the provided point.
+// The point should be in the [0, TotalWeight()) range, otherwise empty string is returned.
+func (s SmartSlugs) Pick(point int) string {
+	if point < 0 {
+		return ""
+	}
+
+	for _, ws := range s {
+		if ws.Weight <= 0 {
+			continue
+		}
+
+		if point < ws.Weight {
+			return ws.Slug
+		}
+
+		point -= ws.Weight
+	}
+
+	return ""
+}
+
+// Value returns the JSON-encoded representation.
+func (s SmartSlugs) Value() (driver.Value, error) {
+	return json.Marshal([]WeightedSlug(s))
+}
+
+// Scan decodes a JSON-encoded value. NULL values produce an empty list.
+func (s *SmartSlugs) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	x := make([]WeightedSlug, 0)
+	if err := json.Unmarshal(b, &x); err != nil {
+		return err
+	}
+
+	*s = x
+	return nil
+}
@@ -0,0 +1,102 @@
+package valueobject_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+func TestSmartSlugs_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    valueobject.SmartSlugs
+		wantErr bool
+	}{
+		{
+			name:  "legacy list of slugs",
+			input: `["a","b"]`,
+			want:  valueobject.SmartSlugs{{Slug: "a", Weight: 1}, {Slug: "b", Weight: 1}},
+		},
+		{
+			name:  "weighted slugs",
+			input: `[{"slug":"a","weight":70},{"slug":"b","weight":30}]`,
+			want:  valueobject.SmartSlugs{{Slug: "a", Weight: 70}, {Slug: "b", Weight: 30}},
+		},
+		{
+			name:  "weight omitted",
+			input: `[{"slug":"a"}]`,
+			want:  valueobject.SmartSlugs{{Slug: "a", Weight: 1}},
+		},
+		{
+			name:    "invalid item",
+			input:   `[123]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got valueobject.SmartSlugs
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmartSlugs_Scan(t *testing.T) {
+	var got valueobject.SmartSlugs
+	if err := got.Scan(nil); err != nil || got != nil {
+		t.Errorf("Scan(nil) = %v, %v, want empty list", got, err)
+	}
+
+	if err := got.Scan("string value"); err == nil {
+		t.Error("expected type assertion error")
+	}
+
+	if err := got.Scan([]byte(`["a",{"slug":"b","weight":3}]`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := valueobject.SmartSlugs{{Slug: "a", Weight: 1}, {Slug: "b", Weight: 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() = %v, want %v", got, want)
+	}
+}
+
+func TestSmartSlugs_Pick(t *testing.T) {
+	slugs := valueobject.SmartSlugs{
+		{Slug: "a", Weight: 70},
+		{Slug: "disabled", Weight: 0},
+		{Slug: "b", Weight: 30},
+	}
+
+	if total := slugs.TotalWeight(); total != 100 {
+		t.Fatalf("unexpected total weight. expected 100 got %d", total)
+	}
+
+	tests := []struct {
+		point int
+		want  string
+	}{
+		{point: -1, want: ""},
+		{point: 0, want: "a"},
+		{point: 69, want: "a"},
+		{point: 70, want: "b"},
+		{point: 99, want: "b"},
+		{point: 100, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := slugs.Pick(tt.point); got != tt.want {
+			t.Errorf("Pick(%d) = %s, want %s", tt.point, got, tt.want)
+		}
+	}
+}
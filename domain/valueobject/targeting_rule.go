@@ -0,0 +1,391 @@
+// Package valueobject contains immutable value objects that represent business concepts.
+// These objects are defined by their attributes and are considered equal when all their attributes match.
+package valueobject
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Predefined targeting condition fields.
+const (
+	// CountryConditionField matches the visitor's country ISO code.
+	CountryConditionField = "country"
+	// RegionConditionField matches the visitor's region code, both "US-CA" and "CA" forms are supported.
+	RegionConditionField = "region"
+	// CityConditionField matches the visitor's city name.
+	CityConditionField = "city"
+	// ASNConditionField matches the visitor's autonomous system number, both "15169" and "AS15169" forms are supported.
+	ASNConditionField = "asn"
+	// ASNOrganizationConditionField matches the organization the visitor's autonomous system is registered to.
+	ASNOrganizationConditionField = "asn_org"
+	// ISPConditionField matches the visitor's internet service provider name.
+	ISPConditionField = "isp"
+	// ConnectionTypeConditionField matches the visitor's connection type ("Cable/DSL", "Cellular", ...).
+	ConnectionTypeConditionField = "connection_type"
+	// DeviceConditionField matches the visitor's device type.
+	DeviceConditionField = "device"
+	// OSConditionField matches the visitor's operating system.
+	OSConditionField = "os"
+	// BrowserConditionField matches the visitor's browser name.
+	BrowserConditionField = "browser"
+	// RefererConditionField matches the referring URL.
+	RefererConditionField = "referer"
+	// QueryParamConditionField matches values of the query param defined by TargetingCondition.Key.
+	QueryParamConditionField = "param"
+	// TimeWindowConditionField matches the request time against "HH:MM-HH:MM" ranges.
+	// TargetingCondition.Key might contain IANA timezone name, UTC is used by default.
+	TimeWindowConditionField = "time"
+	// LanguageConditionField matches the languages accepted by the visitor.
+	LanguageConditionField = "language"
+	// ProtocolConditionField matches the request protocol (http/https).
+	ProtocolConditionField = "protocol"
+	// BotConditionField matches "true" for bots/crawlers and "false" for regular visitors.
+	BotConditionField = "bot"
+	// UniqueConditionField matches "true" for unique clicks and "false" for duplicate clicks.
+	UniqueConditionField = "unique"
+)
+
+// Predefined targeting condition operators.
+const (
+	// InOperator matches when the attribute equals one of the values (case-insensitive).
+	InOperator = "in"
+	// NotInOperator matches when the attribute equals none of the values (case-insensitive).
+	NotInOperator = "not_in"
+	// ContainsOperator matches when the attribute contains one of the values (case-insensitive).
+	ContainsOperator = "contains"
+	// NotContainsOperator matches when the attribute contains none of the values (case-insensitive).
+	NotContainsOperator = "not_contains"
+	// ExistsOperator matches when the attribute is not empty.
+	ExistsOperator = "exists"
+	// NotExistsOperator matches when the attribute is empty.
+	NotExistsOperator = "not_exists"
+)
+
+// Predefined logic used to combine targeting conditions.
+const (
+	// AndLogic requires all conditions to match.
+	AndLogic = "and"
+	// OrLogic requires at least one condition to match.
+	OrLogic = "or"
+)
+
+// TargetingAttributes contains visitor attributes used to evaluate targeting conditions.
+type TargetingAttributes struct {
+	// Country is the visitor's country ISO code.
+	Country string
+	// Region is the visitor's region code prefixed with the country code ("US-CA").
+	Region string
+	// City is the visitor's city name.
+	City string
+	// ASN is the visitor's autonomous system number, zero if unknown.
+	ASN uint
+	// ASNOrganization is the organization the visitor's autonomous system is registered to.
+	ASNOrganization string
+	// ISP is the visitor's internet service provider name.
+	ISP string
+	// ConnectionType is the visitor's connection type.
+	ConnectionType string
+	// Device is the visitor's device type.
+	Device string
+	// OS is the visitor's operating system.
+	OS string
+	// Browser is the visitor's browser name.
+	Browser string
+	// Referer is the referring URL.
+	Referer string
+	// Protocol is the request protocol.
+	Protocol string
+	// Bot indicates whether the request comes from a bot/crawler.
+	Bot bool
+	// Unique indicates whether the click is the first click of the visitor during the deduplication window.
+	Unique bool
+	// Params contains URL query parameters.
+	Params map[string][]string
+	// Languages contains languages accepted by the visitor in order of preference.
+	Languages []string
+	// Time is the moment of the request.
+	Time time.Time
+}
+
+// TargetingCondition describes a single check of the visitor attributes.
+type TargetingCondition struct {
+	// Field is the name of the visitor attribute, one of the predefined condition fields.
+	Field string `json:"field"`
+	// Operator defines how the attribute is compared with Values.
+	Operator string `json:"operator"`
+	// Key is the query param name for QueryParamConditionField
+	// or IANA timezone name for TimeWindowConditionField.
+	Key string `json:"key,omitempty"`
+	// Values is the list of values the attribute is compared with.
+	Values []string `json:"values,omitempty"`
+
+	// location is the resolved timezone of TimeWindowConditionField, it is set when the condition is decoded
+	location *time.Location
+	// unknownTimezone marks time window conditions decoded with unknown timezone, they never match
+	unknownTimezone bool
+}
+
+// UnmarshalJSON decodes the condition and resolves the time window timezone once.
+// Unknown timezones are logged and the condition is skipped (never matches), so the rest of the rule still loads.
+func (c *TargetingCondition) UnmarshalJSON(data []byte) error {
+	type condition TargetingCondition
+
+	x := condition{}
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+
+	if x.Field == TimeWindowConditionField {
+		location, err := loadLocation(x.Key)
+		if err != nil {
+			slog.Warn("time window targeting condition is skipped",
+				slog.String("timezone", x.Key),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		x.location = location
+		x.unknownTimezone = err != nil
+	}
+
+	*c = TargetingCondition(x)
+
+	return nil
+}
+
+// Matches function checks if the provided attributes satisfy the condition.
+// Unknown fields and operators never match.
+func (c TargetingCondition) Matches(attrs *TargetingAttributes) bool {
+	if c.Field == TimeWindowConditionField {
+		return c.matchTimeWindow(attrs.Time)
+	}
+
+	values, ok := c.attributeValues(attrs)
+	if !ok {
+		return false
+	}
+
+	switch c.Operator {
+	case InOperator:
+		return c.anyValue(values, strings.EqualFold)
+	case NotInOperator:
+		return !c.anyValue(values, strings.EqualFold)
+	case ContainsOperator:
+		return c.anyValue(values, containsFold)
+	case NotContainsOperator:
+		return !c.anyValue(values, containsFold)
+	case ExistsOperator:
+		return slices.ContainsFunc(values, func(v string) bool { return v != "" })
+	case NotExistsOperator:
+		return !slices.ContainsFunc(values, func(v string) bool { return v != "" })
+	default:
+		return false
+	}
+}
+
+// attributeValues function returns the list of attribute values referenced by the condition field.
+func (c TargetingCondition) attributeValues(attrs *TargetingAttributes) ([]string, bool) {
+	switch c.Field {
+	case CountryConditionField:
+		return []string{attrs.Country}, true
+	case RegionConditionField:
+		_, region, _ := strings.Cut(attrs.Region, "-")
+		return []string{attrs.Region, region}, true
+	case CityConditionField:
+		return []string{attrs.City}, true
+	case ASNConditionField:
+		if attrs.ASN == 0 {
+			return []string{""}, true
+		}
+
+		asn := strconv.FormatUint(uint64(attrs.ASN), 10)
+		return []string{asn, "AS" + asn}, true
+	case ASNOrganizationConditionField:
+		return []string{attrs.ASNOrganization}, true
+	case ISPConditionField:
+		return []string{attrs.ISP}, true
+	case ConnectionTypeConditionField:
+		return []string{attrs.ConnectionType}, true
+	case DeviceConditionField:
+		return []string{attrs.Device}, true
+	case OSConditionField:
+		return []string{attrs.OS}, true
+	case BrowserConditionField:
+		return []string{attrs.Browser}, true
+	case RefererConditionField:
+		return []string{attrs.Referer}, true
+	case ProtocolConditionField:
+		return []string{attrs.Protocol}, true
+	case BotConditionField:
+		return []string{strconv.FormatBool(attrs.Bot)}, true
+	case UniqueConditionField:
+		return []string{strconv.FormatBool(attrs.Unique)}, true
+	case QueryParamConditionField:
+		return attrs.Params[c.Key], true
+	case LanguageConditionField:
+		return attrs.Languages, true
+	default:
+		return nil, false
+	}
+}
+
+// anyValue function checks if any of the attribute values matches any of the condition values.
+func (c TargetingCondition) anyValue(attrValues []string, match func(attrValue, value string) bool) bool {
+	for _, attrValue := range attrValues {
+		for _, value := range c.Values {
+			if match(attrValue, value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// loadLocation function resolves IANA timezone name, UTC is returned for empty name.
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+
+	return location, nil
+}
+
+// matchTimeWindow function checks if the provided moment is inside one of "HH:MM-HH:MM" ranges.
+// Ranges where the end is before the start wrap around midnight.
+func (c TargetingCondition) matchTimeWindow(moment time.Time) bool {
+	if c.unknownTimezone {
+		return false
+	}
+
+	location := c.location
+	if location == nil {
+		loc, err := loadLocation(c.Key)
+		if err != nil {
+			return false
+		}
+
+		location = loc
+	}
+
+	moment = moment.In(location)
+	minute := moment.Hour()*60 + moment.Minute()
+
+	inWindow := false
+	for _, value := range c.Values {
+		from, to, ok := parseTimeRange(value)
+		if !ok {
+			continue
+		}
+
+		if (from <= to && minute >= from && minute < to) || (from > to && (minute >= from || minute < to)) {
+			inWindow = true
+			break
+		}
+	}
+
+	if c.Operator == NotInOperator {
+		return !inWindow
+	}
+
+	return inWindow
+}
+
+// parseTimeRange function parses "HH:MM-HH:MM" range into minutes since midnight.
+func parseTimeRange(value string) (from, to int, ok bool) {
+	start, end, found := strings.Cut(value, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	from, okFrom := parseClock(start)
+	to, okTo := parseClock(end)
+
+	return from, to, okFrom && okTo
+}
+
+// containsFold function reports whether substr is within s (case-insensitive).
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// TargetingConditions is a list of conditions stored as JSON.
+type TargetingConditions []TargetingCondition
+
+// Value returns the JSON-encoded representation.
+func (tc TargetingConditions) Value() (driver.Value, error) {
+	return json.Marshal([]TargetingCondition(tc))
+}
+
+// Scan decodes a JSON-encoded value. NULL values produce an empty list.
+func (tc *TargetingConditions) Scan(value interface{}) error {
+	if value == nil {
+		*tc = nil
+		return nil
+	}
+
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	x := make([]TargetingCondition, 0)
+	if err := json.Unmarshal(b, &x); err != nil {
+		return err
+	}
+
+	*tc = x
+	return nil
+}
+
+// TargetingRule describes a set of conditions combined with AND/OR logic
+// and the action applied to the traffic matching them.
+type TargetingRule struct {
+	// Priority defines the order of rules evaluation, rules with lower priority are evaluated first.
+	Priority int
+	// Logic defines how conditions are combined, AndLogic is used by default.
+	Logic string
+	// Conditions is the list of checks of the visitor attributes.
+	// Rule without conditions matches any traffic.
+	Conditions TargetingConditions
+	// Action describes how the matching traffic should be handled.
+	Action *RedirectRules
+}
+
+// Matches function checks if the provided attributes satisfy the rule conditions.
+func (tr *TargetingRule) Matches(attrs *TargetingAttributes) bool {
+	if len(tr.Conditions) == 0 {
+		return true
+	}
+
+	if strings.EqualFold(tr.Logic, OrLogic) {
+		for _, condition := range tr.Conditions {
+			if condition.Matches(attrs) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, condition := range tr.Conditions {
+		if !condition.Matches(attrs) {
+			return false
+		}
+	}
+
+	return true
+}
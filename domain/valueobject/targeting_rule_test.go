@@ -0,0 +1,279 @@
+package valueobject_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+func TestTargetingCondition_Matches(t *testing.T) {
+	attrs := &valueobject.TargetingAttributes{
+		Country:        "US",
+		Region:         "US-CA",
+		City:           "Los Angeles",
+		ASN:            7018,
+		ISP:            "AT&T Mobility",
+		ConnectionType: "Cellular",
+		Device:         "Mobile",
+		OS:             "Android",
+		Browser:        "Chrome",
+		Referer:        "https://news.example.com/article",
+		Protocol:       "https",
+		Bot:            false,
+		Params:         map[string][]string{"utm_source": {"facebook"}},
+		Languages:      []string{"en-us", "en"},
+		Time:           time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name      string
+		condition valueobject.TargetingCondition
+		want      bool
+	}{
+		{
+			name:      "country in list",
+			condition: valueobject.TargetingCondition{Field: "country", Operator: "in", Values: []string{"CA", "us"}},
+			want:      true,
+		},
+		{
+			name:      "country not in list",
+			condition: valueobject.TargetingCondition{Field: "country", Operator: "not_in", Values: []string{"US"}},
+			want:      false,
+		},
+		{
+			name:      "region with country prefix",
+			condition: valueobject.TargetingCondition{Field: "region", Operator: "in", Values: []string{"us-ca"}},
+			want:      true,
+		},
+		{
+			name:      "region without country prefix",
+			condition: valueobject.TargetingCondition{Field: "region", Operator: "in", Values: []string{"CA", "NY"}},
+			want:      true,
+		},
+		{
+			name:      "city not in list",
+			condition: valueobject.TargetingCondition{Field: "city", Operator: "not_in", Values: []string{"los angeles"}},
+			want:      false,
+		},
+		{
+			name:      "asn number",
+			condition: valueobject.TargetingCondition{Field: "asn", Operator: "in", Values: []string{"7018"}},
+			want:      true,
+		},
+		{
+			name:      "asn with prefix",
+			condition: valueobject.TargetingCondition{Field: "asn", Operator: "not_in", Values: []string{"as7018"}},
+			want:      false,
+		},
+		{
+			name:      "isp contains",
+			condition: valueobject.TargetingCondition{Field: "isp", Operator: "contains", Values: []string{"mobility"}},
+			want:      true,
+		},
+		{
+			name:      "connection type",
+			condition: valueobject.TargetingCondition{Field: "connection_type", Operator: "in", Values: []string{"cellular"}},
+			want:      true,
+		},
+		{
+			name:      "asn organization is unknown",
+			condition: valueobject.TargetingCondition{Field: "asn_org", Operator: "not_exists"},
+			want:      true,
+		},
+		{
+			name:      "referer contains",
+			condition: valueobject.TargetingCondition{Field: "referer", Operator: "contains", Values: []string{"NEWS."}},
+			want:      true,
+		},
+		{
+			name:      "referer not contains",
+			condition: valueobject.TargetingCondition{Field: "referer", Operator: "not_contains", Values: []string{"news."}},
+			want:      false,
+		},
+		{
+			name:      "query param value",
+			condition: valueobject.TargetingCondition{Field: "param", Operator: "in", Key: "utm_source", Values: []string{"facebook"}},
+			want:      true,
+		},
+		{
+			name:      "query param exists",
+			condition: valueobject.TargetingCondition{Field: "param", Operator: "exists", Key: "utm_source"},
+			want:      true,
+		},
+		{
+			name:      "query param not exists",
+			condition: valueobject.TargetingCondition{Field: "param", Operator: "not_exists", Key: "gclid"},
+			want:      true,
+		},
+		{
+			name:      "language",
+			condition: valueobject.TargetingCondition{Field: "language", Operator: "in", Values: []string{"en"}},
+			want:      true,
+		},
+		{
+			name:      "bot",
+			condition: valueobject.TargetingCondition{Field: "bot", Operator: "in", Values: []string{"true"}},
+			want:      false,
+		},
+		{
+			name:      "time window",
+			condition: valueobject.TargetingCondition{Field: "time", Operator: "in", Values: []string{"09:00-17:00"}},
+			want:      true,
+		},
+		{
+			name:      "time window outside",
+			condition: valueobject.TargetingCondition{Field: "time", Operator: "in", Values: []string{"11:00-17:00"}},
+			want:      false,
+		},
+		{
+			name:      "time window over midnight",
+			condition: valueobject.TargetingCondition{Field: "time", Operator: "in", Values: []string{"22:00-11:00"}},
+			want:      true,
+		},
+		{
+			name: "time window in timezone",
+			condition: valueobject.TargetingCondition{
+				Field:    "time",
+				Operator: "in",
+				Key:      "America/New_York",
+				Values:   []string{"09:00-17:00"},
+			},
+			want: false,
+		},
+		{
+			name:      "time window not in",
+			condition: valueobject.TargetingCondition{Field: "time", Operator: "not_in", Values: []string{"09:00-17:00"}},
+			want:      false,
+		},
+		{
+			name:      "unknown field",
+			condition: valueobject.TargetingCondition{Field: "unknown", Operator: "in", Values: []string{"US"}},
+			want:      false,
+		},
+		{
+			name:      "unknown operator",
+			condition: valueobject.TargetingCondition{Field: "country", Operator: "unknown", Values: []string{"US"}},
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.condition.Matches(attrs); got != tt.want {
+				t.Errorf("Matches() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTargetingRule_Matches(t *testing.T) {
+	attrs := &valueobject.TargetingAttributes{Country: "US", Device: "Mobile"}
+
+	usCondition := valueobject.TargetingCondition{Field: "country", Operator: "in", Values: []string{"US"}}
+	desktopCondition := valueobject.TargetingCondition{Field: "device", Operator: "in", Values: []string{"Desktop"}}
+
+	tests := []struct {
+		name string
+		rule *valueobject.TargetingRule
+		want bool
+	}{
+		{
+			name: "no conditions",
+			rule: &valueobject.TargetingRule{},
+			want: true,
+		},
+		{
+			name: "and logic",
+			rule: &valueobject.TargetingRule{
+				Logic:      valueobject.AndLogic,
+				Conditions: valueobject.TargetingConditions{usCondition, desktopCondition},
+			},
+			want: false,
+		},
+		{
+			name: "default logic is and",
+			rule: &valueobject.TargetingRule{
+				Conditions: valueobject.TargetingConditions{usCondition, desktopCondition},
+			},
+			want: false,
+		},
+		{
+			name: "or logic",
+			rule: &valueobject.TargetingRule{
+				Logic:      valueobject.OrLogic,
+				Conditions: valueobject.TargetingConditions{usCondition, desktopCondition},
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(attrs); got != tt.want {
+				t.Errorf("Matches() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTargetingConditions_Scan(t *testing.T) {
+	var got valueobject.TargetingConditions
+	if err := got.Scan(nil); err != nil || got != nil {
+		t.Errorf("Scan(nil) = %v, %v, want empty list", got, err)
+	}
+
+	if err := got.Scan(123); err == nil {
+		t.Error("expected type assertion error")
+	}
+
+	if err := got.Scan([]byte(`[{"field":"param","operator":"in","key":"src","values":["a"]}]`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := valueobject.TargetingConditions{{Field: "param", Operator: "in", Key: "src", Values: []string{"a"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() = %v, want %v", got, want)
+	}
+
+	unknownTimezone := `[{"field":"time","operator":"not_in","key":"Mars/Olympus","values":["09:00-17:00"]},` +
+		`{"field":"country","operator":"in","values":["US"]}]`
+	if err := got.Scan([]byte(unknownTimezone)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 2 || got[0].Matches(&valueobject.TargetingAttributes{Time: time.Now()}) {
+		t.Error("expected time window in unknown timezone to be skipped")
+	}
+	if !got[1].Matches(&valueobject.TargetingAttributes{Country: "US"}) {
+		t.Error("expected other conditions to be loaded")
+	}
+
+	if err := got.Scan([]byte(`[{"field":"time","operator":"in","key":"Europe/Kyiv","values":["09:00-17:00"]}]`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !got[0].Matches(&valueobject.TargetingAttributes{Time: time.Date(2024, 6, 3, 7, 0, 0, 0, time.UTC)}) {
+		t.Error("expected time window to match in the condition timezone")
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{header: "", want: []string{}},
+		{header: "en-US", want: []string{"en-us"}},
+		{header: "de;q=0.5, en-US,en;q=0.9, *;q=0.1", want: []string{"en-us", "en", "de"}},
+		{header: "fr;q=0, pl", want: []string{"pl"}},
+		{header: "fr;q=abc, pl", want: []string{"pl"}},
+	}
+
+	for _, tt := range tests {
+		if got := valueobject.ParseAcceptLanguage(tt.header); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAcceptLanguage(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -36,6 +36,12 @@ var (
 		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
 	})
 
+	// DeeplinksRejected tracks the number of rejected deeplinks per slug.
+	DeeplinksRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "redirector_deeplinks_rejected_total",
+		Help: "The total number of deeplinks rejected by scheme and domain checks per slug.",
+	}, []string{"slug"})
+
 	// ClickHandlerDuration tracks the processing time per click handler.
 	ClickHandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Name:    "redirector_click_handler_duration_seconds",
@@ -54,4 +60,10 @@ var (
 		Name: "redirector_clicks_processed_total",
 		Help: "The total number of clicks processed by status.",
 	}, []string{"status"}) // status: success, error, timeout
+
+	// ConversionsProcessed tracks the number of conversion postbacks processed with status.
+	ConversionsProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "redirector_conversions_processed_total",
+		Help: "The total number of conversion postbacks processed by status.",
+	}, []string{"status"}) // status: success, invalid, not_found, duplicate, error
 )
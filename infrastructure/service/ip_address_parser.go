@@ -3,37 +3,160 @@ package service
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
+	"strings"
 
 	"github.com/lroman242/redirector/domain/service"
+	"github.com/lroman242/redirector/domain/valueobject"
 	"github.com/oschwald/geoip2-golang"
 )
 
-// GeoIP2 implements service.IPAddressParserInterface, allows to find Country name from ip address.
+// englishLocale is the locale used for city names.
+const englishLocale = "en"
+
+// GeoIP2 implements service.IPAddressParserInterface, allows to find geo location from ip address.
+// City level data is resolved when the City (or Enterprise) database is used, otherwise only country is resolved.
+// Network data (ASN, ISP, connection type) is resolved when corresponding databases are provided.
 type GeoIP2 struct {
-	db *geoip2.Reader
+	db               *geoip2.Reader
+	isCity           bool
+	asnDB            *geoip2.Reader
+	isISP            bool
+	connectionTypeDB *geoip2.Reader
+}
+
+// GeoIP2Option configures optional databases of the GeoIP2 parser.
+type GeoIP2Option func(*GeoIP2)
+
+// WithASNDatabase sets GeoLite2-ASN (or GeoIP2-ISP) database used to resolve ASN, organization and ISP.
+func WithASNDatabase(db *geoip2.Reader) GeoIP2Option {
+	return func(g *GeoIP2) {
+		g.asnDB = db
+		g.isISP = strings.Contains(db.Metadata().DatabaseType, "ISP")
+	}
+}
+
+// WithConnectionTypeDatabase sets GeoIP2-Connection-Type database used to resolve connection type.
+func WithConnectionTypeDatabase(db *geoip2.Reader) GeoIP2Option {
+	return func(g *GeoIP2) {
+		g.connectionTypeDB = db
+	}
 }
 
 // NewGeoIP2 func creates new instance of GeoIP2.
-func NewGeoIP2(db *geoip2.Reader) service.IPAddressParserInterface {
-	return &GeoIP2{db: db}
+func NewGeoIP2(db *geoip2.Reader, options ...GeoIP2Option) service.IPAddressParserInterface {
+	dbType := db.Metadata().DatabaseType
+
+	g := &GeoIP2{
+		db:     db,
+		isCity: strings.Contains(dbType, "City") || strings.Contains(dbType, "Enterprise"),
+	}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
 }
 
-// Parse function parses country code from the provided IP address.
-func (g *GeoIP2) Parse(ip net.IP) (string, error) {
+// Parse function resolves geo location from the provided IP address.
+// Network data lookup errors are logged and don't fail parsing.
+func (g *GeoIP2) Parse(ip net.IP) (*valueobject.GeoLocation, error) {
 	if ip == nil {
-		return "", errors.New("ip address cannot be nil")
+		return nil, errors.New("ip address cannot be nil")
+	}
+
+	geo, err := g.parseLocation(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.parseNetwork(ip, geo); err != nil {
+		slog.Error("an error occurred while parsing ip address network", "ip", ip, "error", err)
+	}
+
+	return geo, nil
+}
+
+// parseLocation function resolves country and city level data.
+func (g *GeoIP2) parseLocation(ip net.IP) (*valueobject.GeoLocation, error) {
+	if !g.isCity {
+		record, err := g.db.Country(ip)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get country from IP: %w", err)
+		}
+
+		return &valueobject.GeoLocation{CountryCode: record.Country.IsoCode}, nil
 	}
 
-	record, err := g.db.Country(ip)
+	record, err := g.db.City(ip)
 	if err != nil {
-		return "", fmt.Errorf("failed to get country from IP: %w", err)
+		return nil, fmt.Errorf("failed to get city from IP: %w", err)
+	}
+
+	geo := &valueobject.GeoLocation{
+		CountryCode: record.Country.IsoCode,
+		City:        record.City.Names[englishLocale],
+		PostalCode:  record.Postal.Code,
+		Latitude:    record.Location.Latitude,
+		Longitude:   record.Location.Longitude,
+		TimeZone:    record.Location.TimeZone,
+	}
+	if len(record.Subdivisions) > 0 {
+		geo.Region = record.Subdivisions[0].IsoCode
 	}
 
-	return record.Country.IsoCode, nil
+	return geo, nil
 }
 
-// Close function closes connection to the geo ip database.
+// parseNetwork function resolves ASN, ISP and connection type data from the optional databases.
+func (g *GeoIP2) parseNetwork(ip net.IP, geo *valueobject.GeoLocation) error {
+	var errs []error
+
+	switch {
+	case g.asnDB != nil && g.isISP:
+		record, err := g.asnDB.ISP(ip)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get ISP from IP: %w", err))
+			break
+		}
+
+		geo.ASN = record.AutonomousSystemNumber
+		geo.ASNOrganization = record.AutonomousSystemOrganization
+		geo.ISP = record.ISP
+	case g.asnDB != nil:
+		record, err := g.asnDB.ASN(ip)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get ASN from IP: %w", err))
+			break
+		}
+
+		geo.ASN = record.AutonomousSystemNumber
+		geo.ASNOrganization = record.AutonomousSystemOrganization
+	}
+
+	if g.connectionTypeDB != nil {
+		record, err := g.connectionTypeDB.ConnectionType(ip)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get connection type from IP: %w", err))
+		} else {
+			geo.ConnectionType = record.ConnectionType
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close function closes connections to the geo ip databases.
 func (g *GeoIP2) Close() error {
-	return g.db.Close()
+	errs := []error{g.db.Close()}
+	if g.asnDB != nil {
+		errs = append(errs, g.asnDB.Close())
+	}
+	if g.connectionTypeDB != nil {
+		errs = append(errs, g.connectionTypeDB.Close())
+	}
+
+	return errors.Join(errs...)
 }
@@ -1,19 +1,26 @@
 package service_test
 
 import (
+	"encoding/binary"
 	"errors"
+	"io/fs"
+	"math"
 	"net"
+	"reflect"
 	"testing"
 
+	"github.com/lroman242/redirector/domain/valueobject"
 	"github.com/lroman242/redirector/infrastructure/service"
 	"github.com/oschwald/geoip2-golang"
 )
 
 func TestGeoIP2_Parse(t *testing.T) {
 	reader, err := geoip2.Open("./../../docker/GeoLite2-Country.mmdb")
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skip("GeoLite2-Country.mmdb is not available")
+	}
 	if err != nil {
-		t.Error("failed to open GeoLite2-Country.mmdb")
-		t.FailNow()
+		t.Fatalf("failed to open GeoLite2-Country.mmdb: %v", err)
 	}
 
 	geoIPParser := service.NewGeoIP2(reader)
@@ -40,13 +47,195 @@ func TestGeoIP2_Parse(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			countryCode, internalErr := geoIPParser.Parse(net.ParseIP(tc.ip))
+			geo, internalErr := geoIPParser.Parse(net.ParseIP(tc.ip))
 			if !errors.Is(internalErr, tc.expectedError) {
 				t.Errorf("unexpected error. expected %s but got %s\n", tc.expectedError, err)
 			}
-			if countryCode != tc.expectedCountry {
-				t.Errorf("unexpected country code. expected %s but got %s\n", tc.expectedCountry, countryCode)
+			if geo.CountryCode != tc.expectedCountry {
+				t.Errorf("unexpected country code. expected %s but got %s\n", tc.expectedCountry, geo.CountryCode)
+			}
+		})
+	}
+}
+
+func TestGeoIP2_Parse_Databases(t *testing.T) {
+	location := map[string]interface{}{
+		"city":    map[string]interface{}{"names": map[string]interface{}{"en": "Warsaw"}},
+		"country": map[string]interface{}{"iso_code": "PL"},
+		"location": map[string]interface{}{
+			"latitude":  52.2296,
+			"longitude": 21.0067,
+			"time_zone": "Europe/Warsaw",
+		},
+		"postal":       map[string]interface{}{"code": "00-001"},
+		"subdivisions": []interface{}{map[string]interface{}{"iso_code": "14"}},
+	}
+	network := map[string]interface{}{
+		"autonomous_system_number":       uint32(5617),
+		"autonomous_system_organization": "Orange Polska Spolka Akcyjna",
+		"isp":                            "Orange Polska",
+	}
+
+	testCases := []struct {
+		name     string
+		db       *geoip2.Reader
+		options  []service.GeoIP2Option
+		expected *valueobject.GeoLocation
+	}{
+		{
+			name:     "country database",
+			db:       newTestGeoIPReader(t, "GeoLite2-Country", location),
+			expected: &valueobject.GeoLocation{CountryCode: "PL"},
+		},
+		{
+			name: "city database",
+			db:   newTestGeoIPReader(t, "GeoLite2-City", location),
+			expected: &valueobject.GeoLocation{
+				CountryCode: "PL",
+				Region:      "14",
+				City:        "Warsaw",
+				PostalCode:  "00-001",
+				Latitude:    52.2296,
+				Longitude:   21.0067,
+				TimeZone:    "Europe/Warsaw",
+			},
+		},
+		{
+			name: "asn and connection type databases",
+			db:   newTestGeoIPReader(t, "GeoLite2-Country", location),
+			options: []service.GeoIP2Option{
+				service.WithASNDatabase(newTestGeoIPReader(t, "GeoLite2-ASN", network)),
+				service.WithConnectionTypeDatabase(
+					newTestGeoIPReader(t, "GeoIP2-Connection-Type", map[string]interface{}{"connection_type": "Cable/DSL"}),
+				),
+			},
+			expected: &valueobject.GeoLocation{
+				CountryCode:     "PL",
+				ASN:             5617,
+				ASNOrganization: "Orange Polska Spolka Akcyjna",
+				ConnectionType:  "Cable/DSL",
+			},
+		},
+		{
+			name:    "isp database",
+			db:      newTestGeoIPReader(t, "GeoLite2-Country", location),
+			options: []service.GeoIP2Option{service.WithASNDatabase(newTestGeoIPReader(t, "GeoIP2-ISP", network))},
+			expected: &valueobject.GeoLocation{
+				CountryCode:     "PL",
+				ASN:             5617,
+				ASNOrganization: "Orange Polska Spolka Akcyjna",
+				ISP:             "Orange Polska",
+			},
+		},
+		{
+			name:    "network lookup errors don't fail parsing",
+			db:      newTestGeoIPReader(t, "GeoLite2-Country", location),
+			options: []service.GeoIP2Option{service.WithConnectionTypeDatabase(newTestGeoIPReader(t, "GeoLite2-ASN", network))},
+			expected: &valueobject.GeoLocation{
+				CountryCode: "PL",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			geo, err := service.NewGeoIP2(tc.db, tc.options...).Parse(net.ParseIP("178.43.70.56"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(geo, tc.expected) {
+				t.Errorf("unexpected geo location. expected %+v but got %+v", tc.expected, geo)
 			}
 		})
 	}
 }
+
+// newTestGeoIPReader function builds IPv4 MaxMind DB of the provided type, all addresses resolve to the record.
+func newTestGeoIPReader(t *testing.T, databaseType string, record map[string]interface{}) *geoip2.Reader {
+	t.Helper()
+
+	metadata := map[string]interface{}{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"build_epoch":                 uint32(1700000000),
+		"database_type":               databaseType,
+		"description":                 map[string]interface{}{"en": "test database"},
+		"ip_version":                  uint16(4),
+		"languages":                   []interface{}{"en"},
+		"node_count":                  uint32(1),
+		"record_size":                 uint16(24),
+	}
+
+	// single node search tree, both records point to the first record of the data section:
+	// node count + data section separator size + data offset
+	db := []byte{0, 0, 17, 0, 0, 17}
+	db = append(db, make([]byte, 16)...)
+	db = appendMMDBValue(db, record)
+	db = append(db, "\xAB\xCD\xEFMaxMind.com"...)
+	db = appendMMDBValue(db, metadata)
+
+	reader, err := geoip2.FromBytes(db)
+	if err != nil {
+		t.Fatalf("failed to build %s database: %v", databaseType, err)
+	}
+
+	return reader
+}
+
+// appendMMDBValue function encodes the value in MaxMind DB data section format.
+func appendMMDBValue(b []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		b = appendMMDBControl(b, 2, len(v))
+		return append(b, v...)
+	case float64:
+		b = appendMMDBControl(b, 3, 8)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(v))
+	case uint16:
+		b = appendMMDBControl(b, 5, 2)
+		return binary.BigEndian.AppendUint16(b, v)
+	case uint32:
+		b = appendMMDBControl(b, 6, 4)
+		return binary.BigEndian.AppendUint32(b, v)
+	case map[string]interface{}:
+		b = appendMMDBControl(b, 7, len(v))
+		for key, item := range v {
+			b = appendMMDBValue(b, key)
+			b = appendMMDBValue(b, item)
+		}
+
+		return b
+	case []interface{}:
+		b = appendMMDBControl(b, 11, len(v))
+		for _, item := range v {
+			b = appendMMDBValue(b, item)
+		}
+
+		return b
+	default:
+		panic("unsupported MaxMind DB value type")
+	}
+}
+
+// appendMMDBControl function encodes the control byte of the value, sizes above 284 are not supported.
+func appendMMDBControl(b []byte, dataType, size int) []byte {
+	typeBits, extendedType := byte(dataType<<5), -1
+	if dataType > 7 {
+		typeBits, extendedType = 0, dataType-7
+	}
+
+	if size < 29 {
+		b = append(b, typeBits|byte(size))
+	} else {
+		b = append(b, typeBits|29)
+	}
+	if extendedType >= 0 {
+		b = append(b, byte(extendedType))
+	}
+	if size >= 29 {
+		b = append(b, byte(size-29))
+	}
+
+	return b
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/lroman242/redirector/domain/service"
+)
+
+// maxPostbackResponseSize is the number of response body bytes read before the connection is reused.
+const maxPostbackResponseSize = 64 * 1024
+
+// HTTPPostbackSender implements service.PostbackSenderInterface using GET requests.
+type HTTPPostbackSender struct {
+	client *http.Client
+}
+
+// NewHTTPPostbackSender creates a new HTTPPostbackSender instance, requests are cancelled after the timeout.
+func NewHTTPPostbackSender(timeout time.Duration) service.PostbackSenderInterface {
+	return &HTTPPostbackSender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send function requests the postback URL and returns the response status code.
+// Redirects are followed, the status code of the last response is returned.
+func (s *HTTPPostbackSender) Send(ctx context.Context, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create postback request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send postback: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// the body is drained so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxPostbackResponseSize))
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPPostbackSender_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			if r.URL.Query().Get("click_id") != "click-1" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	sender := NewHTTPPostbackSender(100 * time.Millisecond)
+
+	tests := []struct {
+		name         string
+		url          string
+		expectedCode int
+		expectErr    bool
+	}{
+		{name: "delivered", url: server.URL + "/ok?click_id=click-1", expectedCode: http.StatusOK},
+		{name: "server error", url: server.URL + "/down", expectedCode: http.StatusServiceUnavailable},
+		{name: "timeout", url: server.URL + "/slow", expectErr: true},
+		{name: "invalid url", url: "://invalid", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := sender.Send(context.Background(), tt.url)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if code != tt.expectedCode {
+				t.Errorf("unexpected status code. expected %d got %d", tt.expectedCode, code)
+			}
+		})
+	}
+}
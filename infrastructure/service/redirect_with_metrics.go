@@ -4,6 +4,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/lroman242/redirector/domain/dto"
@@ -28,8 +29,8 @@ func NewRedirectWithMetrics(redirectInteractor interactor.RedirectInteractor) *R
 }
 
 // Redirect handles redirect requests and tracks metrics about the operation.
-// It increments counters for total redirects and redirects by slug, and measures
-// the execution time of the redirect operation.
+// It increments counters for total redirects and redirects by slug, counts rejected deeplinks,
+// and measures the execution time of the redirect operation.
 func (r *RedirectWithMetrics) Redirect(ctx context.Context, slug string, requestData *dto.RedirectRequestData) (*dto.RedirectResult, error) {
 	// Track total redirects and redirects by slug
 	metrics.RedirectTotal.Inc()
@@ -41,5 +42,11 @@ func (r *RedirectWithMetrics) Redirect(ctx context.Context, slug string, request
 		metrics.RedirectDuration.Observe(time.Since(startTime).Seconds())
 	}()
 
-	return r.RedirectInteractor.Redirect(ctx, slug, requestData)
+	result, err := r.RedirectInteractor.Redirect(ctx, slug, requestData)
+	if errors.Is(err, interactor.ErrDeeplinkNotAllowed) ||
+		(result != nil && errors.Is(result.Reason, interactor.ErrDeeplinkNotAllowed)) {
+		metrics.DeeplinksRejected.WithLabelValues(slug).Inc()
+	}
+
+	return result, err
 }
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lroman242/redirector/domain/service"
+	"github.com/redis/go-redis/v9"
+)
+
+// uniqueClickKeyPrefix is used to create Redis keys for already seen clicks
+const uniqueClickKeyPrefix = "uniq:"
+
+// RedisUniqueClickDetector implements service.UniqueClickDetectorInterface using Redis keys with expiration.
+type RedisUniqueClickDetector struct {
+	client *redis.Client
+	window time.Duration
+}
+
+// NewRedisUniqueClickDetector creates a new RedisUniqueClickDetector instance.
+func NewRedisUniqueClickDetector(client *redis.Client, window time.Duration) service.UniqueClickDetectorInterface {
+	return &RedisUniqueClickDetector{
+		client: client,
+		window: window,
+	}
+}
+
+// IsUnique function reports whether the key wasn't remembered during the deduplication window.
+func (d *RedisUniqueClickDetector) IsUnique(ctx context.Context, key string) (bool, error) {
+	seen, err := d.client.Exists(ctx, d.makeKey(key)).Result()
+	if err != nil {
+		return true, fmt.Errorf("failed to check click key: %w", err)
+	}
+
+	return seen == 0, nil
+}
+
+// Remember function remembers the key for the deduplication window.
+// The window of already remembered key is not extended.
+func (d *RedisUniqueClickDetector) Remember(ctx context.Context, key string) error {
+	if err := d.client.SetNX(ctx, d.makeKey(key), 1, d.window).Err(); err != nil {
+		return fmt.Errorf("failed to store click key: %w", err)
+	}
+
+	return nil
+}
+
+// makeKey function builds the Redis key of the click key.
+func (d *RedisUniqueClickDetector) makeKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return uniqueClickKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// BloomUniqueClickDetector implements service.UniqueClickDetectorInterface using in-memory bloom filters.
+// Two filters are rotated every window, so a key is remembered for at least one and at most two windows.
+// Bloom filters might report a small share of unique clicks as duplicates (false positives).
+type BloomUniqueClickDetector struct {
+	mu                sync.Mutex
+	window            time.Duration
+	capacity          uint
+	falsePositiveRate float64
+	current           *bloomFilter
+	previous          *bloomFilter
+	rotatedAt         time.Time
+}
+
+// NewBloomUniqueClickDetector creates a new BloomUniqueClickDetector instance.
+// Capacity is the expected number of clicks per window used to size the filters.
+func NewBloomUniqueClickDetector(
+	window time.Duration,
+	capacity uint,
+	falsePositiveRate float64,
+) service.UniqueClickDetectorInterface {
+	return &BloomUniqueClickDetector{
+		window:            window,
+		capacity:          capacity,
+		falsePositiveRate: falsePositiveRate,
+		current:           newBloomFilter(capacity, falsePositiveRate),
+		previous:          newBloomFilter(capacity, falsePositiveRate),
+		rotatedAt:         time.Now(),
+	}
+}
+
+// IsUnique function reports whether the key wasn't remembered during the deduplication window.
+func (d *BloomUniqueClickDetector) IsUnique(_ context.Context, key string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.rotate()
+
+	h1, h2 := bloomHashes(key)
+
+	return !d.current.contains(h1, h2) && !d.previous.contains(h1, h2), nil
+}
+
+// Remember function remembers the key for the deduplication window.
+func (d *BloomUniqueClickDetector) Remember(_ context.Context, key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.rotate()
+
+	h1, h2 := bloomHashes(key)
+	if !d.previous.contains(h1, h2) {
+		d.current.add(h1, h2)
+	}
+
+	return nil
+}
+
+// rotate function replaces the filters once the window is elapsed, it must be called with the lock held.
+func (d *BloomUniqueClickDetector) rotate() {
+	elapsed := time.Since(d.rotatedAt)
+	if elapsed < d.window {
+		return
+	}
+
+	d.previous = d.current
+	if elapsed >= 2*d.window {
+		d.previous = newBloomFilter(d.capacity, d.falsePositiveRate)
+	}
+
+	d.current = newBloomFilter(d.capacity, d.falsePositiveRate)
+	d.rotatedAt = time.Now()
+}
+
+// bloomFilter is a fixed size bloom filter using double hashing.
+type bloomFilter struct {
+	bits   []uint64
+	size   uint64
+	hashes uint64
+}
+
+// newBloomFilter function creates bloom filter sized for the expected number of items and false positive rate.
+func newBloomFilter(capacity uint, falsePositiveRate float64) *bloomFilter {
+	if capacity == 0 {
+		capacity = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.01
+	}
+
+	size := uint64(math.Ceil(-float64(capacity) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	hashes := uint64(math.Max(1, math.Round(float64(size)/float64(capacity)*math.Ln2)))
+
+	return &bloomFilter{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+// add function sets bits of the item identified by hashes.
+func (f *bloomFilter) add(h1, h2 uint64) {
+	for i := uint64(0); i < f.hashes; i++ {
+		bit := (h1 + i*h2) % f.size
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// contains function checks if all bits of the item identified by hashes are set.
+func (f *bloomFilter) contains(h1, h2 uint64) bool {
+	for i := uint64(0); i < f.hashes; i++ {
+		bit := (h1 + i*h2) % f.size
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bloomHashes function calculates two independent hashes of the key used for double hashing.
+func bloomHashes(key string) (uint64, uint64) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	h1 := h.Sum64()
+
+	h = fnv.New64()
+	_, _ = h.Write([]byte(key))
+	h2 := h.Sum64() | 1
+
+	return h1, h2
+}
@@ -0,0 +1,60 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lroman242/redirector/infrastructure/service"
+)
+
+func TestBloomUniqueClickDetector_IsUnique(t *testing.T) {
+	detector := service.NewBloomUniqueClickDetector(time.Hour, 1000, 0.001)
+	ctx := context.Background()
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("test-slug|192.168.1.%d|Mozilla/5.0", i)
+
+		unique, err := detector.IsUnique(ctx, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !unique {
+			t.Errorf("expected first click with key %s to be unique", key)
+		}
+
+		if unique, _ = detector.IsUnique(ctx, key); !unique {
+			t.Errorf("expected click with key %s to be unique until it is remembered", key)
+		}
+
+		if err = detector.Remember(ctx, key); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		unique, err = detector.IsUnique(ctx, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if unique {
+			t.Errorf("expected repeated click with key %s to be duplicate", key)
+		}
+	}
+}
+
+func TestBloomUniqueClickDetector_WindowExpiration(t *testing.T) {
+	detector := service.NewBloomUniqueClickDetector(10*time.Millisecond, 100, 0.001)
+	ctx := context.Background()
+	key := "test-slug|192.168.1.1|Mozilla/5.0"
+
+	_ = detector.Remember(ctx, key)
+	if unique, _ := detector.IsUnique(ctx, key); unique {
+		t.Fatal("expected remembered click to be duplicate")
+	}
+
+	time.Sleep(25 * time.Millisecond)
+
+	if unique, _ := detector.IsUnique(ctx, key); !unique {
+		t.Error("expected click to be unique after the deduplication window")
+	}
+}
@@ -4,6 +4,7 @@ package service
 
 import (
 	"errors"
+	"regexp"
 
 	"github.com/lroman242/redirector/domain/service"
 	"github.com/lroman242/redirector/domain/valueobject"
@@ -13,6 +14,16 @@ import (
 // ErrEmptyUserAgent is returned when the provided User-Agent string is empty.
 var ErrEmptyUserAgent = errors.New("provided empty user agent")
 
+// botDeviceFamily is the device family assigned by ua-parser to known spiders and crawlers.
+const botDeviceFamily = "Spider"
+
+// botPattern matches User-Agent strings of crawlers, link-preview bots and uptime monitors
+// which are not always recognized by the ua-parser database.
+// HTTP library and headless browser markers are not matched, they are also sent by in-app browsers of real users.
+var botPattern = regexp.MustCompile(`(?i)(\bbot\b|bot[/;)\-]|bot \(|crawl|spider|slurp|mediapartners|` +
+	`facebookexternalhit|facebookcatalog|embedly|quora link preview|outbrain|vkshare|` +
+	`skypeuripreview|nuzzel|lighthouse|pingdom|uptimerobot|statuscake)`)
+
 // UserAgentParser implements service.UserAgentParserInterface using the ua-parser library.
 // It provides functionality to parse User-Agent strings and extract device, platform,
 // and browser information.
@@ -45,6 +56,7 @@ func (p *UserAgentParser) Parse(userAgent string) (*valueobject.UserAgent, error
 	ua.Device = client.Device.Family
 	ua.Platform = client.Os.Family
 	ua.Browser = client.UserAgent.Family
+	ua.Bot = client.Device.Family == botDeviceFamily || botPattern.MatchString(userAgent)
 
 	return ua, nil
 }
@@ -11,6 +11,11 @@ import (
 const (
 	MacSafariUserAgent     = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/603.3.8 (KHTML, like Gecko) Version/10.1.2 Safari/603.3.8"
 	SamsungSafariUserAgent = "Mozilla/5.0 (Linux; Android 4.3; GT-I9300 Build/JSS15J) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/55.0.2883.91 Mobile Safari/537.36 OPR/42.9.2246.119956"
+	GooglebotUserAgent     = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	FacebookUserAgent      = "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)"
+	CurlUserAgent          = "curl/8.4.0"
+	WhatsAppUserAgent      = "Mozilla/5.0 (Linux; Android 13; SM-A536B Build/TP1A.220624.014; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/120.0.6099.144 Mobile Safari/537.36 WhatsApp/2.23.25.83"
+	OkHttpUserAgent        = "okhttp/4.12.0"
 )
 
 func TestUserAgentParser_Parse(t *testing.T) {
@@ -44,6 +49,66 @@ func TestUserAgentParser_Parse(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name:         "Googlebot",
+			userAgentStr: GooglebotUserAgent,
+			expectedUserAgentObj: &valueobject.UserAgent{
+				SrcString: GooglebotUserAgent,
+				Bot:       true,
+				Device:    "Spider",
+				Platform:  "Other",
+				Browser:   "Googlebot",
+			},
+			expectedError: nil,
+		},
+		{
+			name:         "Facebook link preview",
+			userAgentStr: FacebookUserAgent,
+			expectedUserAgentObj: &valueobject.UserAgent{
+				SrcString: FacebookUserAgent,
+				Bot:       true,
+				Device:    "Spider",
+				Platform:  "Other",
+				Browser:   "FacebookBot",
+			},
+			expectedError: nil,
+		},
+		{
+			name:         "curl",
+			userAgentStr: CurlUserAgent,
+			expectedUserAgentObj: &valueobject.UserAgent{
+				SrcString: CurlUserAgent,
+				Bot:       false,
+				Device:    "Other",
+				Platform:  "Other",
+				Browser:   "curl",
+			},
+			expectedError: nil,
+		},
+		{
+			name:         "WhatsApp in-app browser",
+			userAgentStr: WhatsAppUserAgent,
+			expectedUserAgentObj: &valueobject.UserAgent{
+				SrcString: WhatsAppUserAgent,
+				Bot:       false,
+				Device:    "Samsung SM-A536B",
+				Platform:  "Android",
+				Browser:   "WhatsApp",
+			},
+			expectedError: nil,
+		},
+		{
+			name:         "okhttp",
+			userAgentStr: OkHttpUserAgent,
+			expectedUserAgentObj: &valueobject.UserAgent{
+				SrcString: OkHttpUserAgent,
+				Bot:       false,
+				Device:    "Other",
+				Platform:  "Other",
+				Browser:   "okhttp",
+			},
+			expectedError: nil,
+		},
 		{
 			name:         "empty user agent",
 			userAgentStr: "",
@@ -79,6 +144,10 @@ func TestUserAgentParser_Parse(t *testing.T) {
 				t.Errorf("unexpected device value parsed. expected %s but got %s\n",
 					tc.expectedUserAgentObj.Device, ua.Device)
 			}
+			if ua.Bot != tc.expectedUserAgentObj.Bot {
+				t.Errorf("unexpected bot value parsed. expected %t but got %t\n",
+					tc.expectedUserAgentObj.Bot, ua.Bot)
+			}
 		})
 	}
 }
@@ -3,7 +3,9 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"net"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
@@ -13,23 +15,33 @@ import (
 const clickhouseInsertClickQuery = `
 	INSERT INTO clicks (
 		id, target_url, referer, trk_url, slug, parent_slug,
-		source_id, campaign_id, affiliate_id, advertiser_id, is_parallel,
-		landing_id, gclid,
-		user_agent, agent, platform, browser, device,
-		ip, country_code,
+		source_id, campaign_id, affiliate_id, advertiser_id, is_parallel, is_unique, is_cloaked,
+		landing_id, gclid, ad_click_ids,
+		user_agent, agent, platform, browser, device, bot,
+		ip, country_code, region, city, postal_code, latitude, longitude, timezone, language,
+		asn, asn_org, isp, connection_type,
 		p1, p2, p3, p4,
 		created_at
 	) VALUES (
 		?, ?, ?, ?, ?, ?,
-		?, ?, ?, ?, ?,
-		?, ?,
-		?, ?, ?, ?, ?,
-		?, ?,
+		?, ?, ?, ?, ?, ?, ?,
+		?, ?, ?,
+		?, ?, ?, ?, ?, ?,
+		?, ?, ?, ?, ?, ?, ?, ?, ?,
+		?, ?, ?, ?,
 		?, ?, ?, ?,
 		?
 	)
 `
 
+const clickhouseFindClickQuery = `
+	SELECT id, slug, source_id, campaign_id, affiliate_id, advertiser_id, landing_id, created_at,
+		ip, country_code, p1, p2, p3, p4
+	FROM clicks
+	WHERE id = ?
+	LIMIT 1
+`
+
 // ClickhouseStorage implements ClicksRepository interface using Clickhouse as the underlying storage.
 // It provides methods to store and manage click tracking data.
 type ClickhouseStorage struct {
@@ -41,6 +53,17 @@ type ClickhouseStorage struct {
 // and returns a configured storage instance ready for use.
 // Panics if unable to establish a connection to the database.
 func NewClickHouseStorage(host, port, database, username, password string) *ClickhouseStorage {
+	return NewClickHouseSessionStorage(NewClickHouseDB(host, port, database, username, password))
+}
+
+// NewClickHouseSessionStorage creates a new ClickhouseStorage instance using the already opened connection pool.
+func NewClickHouseSessionStorage(session *sql.DB) *ClickhouseStorage {
+	return &ClickhouseStorage{session: session}
+}
+
+// NewClickHouseDB opens the connection pool to the Clickhouse database.
+// Panics if unable to establish a connection to the database.
+func NewClickHouseDB(host, port, database, username, password string) *sql.DB {
 	conn := clickhouse.OpenDB(&clickhouse.Options{
 		Addr: []string{fmt.Sprintf("%s:%s", host, port)},
 		Auth: clickhouse.Auth{
@@ -68,7 +91,7 @@ func NewClickHouseStorage(host, port, database, username, password string) *Clic
 		panic(err)
 	}
 
-	return &ClickhouseStorage{session: conn}
+	return conn
 }
 
 // Save stores a Click record in the Clickhouse database.
@@ -100,15 +123,30 @@ func (c *ClickhouseStorage) Save(ctx context.Context, click *entity.Click) error
 		click.AffiliateID,
 		click.AdvertiserID,
 		click.IsParallel,
+		click.IsUnique,
+		click.IsCloaked,
 		click.LandingID,
 		click.GCLID,
+		adClickIDs(click),
 		click.UserAgent.SrcString,
 		click.Agent,
 		click.Platform,
 		click.Browser,
 		click.Device,
+		click.Bot,
 		click.IP.String(),
 		click.CountryCode,
+		click.Region,
+		click.City,
+		click.PostalCode,
+		click.Latitude,
+		click.Longitude,
+		click.TimeZone,
+		click.Language,
+		uint32(click.ASN),
+		click.ASNOrganization,
+		click.ISP,
+		click.ConnectionType,
 		click.P1,
 		click.P2,
 		click.P3,
@@ -125,3 +163,45 @@ func (c *ClickhouseStorage) Save(ctx context.Context, click *entity.Click) error
 
 	return nil
 }
+
+// FindClick returns the click by ID, nil is returned if the click doesn't exist.
+// Only the fields required for conversions attribution and affiliate postbacks are loaded.
+func (c *ClickhouseStorage) FindClick(ctx context.Context, id string) (*entity.Click, error) {
+	var ip string
+	click := new(entity.Click)
+	err := c.session.QueryRowContext(ctx, clickhouseFindClickQuery, id).Scan(
+		&click.ID,
+		&click.Slug,
+		&click.SourceID,
+		&click.CampaignID,
+		&click.AffiliateID,
+		&click.AdvertiserID,
+		&click.LandingID,
+		&click.CreatedAt,
+		&ip,
+		&click.CountryCode,
+		&click.P1,
+		&click.P2,
+		&click.P3,
+		&click.P4,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find click: %w", err)
+	}
+
+	click.IP = net.ParseIP(ip)
+
+	return click, nil
+}
+
+// adClickIDs function returns click identifiers of the click, ClickHouse Map column doesn't accept nil maps.
+func adClickIDs(click *entity.Click) map[string]string {
+	if click.AdClickIDs == nil {
+		return make(map[string]string)
+	}
+
+	return click.AdClickIDs
+}
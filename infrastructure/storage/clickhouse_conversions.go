@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lroman242/redirector/domain/entity"
+)
+
+const clickhouseInsertConversionQuery = `
+	INSERT INTO conversions (
+		id, click_id, transaction_id, event_type, payout,
+		slug, source_id, campaign_id, affiliate_id, advertiser_id, landing_id,
+		clicked_at, created_at
+	) VALUES (
+		?, ?, ?, ?, ?,
+		?, ?, ?, ?, ?, ?,
+		?, ?
+	)
+`
+
+// ClickhouseConversionsStorage implements ConversionsRepository interface using Clickhouse as the underlying storage.
+type ClickhouseConversionsStorage struct {
+	session *sql.DB
+}
+
+// NewClickhouseConversionsStorage creates a new ClickhouseConversionsStorage instance.
+func NewClickhouseConversionsStorage(session *sql.DB) *ClickhouseConversionsStorage {
+	return &ClickhouseConversionsStorage{session: session}
+}
+
+// Save stores a Conversion record in the Clickhouse database.
+func (c *ClickhouseConversionsStorage) Save(ctx context.Context, conversion *entity.Conversion) error {
+	scope, err := c.session.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer scope.Rollback()
+
+	batch, err := scope.PrepareContext(ctx, clickhouseInsertConversionQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer batch.Close()
+
+	_, err = batch.ExecContext(ctx,
+		conversion.ID,
+		conversion.ClickID,
+		conversion.TransactionID,
+		conversion.EventType,
+		conversion.Payout,
+		conversion.Slug,
+		conversion.SourceID,
+		conversion.CampaignID,
+		conversion.AffiliateID,
+		conversion.AdvertiserID,
+		conversion.LandingID,
+		conversion.ClickedAt,
+		conversion.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to execute statement: %w", err)
+	}
+
+	if err = scope.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
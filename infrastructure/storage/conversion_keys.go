@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lroman242/redirector/domain/entity"
+)
+
+// reserveConversionKeyQuery inserts the conversion deduplication key, already reserved keys are ignored
+const reserveConversionKeyQuery = `
+INSERT INTO conversion_keys (key, conversion_id, created_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (key) DO NOTHING`
+
+// releaseConversionKeyQuery removes the deduplication key reserved by the conversion
+const releaseConversionKeyQuery = `
+DELETE FROM conversion_keys
+WHERE key = $1 AND conversion_id = $2`
+
+// ConversionKeysStorage implements repository.ConversionKeysRepository using Postgres as the underlying storage.
+// The primary key of the keys table makes the reservation atomic.
+type ConversionKeysStorage struct {
+	db *sql.DB
+}
+
+// NewConversionKeysStorage creates a new ConversionKeysStorage instance.
+func NewConversionKeysStorage(db *sql.DB) *ConversionKeysStorage {
+	return &ConversionKeysStorage{db: db}
+}
+
+// Reserve stores the deduplication key of the conversion, false is returned if the key is already stored.
+func (s *ConversionKeysStorage) Reserve(ctx context.Context, conversion *entity.Conversion) (bool, error) {
+	result, err := s.db.ExecContext(ctx, reserveConversionKeyQuery,
+		conversion.DeduplicationKey(),
+		conversion.ID,
+		conversion.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve conversion key: %w", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve conversion key: %w", err)
+	}
+
+	return inserted == 1, nil
+}
+
+// Release removes the deduplication key reserved by the conversion.
+func (s *ConversionKeysStorage) Release(ctx context.Context, conversion *entity.Conversion) error {
+	if _, err := s.db.ExecContext(ctx, releaseConversionKeyQuery, conversion.DeduplicationKey(), conversion.ID); err != nil {
+		return fmt.Errorf("failed to release conversion key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/lroman242/redirector/domain/valueobject"
+	"github.com/lroman242/redirector/infrastructure/logger"
+)
+
+const (
+	// allowIPListAction marks networks which are always allowed
+	allowIPListAction = "allow"
+	// denyIPListAction marks networks which are blocked
+	denyIPListAction = "deny"
+)
+
+// findGlobalIPListsQuery selects active global IP lists entries
+const findGlobalIPListsQuery = `
+SELECT cidr::text, action
+FROM ip_lists
+WHERE active = true`
+
+// IPListsStorage implements repository.IPListsRepository.
+// Global lists are loaded from Postgres and an optional local file and kept in memory.
+type IPListsStorage struct {
+	db       *sql.DB
+	filePath string
+	lists    atomic.Pointer[valueobject.IPLists]
+}
+
+// NewIPListsStorage creates a new IPListsStorage instance.
+// Both db and filePath are optional, lists are empty until Load is called.
+func NewIPListsStorage(db *sql.DB, filePath string) *IPListsStorage {
+	s := &IPListsStorage{
+		db:       db,
+		filePath: filePath,
+	}
+	s.lists.Store(new(valueobject.IPLists))
+
+	return s
+}
+
+// FindGlobalIPLists returns the last loaded lists.
+func (s *IPListsStorage) FindGlobalIPLists(_ context.Context) valueobject.IPLists {
+	return *s.lists.Load()
+}
+
+// Load function reads lists from all sources and replaces the lists in memory.
+// Previously loaded lists are kept if any of the sources fails.
+func (s *IPListsStorage) Load(ctx context.Context) error {
+	allowed := make([]string, 0)
+	denied := make([]string, 0)
+
+	if s.db != nil {
+		dbAllowed, dbDenied, err := s.loadFromDB(ctx)
+		if err != nil {
+			return err
+		}
+
+		allowed = append(allowed, dbAllowed...)
+		denied = append(denied, dbDenied...)
+	}
+
+	if s.filePath != "" {
+		fileAllowed, fileDenied, err := s.loadFromFile()
+		if err != nil {
+			return err
+		}
+
+		allowed = append(allowed, fileAllowed...)
+		denied = append(denied, fileDenied...)
+	}
+
+	allowedList, err := valueobject.NewCIDRList(allowed)
+	if err != nil {
+		return fmt.Errorf("failed to build allowed IP list: %w", err)
+	}
+
+	deniedList, err := valueobject.NewCIDRList(denied)
+	if err != nil {
+		return fmt.Errorf("failed to build denied IP list: %w", err)
+	}
+
+	s.lists.Store(&valueobject.IPLists{Allowed: allowedList, Denied: deniedList})
+
+	return nil
+}
+
+// Watch function periodically reloads lists until the context is canceled.
+func (s *IPListsStorage) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Load(ctx); err != nil {
+				slog.Error("an error occurred while reloading global IP lists", logger.ErrAttr(err))
+			}
+		}
+	}
+}
+
+// loadFromDB loads active global IP lists entries from the database
+func (s *IPListsStorage) loadFromDB(ctx context.Context) (allowed, denied []string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, findGlobalIPListsQuery)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute global IP lists query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cidr, action string
+		if err := rows.Scan(&cidr, &action); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan global IP list entry: %w", err)
+		}
+
+		if strings.EqualFold(action, allowIPListAction) {
+			allowed = append(allowed, cidr)
+		} else {
+			denied = append(denied, cidr)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating global IP lists rows: %w", err)
+	}
+
+	return allowed, denied, nil
+}
+
+// loadFromFile loads IP lists from the local file.
+// Each line contains a network optionally prefixed with "allow" or "deny" action (deny is used by default),
+// empty lines and lines starting with "#" are ignored.
+func (s *IPListsStorage) loadFromFile() (allowed, denied []string, err error) {
+	file, err := os.Open(s.filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open IP lists file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 1:
+			denied = append(denied, fields[0])
+		case len(fields) == 2 && strings.EqualFold(fields[0], allowIPListAction):
+			allowed = append(allowed, fields[1])
+		case len(fields) == 2 && strings.EqualFold(fields[0], denyIPListAction):
+			denied = append(denied, fields[1])
+		default:
+			return nil, nil, fmt.Errorf("invalid IP lists file entry at line %d: %q", lineNumber, line)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read IP lists file: %w", err)
+	}
+
+	return allowed, denied, nil
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// findPostbackSecretQuery selects the secret the advertiser signs conversion postbacks with
+const findPostbackSecretQuery = `
+SELECT secret
+FROM advertiser_postback_secrets
+WHERE advertiser_id = $1`
+
+// PostbackSecretsStorage implements repository.PostbackSecretsRepository using Postgres as the underlying storage.
+type PostbackSecretsStorage struct {
+	db *sql.DB
+}
+
+// NewPostbackSecretsStorage creates a new PostbackSecretsStorage instance.
+func NewPostbackSecretsStorage(db *sql.DB) *PostbackSecretsStorage {
+	return &PostbackSecretsStorage{db: db}
+}
+
+// FindSecret returns the postback secret of the advertiser, empty string is returned if it's not set.
+func (s *PostbackSecretsStorage) FindSecret(ctx context.Context, advertiserID string) (string, error) {
+	var secret string
+
+	err := s.db.QueryRowContext(ctx, findPostbackSecretQuery, advertiserID).Scan(&secret)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find postback secret: %w", err)
+	}
+
+	return secret, nil
+}
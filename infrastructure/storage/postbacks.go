@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lroman242/redirector/domain/entity"
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+// findPostbackURLTemplateQuery selects the source postback URL template, falling back to the affiliate one
+const findPostbackURLTemplateQuery = `
+SELECT url_template
+FROM affiliate_postback_urls
+WHERE affiliate_id = $1 AND (source_id = $2 OR source_id = '')
+ORDER BY source_id = ''
+LIMIT 1`
+
+// enqueuePostbackQuery inserts the postback to the delivery queue, already queued postbacks are ignored
+const enqueuePostbackQuery = `
+INSERT INTO postback_queue (id, conversion_id, affiliate_id, source_id, url, status, attempts, next_attempt_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (id) DO NOTHING`
+
+// dequeuePostbacksQuery claims due pending postbacks by moving their next attempt time to the end of the lease.
+// Rows locked by other dispatchers are skipped.
+const dequeuePostbacksQuery = `
+UPDATE postback_queue
+SET next_attempt_at = $1, updated_at = NOW()
+WHERE id IN (
+    SELECT id
+    FROM postback_queue
+    WHERE status = $2 AND next_attempt_at <= $3
+    ORDER BY next_attempt_at
+    LIMIT $4
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, conversion_id, affiliate_id, source_id, url, status, attempts, next_attempt_at, created_at`
+
+// updatePostbackQuery stores the delivery state of the postback
+const updatePostbackQuery = `
+UPDATE postback_queue
+SET status = $2, attempts = $3, next_attempt_at = $4, updated_at = NOW()
+WHERE id = $1`
+
+// insertPostbackAttemptQuery inserts the delivery attempt log entry
+const insertPostbackAttemptQuery = `
+INSERT INTO postback_attempts (postback_id, conversion_id, attempt, url, status_code, error, duration_ms, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+// PostbacksStorage implements repository.PostbacksRepository using Postgres as the underlying storage.
+type PostbacksStorage struct {
+	db *sql.DB
+}
+
+// NewPostbacksStorage creates a new PostbacksStorage instance.
+func NewPostbacksStorage(db *sql.DB) *PostbacksStorage {
+	return &PostbacksStorage{db: db}
+}
+
+// FindPostbackURLTemplate returns the postback URL template of the affiliate traffic source,
+// empty string is returned if neither source nor affiliate template is set.
+func (s *PostbacksStorage) FindPostbackURLTemplate(ctx context.Context, affiliateID, sourceID string) (string, error) {
+	var urlTemplate string
+	err := s.db.QueryRowContext(ctx, findPostbackURLTemplateQuery, affiliateID, sourceID).Scan(&urlTemplate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to execute postback URL query: %w", err)
+	}
+
+	return urlTemplate, nil
+}
+
+// Enqueue adds the postback to the delivery queue.
+func (s *PostbacksStorage) Enqueue(ctx context.Context, postback *entity.Postback) error {
+	_, err := s.db.ExecContext(ctx, enqueuePostbackQuery,
+		postback.ID,
+		postback.ConversionID,
+		postback.AffiliateID,
+		postback.SourceID,
+		postback.URL,
+		postback.Status,
+		postback.Attempts,
+		postback.NextAttemptAt,
+		postback.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert postback: %w", err)
+	}
+
+	return nil
+}
+
+// Dequeue claims up to limit pending postbacks due by now for the lease duration.
+func (s *PostbacksStorage) Dequeue(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	lease time.Duration,
+) ([]*entity.Postback, error) {
+	rows, err := s.db.QueryContext(ctx, dequeuePostbacksQuery,
+		now.Add(lease),
+		valueobject.PendingPostbackStatus,
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute dequeue postbacks query: %w", err)
+	}
+	defer rows.Close()
+
+	postbacks := make([]*entity.Postback, 0, limit)
+	for rows.Next() {
+		postback := new(entity.Postback)
+		if err := rows.Scan(
+			&postback.ID,
+			&postback.ConversionID,
+			&postback.AffiliateID,
+			&postback.SourceID,
+			&postback.URL,
+			&postback.Status,
+			&postback.Attempts,
+			&postback.NextAttemptAt,
+			&postback.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan postback: %w", err)
+		}
+
+		postbacks = append(postbacks, postback)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating postbacks rows: %w", err)
+	}
+
+	return postbacks, nil
+}
+
+// Update stores the delivery status, attempts and the next attempt time of the postback.
+func (s *PostbacksStorage) Update(ctx context.Context, postback *entity.Postback) error {
+	_, err := s.db.ExecContext(ctx, updatePostbackQuery,
+		postback.ID,
+		postback.Status,
+		postback.Attempts,
+		postback.NextAttemptAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update postback: %w", err)
+	}
+
+	return nil
+}
+
+// SaveAttempt stores the delivery attempt log entry.
+func (s *PostbacksStorage) SaveAttempt(ctx context.Context, attempt *entity.PostbackAttempt) error {
+	_, err := s.db.ExecContext(ctx, insertPostbackAttemptQuery,
+		attempt.PostbackID,
+		attempt.ConversionID,
+		attempt.Attempt,
+		attempt.URL,
+		attempt.StatusCode,
+		attempt.Error,
+		attempt.Duration.Milliseconds(),
+		attempt.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert postback attempt: %w", err)
+	}
+
+	return nil
+}
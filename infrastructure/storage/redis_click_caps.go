@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lroman242/redirector/domain/entity"
+	"github.com/lroman242/redirector/domain/valueobject"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// trackingLinkCapsKeyPrefix is used to create Redis keys for tracking link click counters
+	trackingLinkCapsKeyPrefix = "caps:trk:"
+	// campaignCapsKeyPrefix is used to create Redis keys for campaign click counters
+	campaignCapsKeyPrefix = "caps:campaign:"
+
+	// hourlyCounterTTL keeps hourly counters a bit longer than the period they describe
+	hourlyCounterTTL = 2 * time.Hour
+	// dailyCounterTTL keeps daily counters a bit longer than the period they describe
+	dailyCounterTTL = 48 * time.Hour
+)
+
+// reserveClickScript checks all counters against their caps and increments them only if none is reached.
+// KEYS contains counter keys, ARGV contains pairs of cap and TTL (in seconds, 0 means no expiration) for each key.
+var reserveClickScript = redis.NewScript(`
+for i, key in ipairs(KEYS) do
+	local current = tonumber(redis.call('GET', key) or '0')
+	if current >= tonumber(ARGV[i * 2 - 1]) then
+		return 0
+	end
+end
+
+for i, key in ipairs(KEYS) do
+	redis.call('INCR', key)
+	local ttl = tonumber(ARGV[i * 2])
+	if ttl > 0 then
+		redis.call('EXPIRE', key, ttl)
+	end
+end
+
+return 1
+`)
+
+// RedisClickCapsStorage implements repository.ClickCapsRepository using Redis counters.
+type RedisClickCapsStorage struct {
+	client *redis.Client
+}
+
+// NewRedisClickCapsStorage creates a new RedisClickCapsStorage instance.
+func NewRedisClickCapsStorage(client *redis.Client) *RedisClickCapsStorage {
+	return &RedisClickCapsStorage{
+		client: client,
+	}
+}
+
+// Reserve atomically counts the click against the tracking link and campaign caps.
+// The click is not counted and false is returned if any of the caps is already reached.
+func (s *RedisClickCapsStorage) Reserve(
+	ctx context.Context,
+	trackingLink *entity.TrackingLink,
+	at time.Time,
+) (bool, error) {
+	keys := make([]string, 0, 6)
+	args := make([]interface{}, 0, 12)
+
+	keys, args = s.appendCounters(keys, args, trackingLinkCapsKeyPrefix+trackingLink.Slug, trackingLink.ClickCaps, at)
+	if trackingLink.CampaignID != "" {
+		campaignKey := campaignCapsKeyPrefix + trackingLink.CampaignID
+		keys, args = s.appendCounters(keys, args, campaignKey, trackingLink.CampaignClickCaps, at)
+	}
+
+	if len(keys) == 0 {
+		return true, nil
+	}
+
+	reserved, err := reserveClickScript.Run(ctx, s.client, keys, args...).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve click: %w", err)
+	}
+
+	return reserved == 1, nil
+}
+
+// appendCounters adds counter keys and their arguments for each defined cap.
+func (s *RedisClickCapsStorage) appendCounters(
+	keys []string,
+	args []interface{},
+	prefix string,
+	caps valueobject.ClickCaps,
+	at time.Time,
+) ([]string, []interface{}) {
+	at = at.UTC()
+
+	if caps.Hourly > 0 {
+		keys = append(keys, prefix+":h:"+at.Format("2006010215"))
+		args = append(args, caps.Hourly, int64(hourlyCounterTTL.Seconds()))
+	}
+	if caps.Daily > 0 {
+		keys = append(keys, prefix+":d:"+at.Format("20060102"))
+		args = append(args, caps.Daily, int64(dailyCounterTTL.Seconds()))
+	}
+	if caps.Lifetime > 0 {
+		keys = append(keys, prefix+":total")
+		args = append(args, caps.Lifetime, 0)
+	}
+
+	return keys, args
+}
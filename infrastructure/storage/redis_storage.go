@@ -3,9 +3,13 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"sort"
 
 	"github.com/lroman242/redirector/domain/entity"
+	"github.com/lroman242/redirector/domain/valueobject"
 	"github.com/lroman242/redirector/infrastructure/logger"
 	"github.com/redis/go-redis/v9"
 )
@@ -13,6 +17,9 @@ import (
 const (
 	// trackingLinkKeyPrefix is used to create Redis keys for tracking links
 	trackingLinkKeyPrefix = "trk:"
+	// targetingRulesKeyPrefix is used to create Redis keys for tracking link targeting rules,
+	// it doesn't share the tracking links namespace, so no slug might produce a rules key
+	targetingRulesKeyPrefix = "trk_rules:"
 )
 
 // RedisStorage implements repository.TrackingLinksRepositoryInterface using Redis
@@ -51,10 +58,49 @@ func (s *RedisStorage) FindTrackingLink(ctx context.Context, slug string) *entit
 		return nil
 	}
 
+	// Load targeting rules stored separately from the tracking link
+	if err := s.loadTargetingRules(ctx, slug, trkLink); err != nil {
+		slog.Error("failed to load targeting rules",
+			"slug", slug,
+			logger.ErrAttr(err),
+		)
+	}
+
 	return trkLink
 }
 
+// loadTargetingRules loads targeting rules of a tracking link from Redis by the requested slug.
+// Rules embedded into the tracking link data are kept if no separate record exists.
+func (s *RedisStorage) loadTargetingRules(ctx context.Context, slug string, trkLink *entity.TrackingLink) error {
+	data, err := s.client.Get(ctx, s.makeTargetingRulesKey(slug)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get targeting rules: %w", err)
+	}
+
+	rules := make([]*valueobject.TargetingRule, 0)
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("failed to unmarshal targeting rules: %w", err)
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority < rules[j].Priority
+	})
+
+	trkLink.TargetingRules = rules
+
+	return nil
+}
+
 // makeTrackingLinkKey creates a Redis key for a tracking link
 func (s *RedisStorage) makeTrackingLinkKey(slug string) string {
 	return trackingLinkKeyPrefix + slug
 }
+
+// makeTargetingRulesKey creates a Redis key for tracking link targeting rules
+func (s *RedisStorage) makeTargetingRulesKey(slug string) string {
+	return targetingRulesKeyPrefix + slug
+}
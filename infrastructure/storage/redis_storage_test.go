@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedisStorage_makeTargetingRulesKey(t *testing.T) {
+	s := NewRedisStorage(nil)
+
+	// the rules of "abc" must not be stored under the key of the "rules:abc" tracking link
+	if key := s.makeTargetingRulesKey("abc"); strings.HasPrefix(key, trackingLinkKeyPrefix) {
+		t.Errorf("targeting rules key %s shares the tracking links namespace", key)
+	}
+	if s.makeTargetingRulesKey("abc") == s.makeTrackingLinkKey("rules:abc") {
+		t.Error("targeting rules key collides with the tracking link key")
+	}
+}
@@ -20,40 +20,126 @@ const findTrackingLinkBySlugQuery = `SELECT
     t.allowed_geos,
     t.allowed_devices,
     t.allowed_os,
+    t.allowed_languages,
+    t.allowed_regions,
+    t.allowed_cities,
+    t.allowed_ips,
+    t.denied_ips,
     t.campaign_overaged,
     t.campaign_overaged_redirect_rules_id,
     ovr.redirect_type as campaign_overaged_redirect_type,
     ovr.redirect_slug as campaign_overaged_redirect_slug,
     ovr.redirect_url as campaign_overaged_redirect_url,
     ovr.redirect_smart_slug as campaign_overaged_redirect_smart_slug,
+    COALESCE(ovr.sticky_smart_slug, false) as campaign_overaged_sticky_smart_slug,
+    COALESCE(ovr.redirect_method, '') as campaign_overaged_redirect_method,
+    t.click_caps,
+    cc.click_caps as campaign_click_caps,
     t.campaign_active,
     t.campaign_active_redirect_rules_id,
     dr.redirect_type as campaign_disabled_redirect_type,
     dr.redirect_slug as campaign_disabled_redirect_slug,
     dr.redirect_url as campaign_disabled_redirect_url,
     dr.redirect_smart_slug as campaign_disabled_redirect_smart_slug,
+    COALESCE(dr.sticky_smart_slug, false) as campaign_disabled_sticky_smart_slug,
+    COALESCE(dr.redirect_method, '') as campaign_disabled_redirect_method,
     t.campaign_protocol_redirect_rules_id,
     pr.redirect_type as protocol_redirect_type,
     pr.redirect_slug as protocol_redirect_slug,
     pr.redirect_url as protocol_redirect_url,
     pr.redirect_smart_slug as protocol_redirect_smart_slug,
+    COALESCE(pr.sticky_smart_slug, false) as protocol_sticky_smart_slug,
+    COALESCE(pr.redirect_method, '') as protocol_redirect_method,
+    t.allow_bots,
+    t.campaign_bots_redirect_rules_id,
+    botr.redirect_type as bots_redirect_type,
+    botr.redirect_slug as bots_redirect_slug,
+    botr.redirect_url as bots_redirect_url,
+    botr.redirect_smart_slug as bots_redirect_smart_slug,
+    COALESCE(botr.sticky_smart_slug, false) as bots_sticky_smart_slug,
+    COALESCE(botr.redirect_method, '') as bots_redirect_method,
+    t.schedule,
+    t.campaign_schedule_redirect_rules_id,
+    schr.redirect_type as schedule_redirect_type,
+    schr.redirect_slug as schedule_redirect_slug,
+    schr.redirect_url as schedule_redirect_url,
+    schr.redirect_smart_slug as schedule_redirect_smart_slug,
+    COALESCE(schr.sticky_smart_slug, false) as schedule_sticky_smart_slug,
+    COALESCE(schr.redirect_method, '') as schedule_redirect_method,
+    t.handle_duplicate_clicks,
+    t.campaign_duplicate_redirect_rules_id,
+    dupr.redirect_type as duplicate_redirect_type,
+    dupr.redirect_slug as duplicate_redirect_slug,
+    dupr.redirect_url as duplicate_redirect_url,
+    dupr.redirect_smart_slug as duplicate_redirect_smart_slug,
+    COALESCE(dupr.sticky_smart_slug, false) as duplicate_sticky_smart_slug,
+    COALESCE(dupr.redirect_method, '') as duplicate_redirect_method,
     t.campaign_geo_redirect_rules_id,
     gr.redirect_type as geo_redirect_type,
     gr.redirect_slug as geo_redirect_slug,
     gr.redirect_url as geo_redirect_url,
     gr.redirect_smart_slug as geo_redirect_smart_slug,
+    COALESCE(gr.sticky_smart_slug, false) as geo_sticky_smart_slug,
+    COALESCE(gr.redirect_method, '') as geo_redirect_method,
+    t.campaign_region_redirect_rules_id,
+    regr.redirect_type as region_redirect_type,
+    regr.redirect_slug as region_redirect_slug,
+    regr.redirect_url as region_redirect_url,
+    regr.redirect_smart_slug as region_redirect_smart_slug,
+    COALESCE(regr.sticky_smart_slug, false) as region_sticky_smart_slug,
+    COALESCE(regr.redirect_method, '') as region_redirect_method,
+    t.campaign_city_redirect_rules_id,
+    cityr.redirect_type as city_redirect_type,
+    cityr.redirect_slug as city_redirect_slug,
+    cityr.redirect_url as city_redirect_url,
+    cityr.redirect_smart_slug as city_redirect_smart_slug,
+    COALESCE(cityr.sticky_smart_slug, false) as city_sticky_smart_slug,
+    COALESCE(cityr.redirect_method, '') as city_redirect_method,
     t.campaign_devices_redirect_rules_id,
     devr.redirect_type as devices_redirect_type,
     devr.redirect_slug as devices_redirect_slug,
     devr.redirect_url as devices_redirect_url,
     devr.redirect_smart_slug as devices_redirect_smart_slug,
+    COALESCE(devr.sticky_smart_slug, false) as devices_sticky_smart_slug,
+    COALESCE(devr.redirect_method, '') as devices_redirect_method,
     t.campaign_os_redirect_rules_id,
     osr.redirect_type as os_redirect_type,
     osr.redirect_slug as os_redirect_slug,
     osr.redirect_url as os_redirect_url,
     osr.redirect_smart_slug as os_redirect_smart_slug,
+    COALESCE(osr.sticky_smart_slug, false) as os_sticky_smart_slug,
+    COALESCE(osr.redirect_method, '') as os_redirect_method,
+    t.campaign_language_redirect_rules_id,
+    langr.redirect_type as language_redirect_type,
+    langr.redirect_slug as language_redirect_slug,
+    langr.redirect_url as language_redirect_url,
+    langr.redirect_smart_slug as language_redirect_smart_slug,
+    COALESCE(langr.sticky_smart_slug, false) as language_sticky_smart_slug,
+    COALESCE(langr.redirect_method, '') as language_redirect_method,
+    t.campaign_ip_redirect_rules_id,
+    ipr.redirect_type as ip_redirect_type,
+    ipr.redirect_slug as ip_redirect_slug,
+    ipr.redirect_url as ip_redirect_url,
+    ipr.redirect_smart_slug as ip_redirect_smart_slug,
+    COALESCE(ipr.sticky_smart_slug, false) as ip_sticky_smart_slug,
+    COALESCE(ipr.redirect_method, '') as ip_redirect_method,
+    t.campaign_deeplink_redirect_rules_id,
+    deeplr.redirect_type as deeplink_redirect_type,
+    deeplr.redirect_slug as deeplink_redirect_slug,
+    deeplr.redirect_url as deeplink_redirect_url,
+    deeplr.redirect_smart_slug as deeplink_redirect_smart_slug,
+    COALESCE(deeplr.sticky_smart_slug, false) as deeplink_sticky_smart_slug,
+    COALESCE(deeplr.redirect_method, '') as deeplink_redirect_method,
     t.target_url_template,
+    t.redirect_method,
+    t.cloak_referrer,
+    t.interstitial_template,
+    t.allowed_token_keys,
+    t.params_pass_through,
+    t.forwarded_ad_click_ids,
     t.allow_deeplink,
+    t.allowed_deeplink_schemes,
+    t.allowed_deeplink_domains,
     t.campaign_id,
     t.affiliate_id,
     t.advertiser_id,
@@ -62,17 +148,82 @@ FROM tracking_links t
 LEFT JOIN redirect_rules ovr ON ovr.id = t.campaign_overaged_redirect_rules_id
 LEFT JOIN redirect_rules dr ON dr.id = t.campaign_active_redirect_rules_id
 LEFT JOIN redirect_rules pr ON pr.id = t.campaign_protocol_redirect_rules_id
+LEFT JOIN redirect_rules botr ON botr.id = t.campaign_bots_redirect_rules_id
+LEFT JOIN redirect_rules schr ON schr.id = t.campaign_schedule_redirect_rules_id
+LEFT JOIN redirect_rules dupr ON dupr.id = t.campaign_duplicate_redirect_rules_id
 LEFT JOIN redirect_rules gr ON gr.id = t.campaign_geo_redirect_rules_id
+LEFT JOIN redirect_rules regr ON regr.id = t.campaign_region_redirect_rules_id
+LEFT JOIN redirect_rules cityr ON cityr.id = t.campaign_city_redirect_rules_id
 LEFT JOIN redirect_rules devr ON devr.id = t.campaign_devices_redirect_rules_id
 LEFT JOIN redirect_rules osr ON osr.id = t.campaign_os_redirect_rules_id
+LEFT JOIN redirect_rules langr ON langr.id = t.campaign_language_redirect_rules_id
+LEFT JOIN redirect_rules ipr ON ipr.id = t.campaign_ip_redirect_rules_id
+LEFT JOIN redirect_rules deeplr ON deeplr.id = t.campaign_deeplink_redirect_rules_id
+LEFT JOIN campaign_click_caps cc ON cc.campaign_id = t.campaign_id
 WHERE t.slug = $1
 LIMIT 1`
 
 // findLandingPagesBySlugQuery selects landing pages associated with a tracking link
 const findLandingPagesBySlugQuery = `
-SELECT id, title, preview_url, target_url
+SELECT id, title, COALESCE(preview_url, ''), target_url, weight, active
 FROM landing_pages
-WHERE campaign_id = $1`
+WHERE slug = $1`
+
+// findTargetingRulesBySlugQuery selects active targeting rules of a tracking link in evaluation order
+const findTargetingRulesBySlugQuery = `
+SELECT
+    tr.priority,
+    tr.logic,
+    tr.conditions,
+    rr.redirect_type,
+    COALESCE(rr.redirect_slug, ''),
+    COALESCE(rr.redirect_url, ''),
+    rr.redirect_smart_slug,
+    rr.sticky_smart_slug,
+    rr.redirect_method
+FROM targeting_rules tr
+JOIN redirect_rules rr ON rr.id = tr.redirect_rules_id
+WHERE tr.slug = $1 AND tr.active = true
+ORDER BY tr.priority, tr.id`
+
+// findAdvertiserDeeplinkDomainsQuery selects domain patterns deeplinks of the advertiser might point to
+const findAdvertiserDeeplinkDomainsQuery = `
+SELECT domain
+FROM advertiser_deeplink_domains
+WHERE advertiser_id = $1`
+
+// findAdvertiserFallbackURLsQuery selects URLs rejected traffic of the advertiser is sent to, by error class
+const findAdvertiserFallbackURLsQuery = `
+SELECT error_class, url
+FROM advertiser_fallback_urls
+WHERE advertiser_id = $1`
+
+// nullableRedirectRules holds LEFT JOIN-ed redirect rules columns, which are all NULL when the rules are not set.
+type nullableRedirectRules struct {
+	ID                sql.NullInt32
+	RedirectType      sql.NullString
+	RedirectSlug      sql.NullString
+	RedirectURL       sql.NullString
+	RedirectSmartSlug valueobject.SmartSlugs
+	StickySmartSlug   bool
+	RedirectMethod    string
+}
+
+// redirectRules function returns the redirect rules ID and the rules, nil rules are returned if the ID is NULL.
+func (n *nullableRedirectRules) redirectRules() (int32, *valueobject.RedirectRules) {
+	if !n.ID.Valid {
+		return 0, nil
+	}
+
+	return n.ID.Int32, &valueobject.RedirectRules{
+		RedirectType:      n.RedirectType.String,
+		RedirectSlug:      n.RedirectSlug.String,
+		RedirectURL:       n.RedirectURL.String,
+		RedirectSmartSlug: n.RedirectSmartSlug,
+		StickySmartSlug:   n.StickySmartSlug,
+		RedirectMethod:    n.RedirectMethod,
+	}
+}
 
 // SQLStorage implements repository.TrackingLinksRepositoryInterface.
 type SQLStorage struct {
@@ -115,12 +266,20 @@ func (s *SQLStorage) FindTrackingLink(ctx context.Context, slug string) *entity.
 	}
 
 	trkLink := new(entity.TrackingLink)
-	trkLink.CampaignOverageRedirectRules = new(valueobject.RedirectRules)
-	trkLink.CampaignDisabledRedirectRules = new(valueobject.RedirectRules)
-	trkLink.CampaignProtocolRedirectRules = new(valueobject.RedirectRules)
-	trkLink.CampaignGeoRedirectRules = new(valueobject.RedirectRules)
-	trkLink.CampaignDevicesRedirectRules = new(valueobject.RedirectRules)
-	trkLink.CampaignOSRedirectRules = new(valueobject.RedirectRules)
+	botsRules := new(nullableRedirectRules)
+	overageRules := new(nullableRedirectRules)
+	disabledRules := new(nullableRedirectRules)
+	protocolRules := new(nullableRedirectRules)
+	scheduleRules := new(nullableRedirectRules)
+	duplicateRules := new(nullableRedirectRules)
+	geoRules := new(nullableRedirectRules)
+	regionRules := new(nullableRedirectRules)
+	cityRules := new(nullableRedirectRules)
+	devicesRules := new(nullableRedirectRules)
+	osRules := new(nullableRedirectRules)
+	languageRules := new(nullableRedirectRules)
+	ipRules := new(nullableRedirectRules)
+	deeplinkRules := new(nullableRedirectRules)
 
 	err = result.Scan(
 		&trkLink.Slug,
@@ -129,47 +288,141 @@ func (s *SQLStorage) FindTrackingLink(ctx context.Context, slug string) *entity.
 		&trkLink.AllowedGeos,
 		&trkLink.AllowedDevices,
 		&trkLink.AllowedOS,
+		&trkLink.AllowedLanguages,
+		&trkLink.AllowedRegions,
+		&trkLink.AllowedCities,
+		&trkLink.AllowedIPs,
+		&trkLink.DeniedIPs,
 
 		&trkLink.IsCampaignOveraged,
-		&trkLink.CampaignOveragedRedirectRulesID,
-		&trkLink.CampaignOverageRedirectRules.RedirectType,
-		&trkLink.CampaignOverageRedirectRules.RedirectSlug,
-		&trkLink.CampaignOverageRedirectRules.RedirectURL,
-		&trkLink.CampaignOverageRedirectRules.RedirectSmartSlug,
+		&overageRules.ID,
+		&overageRules.RedirectType,
+		&overageRules.RedirectSlug,
+		&overageRules.RedirectURL,
+		&overageRules.RedirectSmartSlug,
+		&overageRules.StickySmartSlug,
+		&overageRules.RedirectMethod,
+		&trkLink.ClickCaps,
+		&trkLink.CampaignClickCaps,
 
 		&trkLink.IsCampaignActive,
-		&trkLink.CampaignActiveRedirectRulesID,
-		&trkLink.CampaignDisabledRedirectRules.RedirectType,
-		&trkLink.CampaignDisabledRedirectRules.RedirectSlug,
-		&trkLink.CampaignDisabledRedirectRules.RedirectURL,
-		&trkLink.CampaignDisabledRedirectRules.RedirectSmartSlug,
-
-		&trkLink.CampaignProtocolRedirectRulesID,
-		&trkLink.CampaignProtocolRedirectRules.RedirectType,
-		&trkLink.CampaignProtocolRedirectRules.RedirectSlug,
-		&trkLink.CampaignProtocolRedirectRules.RedirectURL,
-		&trkLink.CampaignProtocolRedirectRules.RedirectSmartSlug,
-
-		&trkLink.CampaignGeoRedirectRulesID,
-		&trkLink.CampaignGeoRedirectRules.RedirectType,
-		&trkLink.CampaignGeoRedirectRules.RedirectSlug,
-		&trkLink.CampaignGeoRedirectRules.RedirectURL,
-		&trkLink.CampaignGeoRedirectRules.RedirectSmartSlug,
-
-		&trkLink.CampaignDevicesRedirectRulesID,
-		&trkLink.CampaignDevicesRedirectRules.RedirectType,
-		&trkLink.CampaignDevicesRedirectRules.RedirectSlug,
-		&trkLink.CampaignDevicesRedirectRules.RedirectURL,
-		&trkLink.CampaignDevicesRedirectRules.RedirectSmartSlug,
-
-		&trkLink.CampaignOSRedirectRulesID,
-		&trkLink.CampaignOSRedirectRules.RedirectType,
-		&trkLink.CampaignOSRedirectRules.RedirectSlug,
-		&trkLink.CampaignOSRedirectRules.RedirectURL,
-		&trkLink.CampaignOSRedirectRules.RedirectSmartSlug,
+		&disabledRules.ID,
+		&disabledRules.RedirectType,
+		&disabledRules.RedirectSlug,
+		&disabledRules.RedirectURL,
+		&disabledRules.RedirectSmartSlug,
+		&disabledRules.StickySmartSlug,
+		&disabledRules.RedirectMethod,
+
+		&protocolRules.ID,
+		&protocolRules.RedirectType,
+		&protocolRules.RedirectSlug,
+		&protocolRules.RedirectURL,
+		&protocolRules.RedirectSmartSlug,
+		&protocolRules.StickySmartSlug,
+		&protocolRules.RedirectMethod,
+
+		&trkLink.AllowBots,
+		&botsRules.ID,
+		&botsRules.RedirectType,
+		&botsRules.RedirectSlug,
+		&botsRules.RedirectURL,
+		&botsRules.RedirectSmartSlug,
+		&botsRules.StickySmartSlug,
+		&botsRules.RedirectMethod,
+
+		&trkLink.Schedule,
+		&scheduleRules.ID,
+		&scheduleRules.RedirectType,
+		&scheduleRules.RedirectSlug,
+		&scheduleRules.RedirectURL,
+		&scheduleRules.RedirectSmartSlug,
+		&scheduleRules.StickySmartSlug,
+		&scheduleRules.RedirectMethod,
+
+		&trkLink.HandleDuplicateClicks,
+		&duplicateRules.ID,
+		&duplicateRules.RedirectType,
+		&duplicateRules.RedirectSlug,
+		&duplicateRules.RedirectURL,
+		&duplicateRules.RedirectSmartSlug,
+		&duplicateRules.StickySmartSlug,
+		&duplicateRules.RedirectMethod,
+
+		&geoRules.ID,
+		&geoRules.RedirectType,
+		&geoRules.RedirectSlug,
+		&geoRules.RedirectURL,
+		&geoRules.RedirectSmartSlug,
+		&geoRules.StickySmartSlug,
+		&geoRules.RedirectMethod,
+
+		&regionRules.ID,
+		&regionRules.RedirectType,
+		&regionRules.RedirectSlug,
+		&regionRules.RedirectURL,
+		&regionRules.RedirectSmartSlug,
+		&regionRules.StickySmartSlug,
+		&regionRules.RedirectMethod,
+
+		&cityRules.ID,
+		&cityRules.RedirectType,
+		&cityRules.RedirectSlug,
+		&cityRules.RedirectURL,
+		&cityRules.RedirectSmartSlug,
+		&cityRules.StickySmartSlug,
+		&cityRules.RedirectMethod,
+
+		&devicesRules.ID,
+		&devicesRules.RedirectType,
+		&devicesRules.RedirectSlug,
+		&devicesRules.RedirectURL,
+		&devicesRules.RedirectSmartSlug,
+		&devicesRules.StickySmartSlug,
+		&devicesRules.RedirectMethod,
+
+		&osRules.ID,
+		&osRules.RedirectType,
+		&osRules.RedirectSlug,
+		&osRules.RedirectURL,
+		&osRules.RedirectSmartSlug,
+		&osRules.StickySmartSlug,
+		&osRules.RedirectMethod,
+
+		&languageRules.ID,
+		&languageRules.RedirectType,
+		&languageRules.RedirectSlug,
+		&languageRules.RedirectURL,
+		&languageRules.RedirectSmartSlug,
+		&languageRules.StickySmartSlug,
+		&languageRules.RedirectMethod,
+
+		&ipRules.ID,
+		&ipRules.RedirectType,
+		&ipRules.RedirectSlug,
+		&ipRules.RedirectURL,
+		&ipRules.RedirectSmartSlug,
+		&ipRules.StickySmartSlug,
+		&ipRules.RedirectMethod,
+
+		&deeplinkRules.ID,
+		&deeplinkRules.RedirectType,
+		&deeplinkRules.RedirectSlug,
+		&deeplinkRules.RedirectURL,
+		&deeplinkRules.RedirectSmartSlug,
+		&deeplinkRules.StickySmartSlug,
+		&deeplinkRules.RedirectMethod,
 
 		&trkLink.TargetURLTemplate,
+		&trkLink.RedirectMethod,
+		&trkLink.CloakReferrer,
+		&trkLink.InterstitialTemplate,
+		&trkLink.AllowedTokenKeys,
+		&trkLink.ParamsPassThrough,
+		&trkLink.ForwardedAdClickIDs,
 		&trkLink.AllowDeeplink,
+		&trkLink.AllowedDeeplinkSchemes,
+		&trkLink.AllowedDeeplinkDomains,
 		&trkLink.CampaignID,
 		&trkLink.AffiliateID,
 		&trkLink.AdvertiserID,
@@ -181,12 +434,50 @@ func (s *SQLStorage) FindTrackingLink(ctx context.Context, slug string) *entity.
 		return nil
 	}
 
+	// rules which are not set stay nil, so the restriction error is returned instead of the redirect
+	trkLink.CampaignBotsRedirectRulesID, trkLink.CampaignBotsRedirectRules = botsRules.redirectRules()
+	trkLink.CampaignOveragedRedirectRulesID, trkLink.CampaignOverageRedirectRules = overageRules.redirectRules()
+	trkLink.CampaignActiveRedirectRulesID, trkLink.CampaignDisabledRedirectRules = disabledRules.redirectRules()
+	trkLink.CampaignProtocolRedirectRulesID, trkLink.CampaignProtocolRedirectRules = protocolRules.redirectRules()
+	trkLink.CampaignScheduleRedirectRulesID, trkLink.CampaignScheduleRedirectRules = scheduleRules.redirectRules()
+	trkLink.CampaignDuplicateRedirectRulesID, trkLink.CampaignDuplicateRedirectRules = duplicateRules.redirectRules()
+	trkLink.CampaignLanguageRedirectRulesID, trkLink.CampaignLanguageRedirectRules = languageRules.redirectRules()
+	trkLink.CampaignIPRedirectRulesID, trkLink.CampaignIPRedirectRules = ipRules.redirectRules()
+	trkLink.CampaignDeeplinkRedirectRulesID, trkLink.CampaignDeeplinkRedirectRules = deeplinkRules.redirectRules()
+	trkLink.CampaignGeoRedirectRulesID, trkLink.CampaignGeoRedirectRules = geoRules.redirectRules()
+	trkLink.CampaignRegionRedirectRulesID, trkLink.CampaignRegionRedirectRules = regionRules.redirectRules()
+	trkLink.CampaignCityRedirectRulesID, trkLink.CampaignCityRedirectRules = cityRules.redirectRules()
+	trkLink.CampaignDevicesRedirectRulesID, trkLink.CampaignDevicesRedirectRules = devicesRules.redirectRules()
+	trkLink.CampaignOSRedirectRulesID, trkLink.CampaignOSRedirectRules = osRules.redirectRules()
+
 	// Load landing pages
 	if err := s.loadLandingPages(ctx, trkLink); err != nil {
 		slog.Error("an error occurred while loading landing pages", logger.ErrAttr(err))
 		// Don't return nil here - we still want to return the tracking link even if landing pages fail to load
 	}
 
+	// Load targeting rules
+	if err := s.loadTargetingRules(ctx, trkLink); err != nil {
+		slog.Error("an error occurred while loading targeting rules", logger.ErrAttr(err))
+		// Don't return nil here - fixed tracking link restrictions are still applied
+	}
+
+	// Load advertiser deeplink domains
+	if trkLink.AllowDeeplink && trkLink.AdvertiserID != "" {
+		if err := s.loadAdvertiserDeeplinkDomains(ctx, trkLink); err != nil {
+			slog.Error("an error occurred while loading advertiser deeplink domains", logger.ErrAttr(err))
+			// Don't return nil here - tracking link deeplink domains are still applied
+		}
+	}
+
+	// Load advertiser fallback URLs
+	if trkLink.AdvertiserID != "" {
+		if err := s.loadAdvertiserFallbackURLs(ctx, trkLink); err != nil {
+			slog.Error("an error occurred while loading advertiser fallback URLs", logger.ErrAttr(err))
+			// Don't return nil here - global fallback URLs are still applied
+		}
+	}
+
 	return trkLink
 }
 
@@ -198,7 +489,7 @@ func (s *SQLStorage) loadLandingPages(ctx context.Context, trkLink *entity.Track
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.QueryContext(ctx, trkLink.CampaignID)
+	rows, err := stmt.QueryContext(ctx, trkLink.Slug)
 	if err != nil {
 		return fmt.Errorf("failed to execute landing pages query: %w", err)
 	}
@@ -214,6 +505,8 @@ func (s *SQLStorage) loadLandingPages(ctx context.Context, trkLink *entity.Track
 			&landingPage.Title,
 			&landingPage.PreviewURL,
 			&landingPage.TargetURL,
+			&landingPage.Weight,
+			&landingPage.IsActive,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to scan landing page: %w", err)
@@ -228,3 +521,107 @@ func (s *SQLStorage) loadLandingPages(ctx context.Context, trkLink *entity.Track
 
 	return nil
 }
+
+// loadTargetingRules loads targeting rules for a tracking link.
+// Rules which can't be scanned are logged and skipped, no rules are set if the query fails.
+func (s *SQLStorage) loadTargetingRules(ctx context.Context, trkLink *entity.TrackingLink) error {
+	stmt, err := s.DB.PrepareContext(ctx, findTargetingRulesBySlugQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare targeting rules query: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, trkLink.Slug)
+	if err != nil {
+		return fmt.Errorf("failed to execute targeting rules query: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]*valueobject.TargetingRule, 0)
+
+	for rows.Next() {
+		rule := new(valueobject.TargetingRule)
+		rule.Action = new(valueobject.RedirectRules)
+		err := rows.Scan(
+			&rule.Priority,
+			&rule.Logic,
+			&rule.Conditions,
+			&rule.Action.RedirectType,
+			&rule.Action.RedirectSlug,
+			&rule.Action.RedirectURL,
+			&rule.Action.RedirectSmartSlug,
+			&rule.Action.StickySmartSlug,
+			&rule.Action.RedirectMethod,
+		)
+		if err != nil {
+			slog.Error("an error occurred while scanning targeting rule, the rule is skipped",
+				slog.String("slug", trkLink.Slug),
+				logger.ErrAttr(err),
+			)
+
+			continue
+		}
+
+		rules = append(rules, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating targeting rules rows: %w", err)
+	}
+
+	trkLink.TargetingRules = rules
+
+	return nil
+}
+
+// loadAdvertiserDeeplinkDomains loads deeplink domain patterns of the tracking link advertiser
+func (s *SQLStorage) loadAdvertiserDeeplinkDomains(ctx context.Context, trkLink *entity.TrackingLink) error {
+	rows, err := s.DB.QueryContext(ctx, findAdvertiserDeeplinkDomainsQuery, trkLink.AdvertiserID)
+	if err != nil {
+		return fmt.Errorf("failed to execute advertiser deeplink domains query: %w", err)
+	}
+	defer rows.Close()
+
+	trkLink.AdvertiserDeeplinkDomains = make(entity.AllowedListType)
+
+	for rows.Next() {
+		var domain string
+		if err := rows.Scan(&domain); err != nil {
+			return fmt.Errorf("failed to scan advertiser deeplink domain: %w", err)
+		}
+
+		trkLink.AdvertiserDeeplinkDomains[domain] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating advertiser deeplink domains rows: %w", err)
+	}
+
+	return nil
+}
+
+// loadAdvertiserFallbackURLs loads fallback URLs of the tracking link advertiser
+func (s *SQLStorage) loadAdvertiserFallbackURLs(ctx context.Context, trkLink *entity.TrackingLink) error {
+	rows, err := s.DB.QueryContext(ctx, findAdvertiserFallbackURLsQuery, trkLink.AdvertiserID)
+	if err != nil {
+		return fmt.Errorf("failed to execute advertiser fallback URLs query: %w", err)
+	}
+	defer rows.Close()
+
+	trkLink.AdvertiserFallbackURLs = make(map[string]string)
+
+	for rows.Next() {
+		var errorClass, fallbackURL string
+		if err := rows.Scan(&errorClass, &fallbackURL); err != nil {
+			return fmt.Errorf("failed to scan advertiser fallback URL: %w", err)
+		}
+
+		trkLink.AdvertiserFallbackURLs[errorClass] = fallbackURL
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating advertiser fallback URLs rows: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	migrate "github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/lib/pq"
+)
+
+// postgresTestDSNEnv is the environment variable with DSN of the PostgreSQL database used by the tests
+// running queries against the real schema, the tests are skipped when it is not set.
+const postgresTestDSNEnv = "TEST_POSTGRES_DSN"
+
+// postgresTestSlug is the slug of the tracking link created by the tests.
+const postgresTestSlug = "storage-test"
+
+// openPostgresTestDB function connects to the test database and migrates it to the latest schema.
+func openPostgresTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv(postgresTestDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", postgresTestDSNEnv)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		t.Fatalf("failed to create postgres driver: %v", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://../../migrations", "postgres", driver)
+	if err != nil {
+		t.Fatalf("failed to create migration instance: %v", err)
+	}
+
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	return db
+}
+
+// execPostgresTestQueries function executes fixture queries, the test fails on the first error.
+func execPostgresTestQueries(t *testing.T, db *sql.DB, queries ...string) {
+	t.Helper()
+
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("failed to execute %q: %v", query, err)
+		}
+	}
+}
+
+func TestSQLStorage_FindTrackingLink_Postgres(t *testing.T) {
+	db := openPostgresTestDB(t)
+
+	cleanup := func() {
+		execPostgresTestQueries(t, db,
+			`DELETE FROM landing_pages WHERE slug = '`+postgresTestSlug+`'`,
+			`DELETE FROM tracking_links WHERE slug = '`+postgresTestSlug+`'`,
+			`DELETE FROM campaign_click_caps WHERE campaign_id = 'storage-test-campaign'`,
+			`DELETE FROM redirect_rules WHERE redirect_url = 'https://storage-test.example.com'`,
+		)
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	execPostgresTestQueries(t, db,
+		`INSERT INTO redirect_rules (redirect_type, redirect_url) VALUES ('link', 'https://storage-test.example.com')`,
+		`INSERT INTO tracking_links (
+			slug, campaign_id, affiliate_id, advertiser_id, source_id,
+			allowed_protocols, allowed_geos, allowed_devices,
+			campaign_overaged_redirect_rules_id, campaign_active_redirect_rules_id,
+			campaign_active, click_caps, target_url_template
+		)
+		SELECT '`+postgresTestSlug+`', 'storage-test-campaign', 'aff', 'adv', 'src',
+			'{}', '{}', '{}', id, id, true, '{"hourly": 10}', 'https://example.com/{slug}'
+		FROM redirect_rules
+		WHERE redirect_url = 'https://storage-test.example.com'`,
+		`INSERT INTO campaign_click_caps (campaign_id, click_caps) VALUES ('storage-test-campaign', '{"daily": 100}')`,
+		`INSERT INTO landing_pages (id, slug, title, target_url, weight, active)
+		VALUES ('storage-test-landing', '`+postgresTestSlug+`', 'Landing', 'https://landing.example.com', 3, true)`,
+	)
+
+	trkLink := NewSQLStorage(db).FindTrackingLink(context.Background(), postgresTestSlug)
+	if trkLink == nil {
+		t.Fatal("expected tracking link to be found")
+	}
+
+	if trkLink.ClickCaps.Hourly != 10 {
+		t.Errorf("unexpected click caps %+v", trkLink.ClickCaps)
+	}
+	if trkLink.CampaignClickCaps.Daily != 100 {
+		t.Errorf("unexpected campaign click caps %+v", trkLink.CampaignClickCaps)
+	}
+
+	landingPage, ok := trkLink.LandingPages["storage-test-landing"]
+	if !ok || len(trkLink.LandingPages) != 1 {
+		t.Fatalf("expected landing page of the tracking link to be loaded, got %v", trkLink.LandingPages)
+	}
+	if landingPage.TargetURL != "https://landing.example.com" || landingPage.PreviewURL != "" ||
+		landingPage.Weight != 3 || !landingPage.IsActive {
+		t.Errorf("unexpected landing page %+v", landingPage)
+	}
+}
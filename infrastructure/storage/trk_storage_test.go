@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+// fakeConnector returns the tracking link row for the tracking link query, targeting rules rows
+// for the targeting rules query and no rows for other queries.
+type fakeConnector struct {
+	row            []driver.Value
+	targetingRules [][]driver.Value
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn(c), nil }
+func (c fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct {
+	row            []driver.Value
+	targetingRules [][]driver.Value
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{conn: c, query: query}, nil
+}
+func (c fakeConn) Close() error              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) { return nil, driver.ErrSkip }
+
+type fakeStmt struct {
+	conn  fakeConn
+	query string
+}
+
+func (s fakeStmt) Close() error                               { return nil }
+func (s fakeStmt) NumInput() int                              { return -1 }
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	switch s.query {
+	case findTrackingLinkBySlugQuery:
+		return newFakeRows([][]driver.Value{s.conn.row}), nil
+	case findTargetingRulesBySlugQuery:
+		return newFakeRows(s.conn.targetingRules), nil
+	default:
+		return &fakeRows{}, nil
+	}
+}
+
+// newFakeRows function creates the result set with as many columns as the first row has.
+func newFakeRows(rows [][]driver.Value) *fakeRows {
+	if len(rows) == 0 {
+		return &fakeRows{}
+	}
+
+	return &fakeRows{columns: make([]string, len(rows[0])), rows: rows}
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
+
+// trackingLinkValues lists values of the tracking link columns, other columns are empty JSON objects.
+var trackingLinkValues = map[string]driver.Value{
+	"slug":                    "slug",
+	"active":                  true,
+	"campaign_overaged":       false,
+	"campaign_active":         true,
+	"allow_bots":              true,
+	"handle_duplicate_clicks": false,
+	"target_url_template":     "https://target.com",
+	"allow_deeplink":          false,
+	"campaign_id":             "campaign",
+	"affiliate_id":            "affiliate",
+	"advertiser_id":           "advertiser",
+	"source_id":               "source",
+	"allowed_ips":             []byte("[]"),
+	"denied_ips":              []byte("[]"),
+	"redirect_method":         "",
+	"cloak_referrer":          false,
+}
+
+// trackingLinkRow function builds the tracking link query row from the query columns.
+// Redirect rules are named after the rules ID column ("bots" for campaign_bots_redirect_rules_id),
+// rules listed in nullRules are not set.
+func trackingLinkRow(nullRules map[string]bool) []driver.Value {
+	columns := strings.Split(findTrackingLinkBySlugQuery, "\n")
+	columns = columns[1:slices.IndexFunc(columns, func(line string) bool { return strings.HasPrefix(line, "FROM ") })]
+
+	var row []driver.Value
+	rules, ruleID := "", int64(0)
+	for _, column := range columns {
+		column = strings.TrimSuffix(strings.TrimSpace(column), ",")
+		name := column[strings.LastIndexAny(column, ". ")+1:]
+
+		switch {
+		case strings.HasSuffix(name, "_redirect_rules_id"):
+			rules = strings.TrimSuffix(strings.TrimPrefix(name, "campaign_"), "_redirect_rules_id")
+			ruleID++
+			row = append(row, ruleID)
+		case strings.HasPrefix(column, "t."):
+			rules = ""
+			value, ok := trackingLinkValues[name]
+			if !ok {
+				value = []byte("{}")
+			}
+			row = append(row, value)
+		case rules == "":
+			row = append(row, nil)
+		case strings.HasSuffix(name, "_sticky_smart_slug"):
+			row = append(row, false)
+		case strings.HasSuffix(name, "_redirect_type"):
+			row = append(row, valueobject.LinkRedirectType)
+		case strings.HasSuffix(name, "_redirect_url"):
+			row = append(row, "https://"+rules+".com")
+		case strings.HasSuffix(name, "_redirect_slug"), strings.HasSuffix(name, "_redirect_method"):
+			row = append(row, "")
+		default:
+			row = append(row, nil)
+		}
+
+		if rules != "" && nullRules[rules] && !strings.HasPrefix(column, "COALESCE") {
+			row[len(row)-1] = nil
+		}
+	}
+
+	return row
+}
+
+func TestSQLStorage_FindTrackingLink_RedirectRules(t *testing.T) {
+	nullRules := map[string]bool{
+		"overaged": true, "active": true, "protocol": true, "bots": true, "schedule": true,
+		"duplicate": true, "geo": true, "region": true, "city": true, "devices": true,
+		"os": true, "language": true, "ip": true, "deeplink": true,
+	}
+
+	t.Run("rules are not set", func(t *testing.T) {
+		db := sql.OpenDB(fakeConnector{row: trackingLinkRow(nullRules)})
+		defer db.Close()
+
+		trkLink := NewSQLStorage(db).FindTrackingLink(context.Background(), "slug")
+		if trkLink == nil {
+			t.Fatal("expected tracking link to be loaded")
+		}
+
+		if trkLink.CampaignBotsRedirectRules != nil || trkLink.CampaignBotsRedirectRulesID != 0 {
+			t.Errorf("unexpected bots redirect rules %d %v", trkLink.CampaignBotsRedirectRulesID, trkLink.CampaignBotsRedirectRules)
+		}
+		if trkLink.CampaignScheduleRedirectRules != nil || trkLink.CampaignScheduleRedirectRulesID != 0 {
+			t.Errorf("unexpected schedule redirect rules %d %v", trkLink.CampaignScheduleRedirectRulesID, trkLink.CampaignScheduleRedirectRules)
+		}
+		if trkLink.CampaignDuplicateRedirectRules != nil || trkLink.CampaignDuplicateRedirectRulesID != 0 {
+			t.Errorf("unexpected duplicate redirect rules %d %v", trkLink.CampaignDuplicateRedirectRulesID, trkLink.CampaignDuplicateRedirectRules)
+		}
+		if trkLink.CampaignLanguageRedirectRules != nil || trkLink.CampaignLanguageRedirectRulesID != 0 {
+			t.Errorf("unexpected language redirect rules %d %v", trkLink.CampaignLanguageRedirectRulesID, trkLink.CampaignLanguageRedirectRules)
+		}
+		if trkLink.CampaignRegionRedirectRules != nil || trkLink.CampaignRegionRedirectRulesID != 0 {
+			t.Errorf("unexpected region redirect rules %d %v", trkLink.CampaignRegionRedirectRulesID, trkLink.CampaignRegionRedirectRules)
+		}
+		if trkLink.CampaignCityRedirectRules != nil || trkLink.CampaignCityRedirectRulesID != 0 {
+			t.Errorf("unexpected city redirect rules %d %v", trkLink.CampaignCityRedirectRulesID, trkLink.CampaignCityRedirectRules)
+		}
+		if trkLink.CampaignIPRedirectRules != nil || trkLink.CampaignIPRedirectRulesID != 0 {
+			t.Errorf("unexpected ip redirect rules %d %v", trkLink.CampaignIPRedirectRulesID, trkLink.CampaignIPRedirectRules)
+		}
+		if trkLink.CampaignDeeplinkRedirectRules != nil || trkLink.CampaignDeeplinkRedirectRulesID != 0 {
+			t.Errorf("unexpected deeplink redirect rules %d %v", trkLink.CampaignDeeplinkRedirectRulesID, trkLink.CampaignDeeplinkRedirectRules)
+		}
+		if trkLink.CampaignGeoRedirectRules != nil || trkLink.CampaignOverageRedirectRules != nil {
+			t.Error("unexpected redirect rules")
+		}
+		if trkLink.TargetURLTemplate != "https://target.com" || trkLink.SourceID != "source" {
+			t.Errorf("unexpected tracking link %+v", trkLink)
+		}
+	})
+
+	t.Run("rules are set", func(t *testing.T) {
+		db := sql.OpenDB(fakeConnector{row: trackingLinkRow(nil)})
+		defer db.Close()
+
+		trkLink := NewSQLStorage(db).FindTrackingLink(context.Background(), "slug")
+		if trkLink == nil {
+			t.Fatal("expected tracking link to be loaded")
+		}
+
+		rr := trkLink.CampaignBotsRedirectRules
+		if rr == nil || trkLink.CampaignBotsRedirectRulesID != 4 {
+			t.Fatalf("expected bots redirect rules, got %d %v", trkLink.CampaignBotsRedirectRulesID, rr)
+		}
+		if rr.RedirectType != valueobject.LinkRedirectType || rr.RedirectURL != "https://bots.com" || rr.RedirectSlug != "" {
+			t.Errorf("unexpected bots redirect rules %+v", rr)
+		}
+	})
+}
+
+func TestSQLStorage_FindTrackingLink_TargetingRules(t *testing.T) {
+	targetingRule := func(priority int64, conditions string) []driver.Value {
+		return []driver.Value{
+			priority, valueobject.AndLogic, []byte(conditions),
+			valueobject.LinkRedirectType, "", "https://rule.com", nil, false, "",
+		}
+	}
+
+	db := sql.OpenDB(fakeConnector{
+		row: trackingLinkRow(nil),
+		targetingRules: [][]driver.Value{
+			targetingRule(1, `[{"field":"country","operator":"in","values":["US"]}]`),
+			targetingRule(2, `[{"field":"country"`),
+			targetingRule(3, `[{"field":"time","operator":"in","key":"Mars/Olympus","values":["09:00-17:00"]}]`),
+		},
+	})
+	defer db.Close()
+
+	trkLink := NewSQLStorage(db).FindTrackingLink(context.Background(), "slug")
+	if trkLink == nil {
+		t.Fatal("expected tracking link to be loaded")
+	}
+
+	if len(trkLink.TargetingRules) != 2 {
+		t.Fatalf("expected invalid targeting rule to be skipped, got %d rules", len(trkLink.TargetingRules))
+	}
+	if trkLink.TargetingRules[0].Priority != 1 || trkLink.TargetingRules[1].Priority != 3 {
+		t.Errorf("unexpected targeting rules %+v %+v", trkLink.TargetingRules[0], trkLink.TargetingRules[1])
+	}
+	if trkLink.TargetingRules[1].Matches(&valueobject.TargetingAttributes{Time: time.Now()}) {
+		t.Error("expected time window in unknown timezone to never match")
+	}
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// hopPath is the path of the self-hosted intermediate page used by referrer cloaking.
+	hopPath = "/hop"
+	// defaultHopTTL is the hop URL lifetime used when it is not configured.
+	defaultHopTTL = time.Minute
+)
+
+// cloakingTemplate renders the page which sends the visitor to the next hop with meta refresh without the referrer.
+var cloakingTemplate = template.Must(template.New("cloaking").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="referrer" content="no-referrer">
+<meta http-equiv="refresh" content="0;url={{.}}">
+<title>Redirecting...</title>
+</head>
+<body></body>
+</html>
+`))
+
+// ReferrerCloaker hides the source site from the target by sending the visitor through
+// two meta refresh pages with "no-referrer" policy, the second one is served by the hop endpoint.
+// Hop URLs are signed, so the hop endpoint can't be used as an open redirect.
+type ReferrerCloaker struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewReferrerCloaker creates a new ReferrerCloaker instance.
+// A random secret is generated when empty, hop URLs are valid for the current process only in that case.
+func NewReferrerCloaker(secret string, ttl time.Duration) *ReferrerCloaker {
+	key := []byte(secret)
+	if len(key) == 0 {
+		slog.Warn("referrer cloaking secret is not set, hop URLs are signed with a random key")
+		key = make([]byte, sha256.Size)
+		_, _ = rand.Read(key)
+	}
+
+	if ttl <= 0 {
+		ttl = defaultHopTTL
+	}
+
+	return &ReferrerCloaker{secret: key, ttl: ttl}
+}
+
+// HopURL returns the signed URL of the intermediate page which redirects the visitor to the target URL.
+func (c *ReferrerCloaker) HopURL(targetURL string, now time.Time) string {
+	expires := strconv.FormatInt(now.Add(c.ttl).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("u", targetURL)
+	query.Set("e", expires)
+	query.Set("s", c.sign(targetURL, expires))
+
+	return hopPath + "?" + query.Encode()
+}
+
+// verify checks the hop URL signature and expiration time.
+func (c *ReferrerCloaker) verify(targetURL, expires, signature string, now time.Time) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > expiresAt {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(c.sign(targetURL, expires)))
+}
+
+// sign returns the signature of the target URL and expiration time.
+func (c *ReferrerCloaker) sign(targetURL, expires string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(expires + "|" + targetURL))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// writeCloakedRedirect renders the first page of the cloaked redirect which leads to the hop endpoint.
+func (c *ReferrerCloaker) writeCloakedRedirect(w http.ResponseWriter, targetURL string) {
+	writeCloakingPage(w, c.HopURL(targetURL, time.Now()))
+}
+
+// ServeHTTP handles hop requests and renders the second page of the cloaked redirect which leads to the target.
+func (c *ReferrerCloaker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	targetURL := query.Get("u")
+	if targetURL == "" || !c.verify(targetURL, query.Get("e"), query.Get("s"), time.Now()) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	writeCloakingPage(w, targetURL)
+}
+
+// writeCloakingPage renders the meta refresh page with strict referrer policy.
+func writeCloakingPage(w http.ResponseWriter, nextURL string) {
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	writeRedirectPage(w, cloakingTemplate, nextURL)
+}
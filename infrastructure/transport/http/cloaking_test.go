@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReferrerCloaker_HopURL(t *testing.T) {
+	const targetURL = "https://advertiser.com/offer?click_id=1&sub=2"
+
+	cloaker := NewReferrerCloaker("secret", time.Minute)
+	hopURL, err := url.Parse(cloaker.HopURL(targetURL, time.Now()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		query        func(url.Values)
+		cloaker      *ReferrerCloaker
+		expectedCode int
+	}{
+		{name: "valid", query: func(url.Values) {}, cloaker: cloaker, expectedCode: http.StatusOK},
+		{name: "tampered target", query: func(q url.Values) { q.Set("u", "https://evil.com") }, cloaker: cloaker, expectedCode: http.StatusForbidden},
+		{name: "tampered expiration", query: func(q url.Values) { q.Set("e", "9999999999") }, cloaker: cloaker, expectedCode: http.StatusForbidden},
+		{name: "missing signature", query: func(q url.Values) { q.Del("s") }, cloaker: cloaker, expectedCode: http.StatusForbidden},
+		{name: "another secret", query: func(url.Values) {}, cloaker: NewReferrerCloaker("another", time.Minute), expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := hopURL.Query()
+			tt.query(query)
+
+			req := httptest.NewRequest(http.MethodGet, hopPath+"?"+query.Encode(), nil)
+			rec := httptest.NewRecorder()
+			tt.cloaker.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("unexpected status code. expected %d got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			if policy := rec.Header().Get("Referrer-Policy"); policy != "no-referrer" {
+				t.Errorf("unexpected referrer policy %q", policy)
+			}
+			if expected := `content="0;url=https://advertiser.com/offer?click_id=1&amp;sub=2"`; !strings.Contains(rec.Body.String(), expected) {
+				t.Errorf("expected body to contain %s, got %s", expected, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestReferrerCloaker_ExpiredHopURL(t *testing.T) {
+	cloaker := NewReferrerCloaker("secret", time.Minute)
+	hopURL := cloaker.HopURL("https://advertiser.com", time.Now().Add(-2*time.Minute))
+
+	rec := httptest.NewRecorder()
+	cloaker.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, hopURL, nil))
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("unexpected status code. expected %d got %d", http.StatusForbidden, rec.Code)
+	}
+}
+
+func TestReferrerCloaker_WriteCloakedRedirect(t *testing.T) {
+	cloaker := NewReferrerCloaker("secret", time.Minute)
+
+	rec := httptest.NewRecorder()
+	cloaker.writeCloakedRedirect(rec, "https://advertiser.com")
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("unexpected status code. expected %d got %d", http.StatusOK, rec.Code)
+	}
+	if policy := rec.Header().Get("Referrer-Policy"); policy != "no-referrer" {
+		t.Errorf("unexpected referrer policy %q", policy)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `content="0;url=/hop?`) || strings.Contains(body, "url=https://advertiser.com") {
+		t.Errorf("expected the page to lead to the hop endpoint, got %s", body)
+	}
+}
@@ -16,14 +16,55 @@ import (
 	uuid "github.com/satori/go.uuid"
 )
 
+const (
+	// visitorCookieName is the name of the cookie which keeps the visitor identifier.
+	visitorCookieName = "vid"
+	// visitorCookieMaxAge is the visitor cookie lifetime (1 year).
+	visitorCookieMaxAge = 365 * 24 * 60 * 60
+)
+
 // RedirectHandler handles HTTP redirect requests by delegating to a RedirectInteractor.
 type RedirectHandler struct {
-	interactor interactor.RedirectInteractor
+	interactor          interactor.RedirectInteractor
+	cloaker             *ReferrerCloaker
+	interstitials       *InterstitialTemplates
+	trustForwardedProto bool
 }
 
 // NewRedirectHandler creates a new RedirectHandler instance.
-func NewRedirectHandler(interactor interactor.RedirectInteractor) *RedirectHandler {
-	return &RedirectHandler{interactor: interactor}
+// Cloaked redirects are sent through the cloaker hop page, interstitial pages are rendered with provided templates.
+// Both cloaker and interstitials are optional.
+// The request protocol is read from X-Forwarded-Proto header only if trustForwardedProto is set.
+func NewRedirectHandler(
+	interactor interactor.RedirectInteractor,
+	cloaker *ReferrerCloaker,
+	interstitials *InterstitialTemplates,
+	trustForwardedProto bool,
+) *RedirectHandler {
+	return &RedirectHandler{
+		interactor:          interactor,
+		cloaker:             cloaker,
+		interstitials:       interstitials,
+		trustForwardedProto: trustForwardedProto,
+	}
+}
+
+// errorStatusCodes maps redirect error classes to HTTP status codes, unknown errors are internal server errors.
+var errorStatusCodes = map[string]int{
+	interactor.NotFoundErrorClass:      http.StatusNotFound,
+	interactor.DisabledErrorClass:      http.StatusGone,
+	interactor.BlockedErrorClass:       http.StatusForbidden,
+	interactor.GeoErrorClass:           http.StatusForbidden,
+	interactor.MisconfiguredErrorClass: http.StatusInternalServerError,
+}
+
+// errorStatusCode returns the HTTP status code for the redirect error.
+func errorStatusCode(err error) int {
+	if code, ok := errorStatusCodes[interactor.ErrorClass(err)]; ok {
+		return code
+	}
+
+	return http.StatusInternalServerError
 }
 
 // getIPAddress extracts the real client IP address from request headers.
@@ -72,6 +113,82 @@ func getIPAddress(r *http.Request) (net.IP, error) {
 	return userIP, nil
 }
 
+// getProtocol returns the scheme (http/https) used by the client.
+// If trustForwardedProto is set, it checks X-Forwarded-Proto header set by proxies and load balancers first,
+// otherwise the header can be spoofed by the client and only the TLS state of the connection is used.
+func getProtocol(r *http.Request, trustForwardedProto bool) string {
+	if forwardedProto := r.Header.Get("X-Forwarded-Proto"); trustForwardedProto && forwardedProto != "" {
+		// X-Forwarded-Proto can contain multiple values, take the first one
+		return strings.ToLower(strings.TrimSpace(strings.Split(forwardedProto, ",")[0]))
+	}
+
+	if r.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+// getVisitorID returns the visitor identifier stored in the visitor cookie.
+// If the cookie is missing, empty string is returned, so the visitor is identified by IP address and User-Agent,
+// and the cookie with a new identifier is added to the response to recognize the visitor next time.
+func getVisitorID(w http.ResponseWriter, r *http.Request) string {
+	if cookie, err := r.Cookie(visitorCookieName); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     visitorCookieName,
+		Value:    uuid.NewV4().String(),
+		Path:     "/",
+		MaxAge:   visitorCookieMaxAge,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return ""
+}
+
+// getCookies function returns request cookies as a map, the first cookie wins if the name is repeated.
+func getCookies(r *http.Request) map[string]string {
+	cookies := make(map[string]string)
+	for _, cookie := range r.Cookies() {
+		if _, exists := cookies[cookie.Name]; !exists {
+			cookies[cookie.Name] = cookie.Value
+		}
+	}
+
+	return cookies
+}
+
+// writeResult sends the visitor to the target URL. The interstitial page is rendered first if it is set,
+// cloaked redirects hide the referrer behind two meta refresh pages.
+func (rh *RedirectHandler) writeResult(w http.ResponseWriter, r *http.Request, result *dto.RedirectResult) {
+	if result.Interstitial != nil && rh.interstitials != nil {
+		nextURL := result.TargetURL
+		if result.Cloaked && rh.cloaker != nil {
+			nextURL = rh.cloaker.HopURL(nextURL, time.Now())
+		}
+
+		page, err := rh.interstitials.render(result.Interstitial, nextURL)
+		if err == nil {
+			writePageHeader(w)
+			_, _ = w.Write(page)
+			return
+		}
+
+		// the visitor is redirected without the interstitial page rather than lost
+		slog.Error("Failed to render interstitial page", slog.String("error", err.Error()))
+	}
+
+	if result.Cloaked && rh.cloaker != nil {
+		rh.cloaker.writeCloakedRedirect(w, result.TargetURL)
+		return
+	}
+
+	writeRedirect(w, r, result)
+}
+
 // ServeHTTP handles HTTP redirect requests.
 // It extracts request parameters, calls the redirect interactor,
 // and performs the redirect while tracking metrics.
@@ -102,12 +219,14 @@ func (rh *RedirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Slug:      slug,
 		Params:    r.URL.Query(),
 		Headers:   r.Header,
+		Cookies:   getCookies(r),
 		UserAgent: r.UserAgent(),
 		IP:        userIP,
-		Protocol:  r.Proto,
+		Protocol:  getProtocol(r, rh.trustForwardedProto),
 		Referer:   r.Referer(),
 		URL:       r.URL,
 		RequestID: uuid.NewV4().String(),
+		VisitorID: getVisitorID(w, r),
 	}
 
 	slog.Debug("Redirect request", slog.String("slug", slug), "data", data)
@@ -116,7 +235,9 @@ func (rh *RedirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	redirectResult, err := rh.interactor.Redirect(r.Context(), slug, data)
 	if err != nil {
 		slog.Error("Redirect failed", slog.String("error", err.Error()), slog.String("slug", slug), slog.Any("request", data))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		// internal error messages are logged only, visitors see the status text
+		code := errorStatusCode(err)
+		http.Error(w, http.StatusText(code), code)
 		return
 	}
 
@@ -127,33 +248,31 @@ func (rh *RedirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	metrics.RedirectsBySlug.WithLabelValues(slug).Inc()
 
 	// Perform redirect
-	http.Redirect(w, r, redirectResult.TargetURL, http.StatusSeeOther)
+	rh.writeResult(w, r, redirectResult)
 
-	// Process click results asynchronously
+	// Process click results asynchronously, the results are drained even after the request is finished,
+	// so click handlers are never blocked on sending them
 	go func() {
-		for {
-			select {
-			case <-r.Context().Done():
-				return
-			case result, ok := <-redirectResult.OutputCh:
-				slog.Debug("redirect result", slog.String("slug", slug), "result", result, slog.Bool("isClosed", !ok))
-				if !ok {
-					metrics.ClicksProcessed.WithLabelValues("canceled").Inc()
-					slog.Debug("Click processing complete", slog.String("slug", slug))
-					return
-				}
-
-				if result.Err != nil {
-					metrics.ClicksProcessed.WithLabelValues("error").Inc()
-					slog.Error("Click processing failed",
-						slog.String("error", result.Err.Error()),
-						slog.String("slug", slug),
-						slog.Any("request", data),
-					)
-				} else {
-					metrics.ClicksProcessed.WithLabelValues("success").Inc()
-				}
+		if redirectResult.OutputCh == nil {
+			return
+		}
+
+		for result := range redirectResult.OutputCh {
+			slog.Debug("redirect result", slog.String("slug", slug), "result", result)
+
+			if result.Err != nil {
+				metrics.ClicksProcessed.WithLabelValues("error").Inc()
+				slog.Error("Click processing failed",
+					slog.String("error", result.Err.Error()),
+					slog.String("slug", slug),
+					slog.Any("request", data),
+				)
+			} else {
+				metrics.ClicksProcessed.WithLabelValues("success").Inc()
 			}
 		}
+
+		metrics.ClicksProcessed.WithLabelValues("canceled").Inc()
+		slog.Debug("Click processing complete", slog.String("slug", slug))
 	}()
 }
@@ -1,9 +1,15 @@
 package http
 
 import (
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/lroman242/redirector/domain/interactor"
 )
 
 func TestGetIPAddress(t *testing.T) {
@@ -84,3 +90,111 @@ func TestGetIPAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestGetVisitorID(t *testing.T) {
+	t.Run("existing cookie", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/r/slug", nil)
+		req.AddCookie(&http.Cookie{Name: visitorCookieName, Value: "visitor-123"})
+		rec := httptest.NewRecorder()
+
+		if visitorID := getVisitorID(rec, req); visitorID != "visitor-123" {
+			t.Errorf("unexpected visitor id. expected visitor-123 got %s", visitorID)
+		}
+		if len(rec.Result().Cookies()) != 0 {
+			t.Error("unexpected cookie set on response")
+		}
+	})
+
+	t.Run("missing cookie", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/r/slug", nil)
+		rec := httptest.NewRecorder()
+
+		if visitorID := getVisitorID(rec, req); visitorID != "" {
+			t.Errorf("unexpected visitor id %s", visitorID)
+		}
+
+		cookies := rec.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != visitorCookieName || cookies[0].Value == "" {
+			t.Errorf("expected visitor cookie to be set, got %v", cookies)
+		}
+	})
+}
+
+func TestGetProtocol(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		tls      bool
+		trusted  bool
+		expected string
+	}{
+		{
+			name:     "plain http",
+			expected: "http",
+		},
+		{
+			name:     "tls connection",
+			tls:      true,
+			expected: "https",
+		},
+		{
+			name:     "X-Forwarded-Proto header",
+			headers:  map[string]string{"X-Forwarded-Proto": "HTTPS"},
+			trusted:  true,
+			expected: "https",
+		},
+		{
+			name:     "X-Forwarded-Proto header with multiple values",
+			headers:  map[string]string{"X-Forwarded-Proto": "http, https"},
+			tls:      true,
+			trusted:  true,
+			expected: "http",
+		},
+		{
+			name:     "untrusted X-Forwarded-Proto header",
+			headers:  map[string]string{"X-Forwarded-Proto": "https"},
+			expected: "http",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/r/slug", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			if protocol := getProtocol(req, tt.trusted); protocol != tt.expected {
+				t.Errorf("unexpected protocol. expected %s got %s", tt.expected, protocol)
+			}
+		})
+	}
+}
+
+func TestErrorStatusCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "not found", err: interactor.ErrTrackingLinkNotFound, expected: http.StatusNotFound},
+		{name: "disabled", err: interactor.ErrTrackingLinkDisabled, expected: http.StatusGone},
+		{name: "blocked", err: interactor.ErrBlockRedirect, expected: http.StatusForbidden},
+		{name: "geo", err: interactor.ErrUnsupportedGeo, expected: http.StatusForbidden},
+		{name: "wrapped", err: fmt.Errorf("%w: scheme is forbidden", interactor.ErrDeeplinkNotAllowed), expected: http.StatusForbidden},
+		{name: "misconfigured", err: interactor.ErrInvalidRedirectRules, expected: http.StatusInternalServerError},
+		{name: "redirect loop", err: interactor.ErrRedirectLoop, expected: http.StatusInternalServerError},
+		{name: "unknown", err: errors.New("db is down"), expected: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := errorStatusCode(tt.err); code != tt.expected {
+				t.Errorf("unexpected status code. expected %d got %d", tt.expected, code)
+			}
+		})
+	}
+}
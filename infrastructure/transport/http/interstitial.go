@@ -0,0 +1,110 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/lroman242/redirector/domain/dto"
+	"github.com/lroman242/redirector/infrastructure/logger"
+)
+
+// interstitialTemplateExt is the extension of interstitial page template files.
+const interstitialTemplateExt = ".html"
+
+// interstitialData is passed to interstitial page templates.
+// Templates use {{.TargetURL}} to continue the redirect and {{.Token "country_code"}} to access request tokens.
+type interstitialData struct {
+	// TargetURL is the URL the visitor continues to
+	TargetURL string
+
+	interstitial *dto.Interstitial
+}
+
+// Token resolves the token expression ("country_code", "p1|default:none") for the request.
+func (d interstitialData) Token(expr string) string {
+	return d.interstitial.Token(expr)
+}
+
+// InterstitialTemplates keeps interstitial page templates loaded from the directory.
+// Each "<name>.html" file defines the template selected by tracking links with "<name>".
+type InterstitialTemplates struct {
+	dir       string
+	templates atomic.Pointer[map[string]*template.Template]
+}
+
+// NewInterstitialTemplates creates a new InterstitialTemplates instance, templates are empty until Load is called.
+func NewInterstitialTemplates(dir string) *InterstitialTemplates {
+	t := &InterstitialTemplates{dir: dir}
+	t.templates.Store(&map[string]*template.Template{})
+
+	return t
+}
+
+// Load function parses all templates of the directory and replaces the templates in memory.
+// Previously loaded templates are kept if any of the files fails to parse.
+func (t *InterstitialTemplates) Load() error {
+	files, err := filepath.Glob(filepath.Join(t.dir, "*"+interstitialTemplateExt))
+	if err != nil {
+		return fmt.Errorf("failed to list interstitial templates: %w", err)
+	}
+
+	templates := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read interstitial template %s: %w", file, err)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(file), interstitialTemplateExt)
+		tmpl, err := template.New(name).Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("failed to parse interstitial template %s: %w", file, err)
+		}
+
+		templates[name] = tmpl
+	}
+
+	t.templates.Store(&templates)
+
+	return nil
+}
+
+// Watch function reloads templates with the provided interval until the context is canceled.
+func (t *InterstitialTemplates) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.Load(); err != nil {
+				slog.Error("an error occurred while reloading interstitial templates", logger.ErrAttr(err))
+			}
+		}
+	}
+}
+
+// render function executes the interstitial template, the page is returned only if it is rendered completely.
+func (t *InterstitialTemplates) render(interstitial *dto.Interstitial, targetURL string) ([]byte, error) {
+	tmpl, ok := (*t.templates.Load())[interstitial.Template]
+	if !ok {
+		return nil, fmt.Errorf("interstitial template %q is not found", interstitial.Template)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, interstitialData{TargetURL: targetURL, interstitial: interstitial}); err != nil {
+		return nil, fmt.Errorf("failed to render interstitial template %q: %w", interstitial.Template, err)
+	}
+
+	return buf.Bytes(), nil
+}
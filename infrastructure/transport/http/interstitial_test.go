@@ -0,0 +1,125 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lroman242/redirector/domain/dto"
+)
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+}
+
+func TestInterstitialTemplates_Load(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "notice.html", `<a href="{{.TargetURL}}">{{.Token "country_code"}}</a>`)
+	writeTemplate(t, dir, "readme.txt", `not a template`)
+
+	templates := NewInterstitialTemplates(dir)
+	if err := templates.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	interstitial := &dto.Interstitial{
+		Template: "notice",
+		Token: func(expr string) string {
+			return "token:" + expr
+		},
+	}
+
+	page, err := templates.render(interstitial, "https://example.com/?a=1&b=2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `<a href="https://example.com/?a=1&amp;b=2">token:country_code</a>`; string(page) != expected {
+		t.Errorf("unexpected page. expected %s got %s", expected, page)
+	}
+
+	if _, err := templates.render(&dto.Interstitial{Template: "readme"}, "https://example.com"); err == nil {
+		t.Error("expected error for unknown template")
+	}
+
+	// templates are replaced on reload
+	writeTemplate(t, dir, "notice.html", `updated {{.TargetURL}}`)
+	if err := templates.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page, _ := templates.render(interstitial, "https://example.com"); string(page) != "updated https://example.com" {
+		t.Errorf("expected reloaded template, got %s", page)
+	}
+
+	// invalid templates don't replace loaded ones
+	writeTemplate(t, dir, "broken.html", `{{.TargetURL`)
+	if err := templates.Load(); err == nil {
+		t.Error("expected error for invalid template")
+	}
+	if page, _ := templates.render(interstitial, "https://example.com"); string(page) != "updated https://example.com" {
+		t.Errorf("expected previously loaded template, got %s", page)
+	}
+}
+
+func TestRedirectHandler_WriteResult_Interstitial(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "notice.html", `continue to {{.TargetURL}}`)
+
+	templates := NewInterstitialTemplates(dir)
+	if err := templates.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		result           *dto.RedirectResult
+		cloaker          *ReferrerCloaker
+		expectedCode     int
+		expectedBodyPart string
+	}{
+		{
+			name:             "interstitial page",
+			result:           &dto.RedirectResult{TargetURL: "https://example.com", Interstitial: &dto.Interstitial{Template: "notice"}},
+			expectedCode:     http.StatusOK,
+			expectedBodyPart: "continue to https://example.com",
+		},
+		{
+			name: "interstitial page leads to hop when cloaked",
+			result: &dto.RedirectResult{
+				TargetURL:    "https://example.com",
+				Cloaked:      true,
+				Interstitial: &dto.Interstitial{Template: "notice"},
+			},
+			cloaker:          NewReferrerCloaker("secret", 0),
+			expectedCode:     http.StatusOK,
+			expectedBodyPart: "continue to /hop?",
+		},
+		{
+			name:         "redirect when template is missing",
+			result:       &dto.RedirectResult{TargetURL: "https://example.com", Interstitial: &dto.Interstitial{Template: "unknown"}},
+			expectedCode: http.StatusSeeOther,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rh := NewRedirectHandler(nil, tt.cloaker, templates, false)
+			rec := httptest.NewRecorder()
+
+			rh.writeResult(rec, httptest.NewRequest(http.MethodGet, "/r/slug", nil), tt.result)
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("unexpected status code. expected %d got %d", tt.expectedCode, rec.Code)
+			}
+			if !strings.Contains(rec.Body.String(), tt.expectedBodyPart) {
+				t.Errorf("expected body to contain %s, got %s", tt.expectedBodyPart, rec.Body.String())
+			}
+		})
+	}
+}
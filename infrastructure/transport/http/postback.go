@@ -0,0 +1,150 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/lroman242/redirector/domain/dto"
+	"github.com/lroman242/redirector/domain/interactor"
+	"github.com/lroman242/redirector/infrastructure/metrics"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// postbackPath is the path of the conversion postback endpoint.
+	postbackPath = "/postback"
+	// pixelPath is the path of the conversion tracking pixel endpoint.
+	pixelPath = "/pixel.gif"
+)
+
+// transparentPixel is a 1x1 transparent GIF image returned by the pixel endpoint.
+var transparentPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// conversionStatusCodes maps conversion errors to postback response status codes.
+var conversionStatusCodes = map[error]int{
+	interactor.ErrInvalidConversion:      http.StatusBadRequest,
+	interactor.ErrClickNotFound:          http.StatusNotFound,
+	interactor.ErrDuplicateConversion:    http.StatusConflict,
+	interactor.ErrUnauthorizedConversion: http.StatusForbidden,
+}
+
+// conversionStatusCode returns the HTTP status code for the conversion error.
+func conversionStatusCode(err error) int {
+	for target, code := range conversionStatusCodes {
+		if errors.Is(err, target) {
+			return code
+		}
+	}
+
+	return http.StatusInternalServerError
+}
+
+// conversionStatus returns the metrics label for the conversion error.
+func conversionStatus(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, interactor.ErrInvalidConversion):
+		return "invalid"
+	case errors.Is(err, interactor.ErrClickNotFound):
+		return "not_found"
+	case errors.Is(err, interactor.ErrDuplicateConversion):
+		return "duplicate"
+	case errors.Is(err, interactor.ErrUnauthorizedConversion):
+		return "unauthorized"
+	default:
+		return "error"
+	}
+}
+
+// PostbackHandler handles conversion postbacks and tracking pixel requests by delegating to a ConversionInteractor.
+type PostbackHandler struct {
+	interactor interactor.ConversionInteractor
+	pixel      bool
+}
+
+// NewPostbackHandler creates a new PostbackHandler instance which responds with the status code matching the result.
+func NewPostbackHandler(interactor interactor.ConversionInteractor) *PostbackHandler {
+	return &PostbackHandler{interactor: interactor}
+}
+
+// NewPixelHandler creates a new PostbackHandler instance which always responds with the transparent pixel image.
+// Browsers can't act on the failures, so errors are logged only. Unsigned pixel conversions are accepted,
+// but their payout is ignored and affiliates are not notified.
+func NewPixelHandler(interactor interactor.ConversionInteractor) *PostbackHandler {
+	return &PostbackHandler{interactor: interactor, pixel: true}
+}
+
+// getConversionRequestData reads conversion parameters from the query string or the form body.
+// The signature covers the URL encoded click_id, event, payout and transaction_id parameters sorted by name.
+func getConversionRequestData(r *http.Request) (*dto.ConversionRequestData, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, fmt.Errorf("%w: %s", interactor.ErrInvalidConversion, err.Error())
+	}
+
+	data := &dto.ConversionRequestData{
+		RequestID:     uuid.NewV4().String(),
+		ClickID:       r.Form.Get("click_id"),
+		EventType:     r.Form.Get("event"),
+		TransactionID: r.Form.Get("transaction_id"),
+		Signature:     r.Form.Get("signature"),
+		SignedPayload: url.Values{
+			"click_id":       {r.Form.Get("click_id")},
+			"event":          {r.Form.Get("event")},
+			"payout":         {r.Form.Get("payout")},
+			"transaction_id": {r.Form.Get("transaction_id")},
+		}.Encode(),
+	}
+
+	if payout := strings.TrimSpace(r.Form.Get("payout")); payout != "" {
+		value, err := strconv.ParseFloat(payout, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: payout must be a number", interactor.ErrInvalidConversion)
+		}
+
+		data.Payout = value
+	}
+
+	return data, nil
+}
+
+// ServeHTTP handles conversion postback requests.
+func (ph *PostbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, err := getConversionRequestData(r)
+	if err == nil {
+		data.AllowUnsigned = ph.pixel
+		_, err = ph.interactor.Convert(r.Context(), data)
+	}
+
+	metrics.ConversionsProcessed.WithLabelValues(conversionStatus(err)).Inc()
+	if err != nil {
+		slog.Error("Conversion failed", slog.String("error", err.Error()), slog.String("query", r.URL.RawQuery))
+	}
+
+	if ph.pixel {
+		w.Header().Set("Content-Type", "image/gif")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(transparentPixel)
+		return
+	}
+
+	if err != nil {
+		// internal error messages are logged only, advertisers see the status text
+		code := conversionStatusCode(err)
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(http.StatusText(http.StatusOK)))
+}
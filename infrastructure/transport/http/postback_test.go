@@ -0,0 +1,173 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/lroman242/redirector/domain/dto"
+	"github.com/lroman242/redirector/domain/entity"
+	"github.com/lroman242/redirector/domain/interactor"
+	"github.com/lroman242/redirector/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPostbackHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		query         string
+		form          url.Values
+		expectConvert bool
+		signature     string
+		convertErr    error
+		expectedCode  int
+	}{
+		{
+			name:          "success",
+			method:        http.MethodGet,
+			query:         "click_id=click-1&event=sale&payout=2.5&transaction_id=tx-1&signature=abcd",
+			expectConvert: true,
+			signature:     "abcd",
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:   "form post",
+			method: http.MethodPost,
+			form: url.Values{
+				"click_id": {"click-1"}, "event": {"sale"}, "payout": {"2.5"}, "transaction_id": {"tx-1"}, "signature": {"abcd"},
+			},
+			expectConvert: true,
+			signature:     "abcd",
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:         "malformed payout",
+			method:       http.MethodGet,
+			query:        "click_id=click-1&payout=abc",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:          "invalid conversion",
+			method:        http.MethodGet,
+			query:         "click_id=click-1&event=sale&payout=2.5&transaction_id=tx-1",
+			expectConvert: true,
+			convertErr:    fmt.Errorf("%w: click ID is required", interactor.ErrInvalidConversion),
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			name:          "click not found",
+			method:        http.MethodGet,
+			query:         "click_id=click-1&event=sale&payout=2.5&transaction_id=tx-1",
+			expectConvert: true,
+			convertErr:    interactor.ErrClickNotFound,
+			expectedCode:  http.StatusNotFound,
+		},
+		{
+			name:          "duplicate",
+			method:        http.MethodGet,
+			query:         "click_id=click-1&event=sale&payout=2.5&transaction_id=tx-1",
+			expectConvert: true,
+			convertErr:    interactor.ErrDuplicateConversion,
+			expectedCode:  http.StatusConflict,
+		},
+		{
+			name:          "unauthorized",
+			method:        http.MethodGet,
+			query:         "click_id=click-1&event=sale&payout=2.5&transaction_id=tx-1",
+			expectConvert: true,
+			convertErr:    interactor.ErrUnauthorizedConversion,
+			expectedCode:  http.StatusForbidden,
+		},
+		{
+			name:          "internal error",
+			method:        http.MethodGet,
+			query:         "click_id=click-1&event=sale&payout=2.5&transaction_id=tx-1",
+			expectConvert: true,
+			convertErr:    errors.New("storage is down"),
+			expectedCode:  http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			conversionInteractor := mocks.NewMockConversionInteractor(ctrl)
+			if tt.expectConvert {
+				conversionInteractor.EXPECT().
+					Convert(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, data *dto.ConversionRequestData) (*entity.Conversion, error) {
+						if data.ClickID != "click-1" || data.EventType != "sale" || data.Payout != 2.5 || data.TransactionID != "tx-1" {
+							t.Errorf("unexpected conversion request data %+v", data)
+						}
+						if data.RequestID == "" {
+							t.Error("expected request id to be generated")
+						}
+						if data.Signature != tt.signature || data.AllowUnsigned {
+							t.Errorf("unexpected signature %q, unsigned %t", data.Signature, data.AllowUnsigned)
+						}
+						if data.SignedPayload != "click_id=click-1&event=sale&payout=2.5&transaction_id=tx-1" {
+							t.Errorf("unexpected signed payload %q", data.SignedPayload)
+						}
+
+						return &entity.Conversion{ID: data.RequestID}, tt.convertErr
+					})
+			}
+
+			req := httptest.NewRequest(tt.method, postbackPath+"?"+tt.query, nil)
+			if tt.form != nil {
+				req = httptest.NewRequest(tt.method, postbackPath, strings.NewReader(tt.form.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			rec := httptest.NewRecorder()
+
+			NewPostbackHandler(conversionInteractor).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("unexpected status code. expected %d got %d", tt.expectedCode, rec.Code)
+			}
+			if strings.Contains(rec.Body.String(), "storage is down") {
+				t.Error("internal error message is exposed")
+			}
+		})
+	}
+}
+
+func TestPixelHandler_ServeHTTP(t *testing.T) {
+	for _, convertErr := range []error{nil, interactor.ErrClickNotFound} {
+		ctrl := gomock.NewController(t)
+		conversionInteractor := mocks.NewMockConversionInteractor(ctrl)
+		conversionInteractor.EXPECT().
+			Convert(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ any, data *dto.ConversionRequestData) (*entity.Conversion, error) {
+				if !data.AllowUnsigned {
+					t.Error("expected pixel conversions to be accepted without signature")
+				}
+
+				return nil, convertErr
+			})
+
+		req := httptest.NewRequest(http.MethodGet, pixelPath+"?click_id=click-1", nil)
+		rec := httptest.NewRecorder()
+
+		NewPixelHandler(conversionInteractor).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("unexpected status code. expected %d got %d", http.StatusOK, rec.Code)
+		}
+		if contentType := rec.Header().Get("Content-Type"); contentType != "image/gif" {
+			t.Errorf("unexpected content type %s", contentType)
+		}
+		if rec.Header().Get("Cache-Control") != "no-store" {
+			t.Error("expected pixel to be not cacheable")
+		}
+		if !bytes.Equal(rec.Body.Bytes(), transparentPixel) {
+			t.Error("unexpected pixel body")
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+
+	"github.com/lroman242/redirector/domain/dto"
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+// redirectStatusCodes maps HTTP redirect methods to status codes.
+var redirectStatusCodes = map[string]int{
+	valueobject.MovedPermanentlyRedirectMethod: http.StatusMovedPermanently,
+	valueobject.FoundRedirectMethod:            http.StatusFound,
+	valueobject.TemporaryRedirectMethod:        http.StatusTemporaryRedirect,
+	valueobject.PermanentRedirectMethod:        http.StatusPermanentRedirect,
+}
+
+// metaRefreshTemplate renders the page which redirects the visitor with meta refresh without sending the referrer.
+var metaRefreshTemplate = template.Must(template.New("meta_refresh").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="referrer" content="no-referrer">
+<meta http-equiv="refresh" content="0;url={{.}}">
+<title>Redirecting...</title>
+</head>
+<body><a href="{{.}}" rel="noreferrer">Continue</a></body>
+</html>
+`))
+
+// javaScriptTemplate renders the page which redirects the visitor with JavaScript, meta refresh is used as a fallback.
+var javaScriptTemplate = template.Must(template.New("javascript").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Redirecting...</title>
+<script>window.location.replace({{.}});</script>
+<noscript><meta http-equiv="refresh" content="0;url={{.}}"></noscript>
+</head>
+<body><a href="{{.}}">Continue</a></body>
+</html>
+`))
+
+// writeRedirect sends the visitor to the target URL using the redirect method of the result.
+// HTML pages are rendered for meta refresh and JavaScript methods, "303 See Other" is used by default.
+func writeRedirect(w http.ResponseWriter, r *http.Request, result *dto.RedirectResult) {
+	switch result.Method {
+	case valueobject.MetaRefreshRedirectMethod:
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		writeRedirectPage(w, metaRefreshTemplate, result.TargetURL)
+	case valueobject.JavaScriptRedirectMethod:
+		writeRedirectPage(w, javaScriptTemplate, result.TargetURL)
+	default:
+		code, ok := redirectStatusCodes[result.Method]
+		if !ok {
+			code = http.StatusSeeOther
+		}
+
+		http.Redirect(w, r, result.TargetURL, code)
+	}
+}
+
+// writeRedirectPage renders the redirect page.
+func writeRedirectPage(w http.ResponseWriter, tmpl *template.Template, targetURL string) {
+	writePageHeader(w)
+
+	if err := tmpl.Execute(w, targetURL); err != nil {
+		slog.Error("Failed to render redirect page", slog.String("error", err.Error()), slog.String("template", tmpl.Name()))
+	}
+}
+
+// writePageHeader writes headers of HTML pages, pages are not cached so every visit reaches the tracker.
+func writePageHeader(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
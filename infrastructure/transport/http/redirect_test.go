@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lroman242/redirector/domain/dto"
+	"github.com/lroman242/redirector/domain/valueobject"
+)
+
+func TestWriteRedirect(t *testing.T) {
+	const targetURL = "https://example.com/offer?a=1&b=2"
+
+	tests := []struct {
+		name         string
+		method       string
+		expectedCode int
+		expectedBody []string
+	}{
+		{name: "default", expectedCode: http.StatusSeeOther},
+		{name: "unknown method", method: "303", expectedCode: http.StatusSeeOther},
+		{name: "301", method: valueobject.MovedPermanentlyRedirectMethod, expectedCode: http.StatusMovedPermanently},
+		{name: "302", method: valueobject.FoundRedirectMethod, expectedCode: http.StatusFound},
+		{name: "307", method: valueobject.TemporaryRedirectMethod, expectedCode: http.StatusTemporaryRedirect},
+		{name: "308", method: valueobject.PermanentRedirectMethod, expectedCode: http.StatusPermanentRedirect},
+		{
+			name:         "meta refresh",
+			method:       valueobject.MetaRefreshRedirectMethod,
+			expectedCode: http.StatusOK,
+			expectedBody: []string{
+				`<meta name="referrer" content="no-referrer">`,
+				`<meta http-equiv="refresh" content="0;url=https://example.com/offer?a=1&amp;b=2">`,
+			},
+		},
+		{
+			name:         "javascript",
+			method:       valueobject.JavaScriptRedirectMethod,
+			expectedCode: http.StatusOK,
+			expectedBody: []string{`window.location.replace("https://example.com/offer?a=1\u0026b=2")`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/r/slug", nil)
+			rec := httptest.NewRecorder()
+
+			writeRedirect(rec, req, &dto.RedirectResult{TargetURL: targetURL, Method: tt.method})
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("unexpected status code. expected %d got %d", tt.expectedCode, rec.Code)
+			}
+
+			if len(tt.expectedBody) == 0 {
+				if location := rec.Header().Get("Location"); location != targetURL {
+					t.Errorf("unexpected location. expected %s got %s", targetURL, location)
+				}
+				return
+			}
+
+			for _, expected := range tt.expectedBody {
+				if !strings.Contains(rec.Body.String(), expected) {
+					t.Errorf("expected body to contain %s, got %s", expected, rec.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -9,7 +9,14 @@ import (
 )
 
 // NewHandler register a new HTTP handler (router).
-func NewHandler(interactor interactor.RedirectInteractor) http.Handler {
+// Conversion endpoints are registered only if the conversion interactor is set.
+func NewHandler(
+	interactor interactor.RedirectInteractor,
+	cloaker *ReferrerCloaker,
+	interstitials *InterstitialTemplates,
+	conversionInteractor interactor.ConversionInteractor,
+	trustForwardedProto bool,
+) http.Handler {
 	r := mux.NewRouter()
 
 	// Prometheus metrics endpoint
@@ -22,7 +29,16 @@ func NewHandler(interactor interactor.RedirectInteractor) http.Handler {
 	}))
 
 	// Redirect endpoint
-	r.Handle("/r/{slug}", NewRedirectHandler(interactor))
+	r.Handle("/r/{slug}", NewRedirectHandler(interactor, cloaker, interstitials, trustForwardedProto))
+
+	// Referrer cloaking hop endpoint
+	r.Handle(hopPath, cloaker)
+
+	// Conversion postback and tracking pixel endpoints
+	if conversionInteractor != nil {
+		r.Handle(postbackPath, NewPostbackHandler(conversionInteractor)).Methods(http.MethodGet, http.MethodPost)
+		r.Handle(pixelPath, NewPixelHandler(conversionInteractor)).Methods(http.MethodGet)
+	}
 
 	return r
 }
@@ -0,0 +1,2 @@
+ALTER TABLE redirect_rules
+    DROP COLUMN IF EXISTS sticky_smart_slug;
@@ -0,0 +1,4 @@
+-- redirect_smart_slug keeps a list of weighted targets, e.g. [{"slug": "a", "weight": 70}, {"slug": "b", "weight": 30}].
+-- Plain string items (legacy format) are treated as targets with weight 1.
+ALTER TABLE redirect_rules
+    ADD COLUMN sticky_smart_slug boolean NOT NULL DEFAULT false;
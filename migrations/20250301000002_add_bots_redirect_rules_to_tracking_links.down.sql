@@ -0,0 +1,6 @@
+ALTER TABLE tracking_links
+    DROP CONSTRAINT IF EXISTS tracking_links_campaign_bots_redirect_rules_id_fkey;
+
+ALTER TABLE tracking_links
+    DROP COLUMN IF EXISTS campaign_bots_redirect_rules_id,
+    DROP COLUMN IF EXISTS allow_bots;
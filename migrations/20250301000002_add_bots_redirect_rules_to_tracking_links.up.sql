@@ -0,0 +1,3 @@
+ALTER TABLE tracking_links
+    ADD COLUMN allow_bots boolean NOT NULL DEFAULT true,
+    ADD COLUMN campaign_bots_redirect_rules_id integer REFERENCES redirect_rules(id);
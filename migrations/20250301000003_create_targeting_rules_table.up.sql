@@ -0,0 +1,14 @@
+-- conditions keeps a list of checks, e.g. [{"field": "country", "operator": "in", "values": ["US", "CA"]}]
+CREATE TABLE targeting_rules (
+    id                serial PRIMARY KEY,
+    slug              varchar(55) NOT NULL REFERENCES tracking_links(slug),
+    priority          integer     NOT NULL DEFAULT 0,
+    logic             varchar(3)  NOT NULL DEFAULT 'and',
+    conditions        jsonb       NOT NULL DEFAULT '[]',
+    redirect_rules_id integer     NOT NULL REFERENCES redirect_rules(id),
+    active            boolean     NOT NULL DEFAULT true,
+    created_at        timestamp without time zone DEFAULT NOW(),
+    updated_at        timestamp without time zone DEFAULT NOW()
+);
+
+CREATE INDEX idx_targeting_rules_slug ON targeting_rules(slug, priority);
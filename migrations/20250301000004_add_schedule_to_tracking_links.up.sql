@@ -0,0 +1,3 @@
+ALTER TABLE tracking_links
+    ADD COLUMN schedule jsonb NOT NULL DEFAULT '{}',
+    ADD COLUMN campaign_schedule_redirect_rules_id integer REFERENCES redirect_rules(id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS campaign_click_caps;
+
+ALTER TABLE tracking_links
+    DROP COLUMN IF EXISTS click_caps;
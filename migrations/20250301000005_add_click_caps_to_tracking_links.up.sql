@@ -0,0 +1,11 @@
+ALTER TABLE tracking_links
+    ADD COLUMN click_caps jsonb NOT NULL DEFAULT '{}';
+
+-- Campaign click caps are shared by all tracking links of the campaign,
+-- so they are counted under a single campaign counter
+CREATE TABLE campaign_click_caps (
+    campaign_id varchar(255) PRIMARY KEY,
+    click_caps  jsonb NOT NULL DEFAULT '{}',
+    created_at  timestamp without time zone DEFAULT NOW(),
+    updated_at  timestamp without time zone DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+ALTER TABLE tracking_links
+    ADD COLUMN handle_duplicate_clicks boolean NOT NULL DEFAULT false,
+    ADD COLUMN campaign_duplicate_redirect_rules_id integer REFERENCES redirect_rules(id);
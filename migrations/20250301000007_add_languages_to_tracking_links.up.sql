@@ -0,0 +1,3 @@
+ALTER TABLE tracking_links
+    ADD COLUMN allowed_languages jsonb NOT NULL DEFAULT '{}',
+    ADD COLUMN campaign_language_redirect_rules_id integer REFERENCES redirect_rules(id);
@@ -0,0 +1,9 @@
+ALTER TABLE tracking_links
+    DROP CONSTRAINT IF EXISTS tracking_links_campaign_region_redirect_rules_id_fkey,
+    DROP CONSTRAINT IF EXISTS tracking_links_campaign_city_redirect_rules_id_fkey;
+
+ALTER TABLE tracking_links
+    DROP COLUMN IF EXISTS campaign_city_redirect_rules_id,
+    DROP COLUMN IF EXISTS campaign_region_redirect_rules_id,
+    DROP COLUMN IF EXISTS allowed_cities,
+    DROP COLUMN IF EXISTS allowed_regions;
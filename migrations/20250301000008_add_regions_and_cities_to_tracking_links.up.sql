@@ -0,0 +1,5 @@
+ALTER TABLE tracking_links
+    ADD COLUMN allowed_regions jsonb NOT NULL DEFAULT '{}',
+    ADD COLUMN allowed_cities jsonb NOT NULL DEFAULT '{}',
+    ADD COLUMN campaign_region_redirect_rules_id integer REFERENCES redirect_rules(id),
+    ADD COLUMN campaign_city_redirect_rules_id integer REFERENCES redirect_rules(id);
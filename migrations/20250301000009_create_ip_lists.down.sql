@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS ip_lists;
+
+ALTER TABLE tracking_links
+    DROP CONSTRAINT IF EXISTS tracking_links_campaign_ip_redirect_rules_id_fkey;
+
+ALTER TABLE tracking_links
+    DROP COLUMN IF EXISTS campaign_ip_redirect_rules_id,
+    DROP COLUMN IF EXISTS denied_ips,
+    DROP COLUMN IF EXISTS allowed_ips;
@@ -0,0 +1,16 @@
+-- allowed_ips/denied_ips keep lists of networks, e.g. ["10.0.0.0/8", "2001:db8::/32", "192.168.1.1"]
+ALTER TABLE tracking_links
+    ADD COLUMN allowed_ips jsonb NOT NULL DEFAULT '[]',
+    ADD COLUMN denied_ips jsonb NOT NULL DEFAULT '[]',
+    ADD COLUMN campaign_ip_redirect_rules_id integer REFERENCES redirect_rules(id);
+
+-- global lists applied to all tracking links, action is either 'allow' or 'deny'
+CREATE TABLE ip_lists (
+    id         serial PRIMARY KEY,
+    cidr       cidr        NOT NULL,
+    action     varchar(5)  NOT NULL DEFAULT 'deny',
+    comment    varchar(255),
+    active     boolean     NOT NULL DEFAULT true,
+    created_at timestamp without time zone DEFAULT NOW(),
+    updated_at timestamp without time zone DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+-- allowed_token_keys keeps params/headers/cookies which might be echoed by dynamic tokens,
+-- e.g. {"param:utm_source": true, "header:X-Device-Id": true, "cookie:vid": true}
+ALTER TABLE tracking_links
+    ADD COLUMN allowed_token_keys jsonb NOT NULL DEFAULT '{}';
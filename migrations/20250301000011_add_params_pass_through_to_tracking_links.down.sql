@@ -0,0 +1,2 @@
+ALTER TABLE tracking_links
+    DROP COLUMN IF EXISTS params_pass_through;
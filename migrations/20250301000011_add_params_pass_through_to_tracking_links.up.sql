@@ -0,0 +1,4 @@
+-- params_pass_through keeps the policy of merging incoming query params into the target URL,
+-- e.g. {"mode": "deny", "params": ["token"], "on_conflict": "override"}
+ALTER TABLE tracking_links
+    ADD COLUMN params_pass_through jsonb NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+ALTER TABLE tracking_links
+    DROP COLUMN IF EXISTS forwarded_ad_click_ids;
@@ -0,0 +1,4 @@
+-- forwarded_ad_click_ids keeps ad network click identifiers forwarded to the destination,
+-- e.g. {"gclid": true, "fbclid": true, "msclkid": false}
+ALTER TABLE tracking_links
+    ADD COLUMN forwarded_ad_click_ids jsonb NOT NULL DEFAULT '{}';
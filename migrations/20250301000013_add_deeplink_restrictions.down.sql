@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS advertiser_deeplink_domains;
+
+ALTER TABLE tracking_links
+    DROP CONSTRAINT IF EXISTS tracking_links_campaign_deeplink_redirect_rules_id_fkey;
+
+ALTER TABLE tracking_links
+    DROP COLUMN IF EXISTS campaign_deeplink_redirect_rules_id,
+    DROP COLUMN IF EXISTS allowed_deeplink_domains,
+    DROP COLUMN IF EXISTS allowed_deeplink_schemes;
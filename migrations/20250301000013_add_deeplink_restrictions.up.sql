@@ -0,0 +1,17 @@
+-- allowed_deeplink_schemes/allowed_deeplink_domains keep deeplink allow-lists,
+-- e.g. {"https": true, "myapp": true} and {"example.com": true, "*.example.com": true}
+ALTER TABLE tracking_links
+    ADD COLUMN allowed_deeplink_schemes jsonb NOT NULL DEFAULT '{}',
+    ADD COLUMN allowed_deeplink_domains jsonb NOT NULL DEFAULT '{}',
+    ADD COLUMN campaign_deeplink_redirect_rules_id integer REFERENCES redirect_rules(id);
+
+-- domain patterns allowed for deeplinks of all advertiser tracking links
+CREATE TABLE advertiser_deeplink_domains (
+    id            serial PRIMARY KEY,
+    advertiser_id varchar(255) NOT NULL,
+    domain        varchar(255) NOT NULL,
+    created_at    timestamp without time zone DEFAULT NOW(),
+    updated_at    timestamp without time zone DEFAULT NOW()
+);
+
+CREATE INDEX idx_advertiser_deeplink_domains_advertiser ON advertiser_deeplink_domains(advertiser_id);
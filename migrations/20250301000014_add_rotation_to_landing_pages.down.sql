@@ -0,0 +1,3 @@
+ALTER TABLE landing_pages
+    DROP COLUMN IF EXISTS active,
+    DROP COLUMN IF EXISTS weight;
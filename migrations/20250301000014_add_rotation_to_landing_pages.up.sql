@@ -0,0 +1,5 @@
+-- weight/active control default landing page rotation, landing pages are not rotated
+-- until they are activated, so existing tracking links keep their target URL
+ALTER TABLE landing_pages
+    ADD COLUMN weight integer NOT NULL DEFAULT 1 CHECK (weight >= 0),
+    ADD COLUMN active boolean NOT NULL DEFAULT false;
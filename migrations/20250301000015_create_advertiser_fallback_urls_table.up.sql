@@ -0,0 +1,11 @@
+-- URLs rejected traffic of the advertiser is sent to instead of error pages,
+-- error_class is one of "disabled", "blocked", "geo", "misconfigured"
+CREATE TABLE advertiser_fallback_urls (
+    id            serial PRIMARY KEY,
+    advertiser_id varchar(255) NOT NULL,
+    error_class   varchar(55) NOT NULL,
+    url           text NOT NULL,
+    created_at    timestamp without time zone DEFAULT NOW(),
+    updated_at    timestamp without time zone DEFAULT NOW(),
+    UNIQUE (advertiser_id, error_class)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE redirect_rules
+    DROP COLUMN IF EXISTS redirect_method;
+
+ALTER TABLE tracking_links
+    DROP COLUMN IF EXISTS redirect_method;
@@ -0,0 +1,7 @@
+-- redirect_method is one of "301", "302", "307", "308", "meta_refresh", "javascript",
+-- "303 See Other" redirect is used when empty, redirect rules override the tracking link method
+ALTER TABLE tracking_links
+    ADD COLUMN redirect_method varchar(55) NOT NULL DEFAULT '';
+
+ALTER TABLE redirect_rules
+    ADD COLUMN redirect_method varchar(55) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE tracking_links
+    DROP COLUMN IF EXISTS cloak_referrer;
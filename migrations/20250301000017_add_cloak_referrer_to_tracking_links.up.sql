@@ -0,0 +1,3 @@
+-- cloak_referrer hides the source site from the target with a double meta refresh through the /hop endpoint
+ALTER TABLE tracking_links
+    ADD COLUMN cloak_referrer boolean NOT NULL DEFAULT false;
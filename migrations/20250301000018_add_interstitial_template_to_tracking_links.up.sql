@@ -0,0 +1,4 @@
+-- interstitial_template is the name of the page template (<name>.html in the templates directory)
+-- rendered before the redirect, the visitor is redirected immediately if empty
+ALTER TABLE tracking_links
+    ADD COLUMN interstitial_template varchar(255) NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- deduplication keys of registered conversions, the primary key rejects repeated postbacks atomically.
+-- key is "transaction|<advertiser_id>|<transaction_id>" or "event|<click_id>|<event_type>"
+CREATE TABLE conversion_keys (
+    key           text PRIMARY KEY,
+    conversion_id varchar(36) NOT NULL,
+    created_at    timestamp with time zone DEFAULT NOW()
+);
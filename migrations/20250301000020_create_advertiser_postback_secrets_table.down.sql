@@ -0,0 +1 @@
+DROP TABLE IF EXISTS advertiser_postback_secrets;
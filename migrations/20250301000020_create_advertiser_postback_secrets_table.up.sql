@@ -0,0 +1,8 @@
+-- secrets advertisers sign conversion postbacks with (HMAC-SHA256), unsigned postbacks are rejected
+-- and unsigned pixel conversions are stored without payout and affiliate postbacks
+CREATE TABLE advertiser_postback_secrets (
+    advertiser_id varchar(255) PRIMARY KEY,
+    secret        text NOT NULL,
+    created_at    timestamp without time zone DEFAULT NOW(),
+    updated_at    timestamp without time zone DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS postback_attempts;
+DROP TABLE IF EXISTS postback_queue;
+DROP TABLE IF EXISTS affiliate_postback_urls;
@@ -0,0 +1,42 @@
+-- postback URL templates of affiliates, the template with the matching source_id takes precedence
+-- over the affiliate-wide one (empty source_id)
+CREATE TABLE affiliate_postback_urls (
+    id           serial PRIMARY KEY,
+    affiliate_id varchar(255) NOT NULL,
+    source_id    varchar(255) NOT NULL DEFAULT '',
+    url_template text NOT NULL,
+    created_at   timestamp without time zone DEFAULT NOW(),
+    updated_at   timestamp without time zone DEFAULT NOW(),
+    UNIQUE (affiliate_id, source_id)
+);
+
+-- durable delivery queue of affiliate postbacks, status is one of 'pending', 'delivered', 'failed'
+CREATE TABLE postback_queue (
+    id              varchar(36) PRIMARY KEY,
+    conversion_id   varchar(36) NOT NULL,
+    affiliate_id    varchar(255) NOT NULL,
+    source_id       varchar(255) NOT NULL DEFAULT '',
+    url             text NOT NULL,
+    status          varchar(15) NOT NULL DEFAULT 'pending',
+    attempts        integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    created_at      timestamp with time zone DEFAULT NOW(),
+    updated_at      timestamp with time zone DEFAULT NOW()
+);
+
+CREATE INDEX postback_queue_pending_idx ON postback_queue (next_attempt_at) WHERE status = 'pending';
+
+-- log of postback delivery attempts, status_code is 0 if no response was received
+CREATE TABLE postback_attempts (
+    id            bigserial PRIMARY KEY,
+    postback_id   varchar(36) NOT NULL REFERENCES postback_queue(id) ON DELETE CASCADE,
+    conversion_id varchar(36) NOT NULL,
+    attempt       integer NOT NULL,
+    url           text NOT NULL,
+    status_code   integer NOT NULL DEFAULT 0,
+    error         text NOT NULL DEFAULT '',
+    duration_ms   bigint NOT NULL DEFAULT 0,
+    created_at    timestamp with time zone DEFAULT NOW()
+);
+
+CREATE INDEX postback_attempts_postback_id_idx ON postback_attempts (postback_id);
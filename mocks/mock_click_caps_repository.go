@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/repository/click_caps_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mocks -destination=mocks/mock_click_caps_repository.go -source=domain/repository/click_caps_repository.go ClickCapsRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/lroman242/redirector/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockClickCapsRepository is a mock of ClickCapsRepository interface.
+type MockClickCapsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockClickCapsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockClickCapsRepositoryMockRecorder is the mock recorder for MockClickCapsRepository.
+type MockClickCapsRepositoryMockRecorder struct {
+	mock *MockClickCapsRepository
+}
+
+// NewMockClickCapsRepository creates a new mock instance.
+func NewMockClickCapsRepository(ctrl *gomock.Controller) *MockClickCapsRepository {
+	mock := &MockClickCapsRepository{ctrl: ctrl}
+	mock.recorder = &MockClickCapsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClickCapsRepository) EXPECT() *MockClickCapsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Reserve mocks base method.
+func (m *MockClickCapsRepository) Reserve(ctx context.Context, trackingLink *entity.TrackingLink, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, trackingLink, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockClickCapsRepositoryMockRecorder) Reserve(ctx, trackingLink, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockClickCapsRepository)(nil).Reserve), ctx, trackingLink, at)
+}
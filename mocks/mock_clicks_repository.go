@@ -41,6 +41,21 @@ func (m *MockClicksRepository) EXPECT() *MockClicksRepositoryMockRecorder {
 	return m.recorder
 }
 
+// FindClick mocks base method.
+func (m *MockClicksRepository) FindClick(ctx context.Context, id string) (*entity.Click, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindClick", ctx, id)
+	ret0, _ := ret[0].(*entity.Click)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindClick indicates an expected call of FindClick.
+func (mr *MockClicksRepositoryMockRecorder) FindClick(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindClick", reflect.TypeOf((*MockClicksRepository)(nil).FindClick), ctx, id)
+}
+
 // Save mocks base method.
 func (m *MockClicksRepository) Save(ctx context.Context, click *entity.Click) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/interactor/conversion_interactor.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mocks -destination=mocks/mock_conversion_interactor.go -source=domain/interactor/conversion_interactor.go ConversionInteractor
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/lroman242/redirector/domain/dto"
+	entity "github.com/lroman242/redirector/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConversionInteractor is a mock of ConversionInteractor interface.
+type MockConversionInteractor struct {
+	ctrl     *gomock.Controller
+	recorder *MockConversionInteractorMockRecorder
+	isgomock struct{}
+}
+
+// MockConversionInteractorMockRecorder is the mock recorder for MockConversionInteractor.
+type MockConversionInteractorMockRecorder struct {
+	mock *MockConversionInteractor
+}
+
+// NewMockConversionInteractor creates a new mock instance.
+func NewMockConversionInteractor(ctrl *gomock.Controller) *MockConversionInteractor {
+	mock := &MockConversionInteractor{ctrl: ctrl}
+	mock.recorder = &MockConversionInteractorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConversionInteractor) EXPECT() *MockConversionInteractorMockRecorder {
+	return m.recorder
+}
+
+// Convert mocks base method.
+func (m *MockConversionInteractor) Convert(ctx context.Context, requestData *dto.ConversionRequestData) (*entity.Conversion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Convert", ctx, requestData)
+	ret0, _ := ret[0].(*entity.Conversion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Convert indicates an expected call of Convert.
+func (mr *MockConversionInteractorMockRecorder) Convert(ctx, requestData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Convert", reflect.TypeOf((*MockConversionInteractor)(nil).Convert), ctx, requestData)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/repository/conversion_keys_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mocks -destination=mocks/mock_conversion_keys_repository.go -source=domain/repository/conversion_keys_repository.go ConversionKeysRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/lroman242/redirector/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConversionKeysRepository is a mock of ConversionKeysRepository interface.
+type MockConversionKeysRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConversionKeysRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockConversionKeysRepositoryMockRecorder is the mock recorder for MockConversionKeysRepository.
+type MockConversionKeysRepositoryMockRecorder struct {
+	mock *MockConversionKeysRepository
+}
+
+// NewMockConversionKeysRepository creates a new mock instance.
+func NewMockConversionKeysRepository(ctrl *gomock.Controller) *MockConversionKeysRepository {
+	mock := &MockConversionKeysRepository{ctrl: ctrl}
+	mock.recorder = &MockConversionKeysRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConversionKeysRepository) EXPECT() *MockConversionKeysRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Release mocks base method.
+func (m *MockConversionKeysRepository) Release(ctx context.Context, conversion *entity.Conversion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, conversion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockConversionKeysRepositoryMockRecorder) Release(ctx, conversion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockConversionKeysRepository)(nil).Release), ctx, conversion)
+}
+
+// Reserve mocks base method.
+func (m *MockConversionKeysRepository) Reserve(ctx context.Context, conversion *entity.Conversion) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, conversion)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockConversionKeysRepositoryMockRecorder) Reserve(ctx, conversion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockConversionKeysRepository)(nil).Reserve), ctx, conversion)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/repository/conversions_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mocks -destination=mocks/mock_conversions_repository.go -source=domain/repository/conversions_repository.go ConversionsRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/lroman242/redirector/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConversionsRepository is a mock of ConversionsRepository interface.
+type MockConversionsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConversionsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockConversionsRepositoryMockRecorder is the mock recorder for MockConversionsRepository.
+type MockConversionsRepositoryMockRecorder struct {
+	mock *MockConversionsRepository
+}
+
+// NewMockConversionsRepository creates a new mock instance.
+func NewMockConversionsRepository(ctrl *gomock.Controller) *MockConversionsRepository {
+	mock := &MockConversionsRepository{ctrl: ctrl}
+	mock.recorder = &MockConversionsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConversionsRepository) EXPECT() *MockConversionsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockConversionsRepository) Save(ctx context.Context, conversion *entity.Conversion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, conversion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockConversionsRepositoryMockRecorder) Save(ctx, conversion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockConversionsRepository)(nil).Save), ctx, conversion)
+}
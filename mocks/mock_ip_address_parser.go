@@ -13,6 +13,7 @@ import (
 	net "net"
 	reflect "reflect"
 
+	valueobject "github.com/lroman242/redirector/domain/valueobject"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,10 +42,10 @@ func (m *MockIPAddressParserInterface) EXPECT() *MockIPAddressParserInterfaceMoc
 }
 
 // Parse mocks base method.
-func (m *MockIPAddressParserInterface) Parse(ip net.IP) (string, error) {
+func (m *MockIPAddressParserInterface) Parse(ip net.IP) (*valueobject.GeoLocation, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Parse", ip)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(*valueobject.GeoLocation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
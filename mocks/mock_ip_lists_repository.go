@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/repository/ip_lists_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mocks -destination=mocks/mock_ip_lists_repository.go -source=domain/repository/ip_lists_repository.go IPListsRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	valueobject "github.com/lroman242/redirector/domain/valueobject"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIPListsRepository is a mock of IPListsRepository interface.
+type MockIPListsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPListsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIPListsRepositoryMockRecorder is the mock recorder for MockIPListsRepository.
+type MockIPListsRepositoryMockRecorder struct {
+	mock *MockIPListsRepository
+}
+
+// NewMockIPListsRepository creates a new mock instance.
+func NewMockIPListsRepository(ctrl *gomock.Controller) *MockIPListsRepository {
+	mock := &MockIPListsRepository{ctrl: ctrl}
+	mock.recorder = &MockIPListsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPListsRepository) EXPECT() *MockIPListsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindGlobalIPLists mocks base method.
+func (m *MockIPListsRepository) FindGlobalIPLists(ctx context.Context) valueobject.IPLists {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindGlobalIPLists", ctx)
+	ret0, _ := ret[0].(valueobject.IPLists)
+	return ret0
+}
+
+// FindGlobalIPLists indicates an expected call of FindGlobalIPLists.
+func (mr *MockIPListsRepositoryMockRecorder) FindGlobalIPLists(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGlobalIPLists", reflect.TypeOf((*MockIPListsRepository)(nil).FindGlobalIPLists), ctx)
+}
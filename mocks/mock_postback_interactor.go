@@ -0,0 +1,84 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/interactor/postback_interactor.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mocks -destination=mocks/mock_postback_interactor.go -source=domain/interactor/postback_interactor.go PostbackInteractor
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/lroman242/redirector/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPostbackInteractor is a mock of PostbackInteractor interface.
+type MockPostbackInteractor struct {
+	ctrl     *gomock.Controller
+	recorder *MockPostbackInteractorMockRecorder
+	isgomock struct{}
+}
+
+// MockPostbackInteractorMockRecorder is the mock recorder for MockPostbackInteractor.
+type MockPostbackInteractorMockRecorder struct {
+	mock *MockPostbackInteractor
+}
+
+// NewMockPostbackInteractor creates a new mock instance.
+func NewMockPostbackInteractor(ctrl *gomock.Controller) *MockPostbackInteractor {
+	mock := &MockPostbackInteractor{ctrl: ctrl}
+	mock.recorder = &MockPostbackInteractorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPostbackInteractor) EXPECT() *MockPostbackInteractorMockRecorder {
+	return m.recorder
+}
+
+// Dispatch mocks base method.
+func (m *MockPostbackInteractor) Dispatch(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dispatch", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dispatch indicates an expected call of Dispatch.
+func (mr *MockPostbackInteractorMockRecorder) Dispatch(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dispatch", reflect.TypeOf((*MockPostbackInteractor)(nil).Dispatch), ctx)
+}
+
+// Enqueue mocks base method.
+func (m *MockPostbackInteractor) Enqueue(ctx context.Context, conversion *entity.Conversion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, conversion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockPostbackInteractorMockRecorder) Enqueue(ctx, conversion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockPostbackInteractor)(nil).Enqueue), ctx, conversion)
+}
+
+// Run mocks base method.
+func (m *MockPostbackInteractor) Run(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx, interval)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockPostbackInteractorMockRecorder) Run(ctx, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockPostbackInteractor)(nil).Run), ctx, interval)
+}
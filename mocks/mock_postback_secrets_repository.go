@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/repository/postback_secrets_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mocks -destination=mocks/mock_postback_secrets_repository.go -source=domain/repository/postback_secrets_repository.go PostbackSecretsRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPostbackSecretsRepository is a mock of PostbackSecretsRepository interface.
+type MockPostbackSecretsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPostbackSecretsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPostbackSecretsRepositoryMockRecorder is the mock recorder for MockPostbackSecretsRepository.
+type MockPostbackSecretsRepositoryMockRecorder struct {
+	mock *MockPostbackSecretsRepository
+}
+
+// NewMockPostbackSecretsRepository creates a new mock instance.
+func NewMockPostbackSecretsRepository(ctrl *gomock.Controller) *MockPostbackSecretsRepository {
+	mock := &MockPostbackSecretsRepository{ctrl: ctrl}
+	mock.recorder = &MockPostbackSecretsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPostbackSecretsRepository) EXPECT() *MockPostbackSecretsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindSecret mocks base method.
+func (m *MockPostbackSecretsRepository) FindSecret(ctx context.Context, advertiserID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSecret", ctx, advertiserID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSecret indicates an expected call of FindSecret.
+func (mr *MockPostbackSecretsRepositoryMockRecorder) FindSecret(ctx, advertiserID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSecret", reflect.TypeOf((*MockPostbackSecretsRepository)(nil).FindSecret), ctx, advertiserID)
+}
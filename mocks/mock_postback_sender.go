@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/service/postback_sender.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mocks -destination=mocks/mock_postback_sender.go -source=domain/service/postback_sender.go PostbackSenderInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPostbackSenderInterface is a mock of PostbackSenderInterface interface.
+type MockPostbackSenderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPostbackSenderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockPostbackSenderInterfaceMockRecorder is the mock recorder for MockPostbackSenderInterface.
+type MockPostbackSenderInterfaceMockRecorder struct {
+	mock *MockPostbackSenderInterface
+}
+
+// NewMockPostbackSenderInterface creates a new mock instance.
+func NewMockPostbackSenderInterface(ctrl *gomock.Controller) *MockPostbackSenderInterface {
+	mock := &MockPostbackSenderInterface{ctrl: ctrl}
+	mock.recorder = &MockPostbackSenderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPostbackSenderInterface) EXPECT() *MockPostbackSenderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockPostbackSenderInterface) Send(ctx context.Context, url string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, url)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockPostbackSenderInterfaceMockRecorder) Send(ctx, url any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockPostbackSenderInterface)(nil).Send), ctx, url)
+}
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/repository/postbacks_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mocks -destination=mocks/mock_postbacks_repository.go -source=domain/repository/postbacks_repository.go PostbacksRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/lroman242/redirector/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPostbacksRepository is a mock of PostbacksRepository interface.
+type MockPostbacksRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPostbacksRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPostbacksRepositoryMockRecorder is the mock recorder for MockPostbacksRepository.
+type MockPostbacksRepositoryMockRecorder struct {
+	mock *MockPostbacksRepository
+}
+
+// NewMockPostbacksRepository creates a new mock instance.
+func NewMockPostbacksRepository(ctrl *gomock.Controller) *MockPostbacksRepository {
+	mock := &MockPostbacksRepository{ctrl: ctrl}
+	mock.recorder = &MockPostbacksRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPostbacksRepository) EXPECT() *MockPostbacksRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Dequeue mocks base method.
+func (m *MockPostbacksRepository) Dequeue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*entity.Postback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dequeue", ctx, now, limit, lease)
+	ret0, _ := ret[0].([]*entity.Postback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dequeue indicates an expected call of Dequeue.
+func (mr *MockPostbacksRepositoryMockRecorder) Dequeue(ctx, now, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dequeue", reflect.TypeOf((*MockPostbacksRepository)(nil).Dequeue), ctx, now, limit, lease)
+}
+
+// Enqueue mocks base method.
+func (m *MockPostbacksRepository) Enqueue(ctx context.Context, postback *entity.Postback) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, postback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockPostbacksRepositoryMockRecorder) Enqueue(ctx, postback any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockPostbacksRepository)(nil).Enqueue), ctx, postback)
+}
+
+// FindPostbackURLTemplate mocks base method.
+func (m *MockPostbacksRepository) FindPostbackURLTemplate(ctx context.Context, affiliateID, sourceID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPostbackURLTemplate", ctx, affiliateID, sourceID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPostbackURLTemplate indicates an expected call of FindPostbackURLTemplate.
+func (mr *MockPostbacksRepositoryMockRecorder) FindPostbackURLTemplate(ctx, affiliateID, sourceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPostbackURLTemplate", reflect.TypeOf((*MockPostbacksRepository)(nil).FindPostbackURLTemplate), ctx, affiliateID, sourceID)
+}
+
+// SaveAttempt mocks base method.
+func (m *MockPostbacksRepository) SaveAttempt(ctx context.Context, attempt *entity.PostbackAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAttempt", ctx, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAttempt indicates an expected call of SaveAttempt.
+func (mr *MockPostbacksRepositoryMockRecorder) SaveAttempt(ctx, attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAttempt", reflect.TypeOf((*MockPostbacksRepository)(nil).SaveAttempt), ctx, attempt)
+}
+
+// Update mocks base method.
+func (m *MockPostbacksRepository) Update(ctx context.Context, postback *entity.Postback) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, postback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPostbacksRepositoryMockRecorder) Update(ctx, postback any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPostbacksRepository)(nil).Update), ctx, postback)
+}
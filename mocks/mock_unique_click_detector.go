@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/service/unique_click_detector.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mocks -destination=mocks/mock_unique_click_detector.go -source=domain/service/unique_click_detector.go UniqueClickDetectorInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUniqueClickDetectorInterface is a mock of UniqueClickDetectorInterface interface.
+type MockUniqueClickDetectorInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUniqueClickDetectorInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockUniqueClickDetectorInterfaceMockRecorder is the mock recorder for MockUniqueClickDetectorInterface.
+type MockUniqueClickDetectorInterfaceMockRecorder struct {
+	mock *MockUniqueClickDetectorInterface
+}
+
+// NewMockUniqueClickDetectorInterface creates a new mock instance.
+func NewMockUniqueClickDetectorInterface(ctrl *gomock.Controller) *MockUniqueClickDetectorInterface {
+	mock := &MockUniqueClickDetectorInterface{ctrl: ctrl}
+	mock.recorder = &MockUniqueClickDetectorInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUniqueClickDetectorInterface) EXPECT() *MockUniqueClickDetectorInterfaceMockRecorder {
+	return m.recorder
+}
+
+// IsUnique mocks base method.
+func (m *MockUniqueClickDetectorInterface) IsUnique(ctx context.Context, key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsUnique", ctx, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsUnique indicates an expected call of IsUnique.
+func (mr *MockUniqueClickDetectorInterfaceMockRecorder) IsUnique(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUnique", reflect.TypeOf((*MockUniqueClickDetectorInterface)(nil).IsUnique), ctx, key)
+}
+
+// Remember mocks base method.
+func (m *MockUniqueClickDetectorInterface) Remember(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remember", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remember indicates an expected call of Remember.
+func (mr *MockUniqueClickDetectorInterfaceMockRecorder) Remember(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remember", reflect.TypeOf((*MockUniqueClickDetectorInterface)(nil).Remember), ctx, key)
+}
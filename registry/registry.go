@@ -1,6 +1,7 @@
 package registry
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
@@ -24,23 +25,51 @@ import (
 type Registry interface {
 	// NewService creates a new RedirectInteractor instance
 	NewService() interactor.RedirectInteractor
+	// NewConversionService creates a new ConversionInteractor instance
+	NewConversionService() interactor.ConversionInteractor
+	// NewPostbackService creates a new PostbackInteractor instance
+	NewPostbackService() interactor.PostbackInteractor
 	// NewServer creates and configures the HTTP server
 	NewServer() *server.Server
+	// NewInterstitialTemplates creates interstitial page templates
+	NewInterstitialTemplates() *http.InterstitialTemplates
 	// NewIPAddressParser creates a service for parsing IP addresses
 	NewIPAddressParser() service.IPAddressParserInterface
 	// NewUserAgentParser creates a service for parsing User-Agent strings
 	NewUserAgentParser() service.UserAgentParserInterface
 	// NewTrackingLinksRepository creates a repository for managing tracking links
 	NewTrackingLinksRepository() repository.TrackingLinksRepositoryInterface
+	// NewClicksRepository creates a repository for storing clicks
+	NewClicksRepository() repository.ClicksRepository
+	// NewConversionsRepository creates a repository for storing conversions
+	NewConversionsRepository() repository.ConversionsRepository
+	// NewConversionKeysRepository creates a repository for conversion deduplication keys
+	NewConversionKeysRepository() repository.ConversionKeysRepository
+	// NewPostbacksRepository creates a repository for affiliate postbacks and their delivery queue
+	NewPostbacksRepository() repository.PostbacksRepository
+	// NewClickCapsRepository creates a repository for click caps counters
+	NewClickCapsRepository() repository.ClickCapsRepository
+	// NewIPListsRepository creates a repository for global IP allow/deny lists
+	NewIPListsRepository() repository.IPListsRepository
+	// NewPostbackSender creates a service for sending affiliate postbacks
+	NewPostbackSender() service.PostbackSenderInterface
+	// NewUniqueClickDetector creates a service for detecting duplicate clicks
+	NewUniqueClickDetector() service.UniqueClickDetectorInterface
 	// NewRedisClient creates a new Redis client
 	NewRedisClient() *redis.Client
 	// NewDB initializes the database connection
 	NewDB() *sql.DB
+	// NewClickHouseDB initializes the ClickHouse connection
+	NewClickHouseDB() *sql.DB
 }
 
 // registry implements Registry interface and manages application component initialization
 type registry struct {
 	conf *config.AppConfig
+
+	// db and clickHouseDB are connection pools shared by all repositories, they are opened on first use
+	db           *sql.DB
+	clickHouseDB *sql.DB
 }
 
 // NewRegistry function initialize new Registry instance.
@@ -61,26 +90,121 @@ func (r *registry) NewService() interactor.RedirectInteractor {
 	slog.Info("initializing RedirectInteractor....")
 	clickHandlers := make([]interactor.ClickHandlerInterface, 0)
 
-	//clickHandlers = append(clickHandlers, click_handlers.NewClickHandlerWithMetrics(/* ... create some click handler*/))
+	// clicks are stored for conversion attribution, every registered click is written to ClickHouse
+	if r.conf.ClickHouseConf.IsEnabled() {
+		clickHandlers = append(clickHandlers, interactor.NewStoreClickHandler(r.NewClicksRepository()))
+	}
+
+	options := []interactor.RedirectInteractorOption{
+		interactor.WithMaxRedirectDepth(r.conf.RedirectConf.MaxRedirectDepth),
+		interactor.WithFallbackURL(r.conf.RedirectConf.FallbackURL),
+		interactor.WithErrorFallbackURLs(map[string]string{
+			interactor.NotFoundErrorClass:      r.conf.RedirectConf.NotFoundFallbackURL,
+			interactor.DisabledErrorClass:      r.conf.RedirectConf.DisabledFallbackURL,
+			interactor.BlockedErrorClass:       r.conf.RedirectConf.BlockedFallbackURL,
+			interactor.GeoErrorClass:           r.conf.RedirectConf.GeoFallbackURL,
+			interactor.MisconfiguredErrorClass: r.conf.RedirectConf.MisconfiguredFallbackURL,
+		}),
+		interactor.WithGlobalIPLists(r.NewIPListsRepository()),
+	}
+	if r.conf.RedirectConf.ClickCapsEnabled {
+		options = append(options, interactor.WithClickCaps(r.NewClickCapsRepository()))
+	}
+	if detector := r.NewUniqueClickDetector(); detector != nil {
+		options = append(options, interactor.WithUniqueClickDetector(detector, r.conf.UniqueClicksConf.Key))
+	}
 
 	redirectInteractor := interactor.NewRedirectInteractor(
 		r.NewTrackingLinksRepository(),
 		r.NewIPAddressParser(),
 		r.NewUserAgentParser(),
 		clickHandlers,
+		options...,
 	)
 
 	return serviceImpl.NewRedirectWithMetrics(redirectInteractor)
 }
 
+// NewConversionService func creates conversion interactor (interactor.ConversionInteractor) implementation.
+// Returns nil if ClickHouse is not configured, as conversions can't be attributed without stored clicks.
+func (r *registry) NewConversionService() interactor.ConversionInteractor {
+	if !r.conf.ClickHouseConf.IsEnabled() {
+		return nil
+	}
+
+	slog.Info("initializing ConversionInteractor....")
+	return interactor.NewConversionInteractor(
+		r.NewClicksRepository(),
+		r.NewConversionsRepository(),
+		r.NewConversionKeysRepository(),
+		r.NewPostbackSecretsRepository(),
+		interactor.WithAffiliatePostbacks(r.NewPostbackService()),
+	)
+}
+
+// NewPostbackService func creates postback interactor (interactor.PostbackInteractor) implementation.
+// Queued postbacks are dispatched in background, they are only queued if the dispatch interval is not positive.
+func (r *registry) NewPostbackService() interactor.PostbackInteractor {
+	slog.Info("initializing PostbackInteractor....")
+	postbacks := interactor.NewPostbackInteractor(
+		r.NewPostbacksRepository(),
+		r.NewPostbackSender(),
+		interactor.WithPostbackBatchSize(r.conf.PostbackConf.BatchSize),
+		interactor.WithPostbackMaxAttempts(r.conf.PostbackConf.MaxAttempts),
+		interactor.WithPostbackRetryBackoff(
+			r.conf.PostbackConf.RetryBackoffDuration(),
+			r.conf.PostbackConf.MaxRetryBackoffDuration(),
+		),
+	)
+
+	if r.conf.PostbackConf.DispatchInterval > 0 {
+		go postbacks.Run(context.Background(), r.conf.PostbackConf.DispatchDuration())
+	}
+
+	return postbacks
+}
+
 // NewServer func creates an instance of new Server (HTTP).
 func (r *registry) NewServer() *server.Server {
 	slog.Info("initializing Server....")
-	return server.NewServer(r.conf.HTTPServerConf, http.NewHandler(r.NewService()))
+	cloaker := http.NewReferrerCloaker(r.conf.CloakingConf.Secret, r.conf.CloakingConf.HopTTLDuration())
+
+	return server.NewServer(r.conf.HTTPServerConf, http.NewHandler(
+		r.NewService(),
+		cloaker,
+		r.NewInterstitialTemplates(),
+		r.NewConversionService(),
+		r.conf.HTTPServerConf.TrustForwardedProto,
+	))
+}
+
+// NewInterstitialTemplates creates interstitial page templates loaded from the configured directory.
+// Templates are loaded on start and periodically reloaded in background, nil is returned if the directory is not set.
+func (r *registry) NewInterstitialTemplates() *http.InterstitialTemplates {
+	if r.conf.InterstitialConf.TemplatesDir == "" {
+		return nil
+	}
+
+	slog.Info("initializing interstitial templates...", "dir", r.conf.InterstitialConf.TemplatesDir)
+	templates := http.NewInterstitialTemplates(r.conf.InterstitialConf.TemplatesDir)
+	if err := templates.Load(); err != nil {
+		slog.Error("an error occurred while loading interstitial templates", logger.ErrAttr(err))
+	}
+
+	if r.conf.InterstitialConf.ReloadInterval > 0 {
+		go templates.Watch(context.Background(), r.conf.InterstitialConf.ReloadDuration())
+	}
+
+	return templates
 }
 
 // NewDB func creates mysql session.
+// The connection pool is opened once and shared by all callers.
 func (r *registry) NewDB() *sql.DB {
+	if r.db != nil {
+		return r.db
+	}
+
 	slog.Info("initializing sql connection ...", slog.String("DSN", r.conf.DBConf.DSN()))
 
 	db, err := sql.Open("postgres", fmt.Sprintf(
@@ -111,9 +235,30 @@ func (r *registry) NewDB() *sql.DB {
 		}
 	}()
 
+	r.db = db
+
 	return db
 }
 
+// NewClickHouseDB func creates ClickHouse session.
+// The connection pool is opened once and shared by all callers.
+func (r *registry) NewClickHouseDB() *sql.DB {
+	if r.clickHouseDB != nil {
+		return r.clickHouseDB
+	}
+
+	slog.Info("initializing clickhouse connection ...", "host", r.conf.ClickHouseConf.Host)
+	r.clickHouseDB = storage.NewClickHouseDB(
+		r.conf.ClickHouseConf.Host,
+		r.conf.ClickHouseConf.Port,
+		r.conf.ClickHouseConf.Database,
+		r.conf.ClickHouseConf.Username,
+		r.conf.ClickHouseConf.Password,
+	)
+
+	return r.clickHouseDB
+}
+
 // NewIPAddressParser creates service.IPAddressParserInterface implementation.
 func (r *registry) NewIPAddressParser() service.IPAddressParserInterface {
 	slog.Info("initializing geoip2 db", "path", r.conf.GeoIP2DBPath)
@@ -123,7 +268,27 @@ func (r *registry) NewIPAddressParser() service.IPAddressParserInterface {
 		panic(err)
 	}
 
-	return serviceImpl.NewGeoIP2(db)
+	options := make([]serviceImpl.GeoIP2Option, 0)
+	if r.conf.GeoIP2ASNDBPath != "" {
+		slog.Info("initializing geoip2 asn db", "path", r.conf.GeoIP2ASNDBPath)
+		asnDB, err := geoip2.Open(r.conf.GeoIP2ASNDBPath)
+		if err != nil {
+			panic(err)
+		}
+
+		options = append(options, serviceImpl.WithASNDatabase(asnDB))
+	}
+	if r.conf.GeoIP2ConnectionTypeDBPath != "" {
+		slog.Info("initializing geoip2 connection type db", "path", r.conf.GeoIP2ConnectionTypeDBPath)
+		connectionTypeDB, err := geoip2.Open(r.conf.GeoIP2ConnectionTypeDBPath)
+		if err != nil {
+			panic(err)
+		}
+
+		options = append(options, serviceImpl.WithConnectionTypeDatabase(connectionTypeDB))
+	}
+
+	return serviceImpl.NewGeoIP2(db, options...)
 }
 
 // NewUserAgentParser creates service.UserAgentParserInterface implementation.
@@ -132,12 +297,87 @@ func (r *registry) NewUserAgentParser() service.UserAgentParserInterface {
 	return serviceImpl.NewUserAgentParser()
 }
 
+// NewPostbackSender creates service.PostbackSenderInterface implementation.
+func (r *registry) NewPostbackSender() service.PostbackSenderInterface {
+	slog.Info("initializing postback sender...")
+	return serviceImpl.NewHTTPPostbackSender(r.conf.PostbackConf.TimeoutDuration())
+}
+
+// NewUniqueClickDetector creates service.UniqueClickDetectorInterface implementation.
+// Returns nil if duplicate clicks detection is disabled.
+func (r *registry) NewUniqueClickDetector() service.UniqueClickDetectorInterface {
+	conf := r.conf.UniqueClicksConf
+
+	switch conf.Detector {
+	case config.RedisUniqueClicksDetector:
+		slog.Info("initializing redis unique click detector...")
+		return serviceImpl.NewRedisUniqueClickDetector(r.NewRedisClient(), conf.WindowDuration())
+	case config.MemoryUniqueClicksDetector:
+		slog.Info("initializing in-memory unique click detector...")
+		return serviceImpl.NewBloomUniqueClickDetector(conf.WindowDuration(), conf.BloomCapacity, conf.BloomFalsePositiveRate)
+	default:
+		return nil
+	}
+}
+
 // NewTrackingLinksRepository creates repository.TrackingLinksRepositoryInterface implementation.
 func (r *registry) NewTrackingLinksRepository() repository.TrackingLinksRepositoryInterface {
 	slog.Info("initializing tracking links repository...")
 	return storage.NewSQLStorage(r.NewDB())
 }
 
+// NewClicksRepository creates repository.ClicksRepository implementation.
+func (r *registry) NewClicksRepository() repository.ClicksRepository {
+	slog.Info("initializing clicks repository...")
+	return storage.NewClickHouseSessionStorage(r.NewClickHouseDB())
+}
+
+// NewConversionsRepository creates repository.ConversionsRepository implementation.
+func (r *registry) NewConversionsRepository() repository.ConversionsRepository {
+	slog.Info("initializing conversions repository...")
+	return storage.NewClickhouseConversionsStorage(r.NewClickHouseDB())
+}
+
+// NewConversionKeysRepository creates repository.ConversionKeysRepository implementation.
+func (r *registry) NewConversionKeysRepository() repository.ConversionKeysRepository {
+	slog.Info("initializing conversion keys repository...")
+	return storage.NewConversionKeysStorage(r.NewDB())
+}
+
+// NewPostbackSecretsRepository creates repository.PostbackSecretsRepository implementation.
+func (r *registry) NewPostbackSecretsRepository() repository.PostbackSecretsRepository {
+	slog.Info("initializing postback secrets repository...")
+	return storage.NewPostbackSecretsStorage(r.NewDB())
+}
+
+// NewPostbacksRepository creates repository.PostbacksRepository implementation.
+func (r *registry) NewPostbacksRepository() repository.PostbacksRepository {
+	slog.Info("initializing postbacks repository...")
+	return storage.NewPostbacksStorage(r.NewDB())
+}
+
+// NewClickCapsRepository creates repository.ClickCapsRepository implementation.
+func (r *registry) NewClickCapsRepository() repository.ClickCapsRepository {
+	slog.Info("initializing click caps repository...")
+	return storage.NewRedisClickCapsStorage(r.NewRedisClient())
+}
+
+// NewIPListsRepository creates repository.IPListsRepository implementation.
+// Lists are loaded on start and periodically reloaded in background.
+func (r *registry) NewIPListsRepository() repository.IPListsRepository {
+	slog.Info("initializing ip lists repository...", "file", r.conf.IPListsConf.FilePath)
+	ipLists := storage.NewIPListsStorage(r.NewDB(), r.conf.IPListsConf.FilePath)
+	if err := ipLists.Load(context.Background()); err != nil {
+		slog.Error("an error occurred while loading global IP lists", logger.ErrAttr(err))
+	}
+
+	if r.conf.IPListsConf.ReloadInterval > 0 {
+		go ipLists.Watch(context.Background(), r.conf.IPListsConf.ReloadDuration())
+	}
+
+	return ipLists
+}
+
 // NewLogger creates pointer to *slog.Logger instance (which might be set as default logger).
 func (r *registry) NewLogger() *slog.Logger {
 	slog.Info("initializing logger...")